@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
 	"fido2/internal/platform/db"
-	"fido2/internal/platform/webauthn"
+	"fido2/internal/platform/reaper"
+	"fido2/internal/platform/tenant"
+	"fido2/internal/repository"
 	"fido2/internal/router"
 	"fido2/pkg/utils"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func init() {
@@ -14,7 +20,21 @@ func init() {
 }
 
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	db.Connect()
-	webauthn.NewRPServer()
-	router.InitRouter()
+	tenant.Init()
+
+	// 背景清理未完成的註冊與過期的 Challenge，收到結束訊號後與伺服器一同停止
+	reaperDone := make(chan struct{})
+	go func() {
+		defer close(reaperDone)
+		config := reaper.ConfigFromEnv()
+		config.ReservationTTL = tenant.MaxRegistrationTimeout()
+		reaper.New(repository.NewUserRepository(), config).Run(ctx)
+	}()
+
+	router.InitRouter(ctx)
+	<-reaperDone
 }
\ No newline at end of file
@@ -3,10 +3,10 @@
 -- 用户表
 CREATE TABLE IF NOT EXISTS "user" (
     id VARCHAR(255) PRIMARY KEY,
+    tenant_id VARCHAR(255) NOT NULL DEFAULT 'default',
     user_name VARCHAR(255) NOT NULL,
     display_name VARCHAR(255) NOT NULL,
     challenge VARCHAR(255) NOT NULL,
-    credential VARCHAR(255) NOT NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
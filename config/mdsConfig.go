@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MDSConfig FIDO Metadata Service (MDS3) 設定，未設定 BlobFile 時不啟用驗證器 Metadata 驗證
+type MDSConfig struct {
+	// BlobFile 離線下載的 MDS3 BLOB (JWT) 檔案路徑
+	BlobFile string
+
+	// RootCertFile 驗證 BLOB 簽章憑證鏈的根憑證 (PEM) 檔案路徑
+	RootCertFile string
+
+	// RequireEntry 註冊時是否要求驗證器必須存在於 Metadata 中
+	RequireEntry bool
+}
+
+// LoadMDSConfig 由 MDS_BLOB_FILE、MDS_ROOT_CERT_FILE 與 MDS_REQUIRE_ENTRY 環境變數讀取 MDS 設定
+func LoadMDSConfig() (*MDSConfig, error) {
+	cfg := &MDSConfig{
+		BlobFile:     GetEnv("MDS_BLOB_FILE"),
+		RootCertFile: GetEnv("MDS_ROOT_CERT_FILE"),
+	}
+	if v := GetEnv("MDS_REQUIRE_ENTRY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MDS_REQUIRE_ENTRY %q: %w", v, err)
+		}
+		cfg.RequireEntry = b
+	}
+	if cfg.BlobFile != "" && cfg.RootCertFile == "" {
+		return nil, fmt.Errorf("invalid MDS config: MDS_ROOT_CERT_FILE is required when MDS_BLOB_FILE is set")
+	}
+	return cfg, nil
+}
\ No newline at end of file
@@ -0,0 +1,31 @@
+package config
+
+import "testing"
+
+func TestLoadMDSConfig(t *testing.T) {
+	t.Setenv("MDS_BLOB_FILE", "")
+	cfg, err := LoadMDSConfig()
+	if err != nil || cfg.BlobFile != "" {
+		t.Fatalf("未設定時不應啟用 MDS，got=%+v err=%v", cfg, err)
+	}
+
+	t.Setenv("MDS_BLOB_FILE", "config/mds/blob.jwt")
+	if _, err := LoadMDSConfig(); err == nil {
+		t.Error("缺少 MDS_ROOT_CERT_FILE 時應回傳錯誤")
+	}
+
+	t.Setenv("MDS_ROOT_CERT_FILE", "config/mds/root.pem")
+	t.Setenv("MDS_REQUIRE_ENTRY", "true")
+	cfg, err = LoadMDSConfig()
+	if err != nil {
+		t.Fatalf("LoadMDSConfig 失敗：%v", err)
+	}
+	if cfg.RootCertFile != "config/mds/root.pem" || !cfg.RequireEntry {
+		t.Errorf("MDS 設定錯誤，got=%+v", cfg)
+	}
+
+	t.Setenv("MDS_REQUIRE_ENTRY", "maybe")
+	if _, err := LoadMDSConfig(); err == nil {
+		t.Error("無效的 MDS_REQUIRE_ENTRY 應回傳錯誤")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,323 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RPConfig Relying Party (WebAuthn 伺服器) 的設定
+// 設定檔最上層為預設租戶的設定，可再以環境變數覆寫
+type RPConfig struct {
+	// ID RP ID，通常為不含 scheme 與 port 的網域
+	ID string `yaml:"id"`
+
+	// DisplayName 顯示於驗證器上的 RP 名稱
+	DisplayName string `yaml:"displayName"`
+
+	// Origins 允許的 Origin 清單
+	Origins []string `yaml:"origins"`
+
+	// TopOrigins 允許的 Top Origin 清單 (跨來源 iframe)
+	TopOrigins []string `yaml:"topOrigins"`
+
+	// TopOriginPolicy Top Origin 驗證方式 (ignore / auto / implicit / explicit)
+	TopOriginPolicy string `yaml:"topOriginPolicy"`
+
+	// RegistrationTimeout 註冊流程的逾時時間
+	RegistrationTimeout time.Duration `yaml:"registrationTimeout"`
+
+	// LoginTimeout 登入流程的逾時時間
+	LoginTimeout time.Duration `yaml:"loginTimeout"`
+
+	// EnforceTimeouts 是否由伺服器端強制檢查逾時
+	EnforceTimeouts bool `yaml:"enforceTimeouts"`
+
+	// Algorithms 支援的 COSE 演算法名稱 (ES256 / EdDSA / RS256 ...)
+	Algorithms []string `yaml:"algorithms"`
+
+	// Attestation 預設的 Attestation 傳遞偏好 (none / indirect / direct / enterprise)
+	Attestation string `yaml:"attestation"`
+
+	// UserVerification 預設的使用者驗證需求 (required / preferred / discouraged)
+	UserVerification string `yaml:"userVerification"`
+
+	// Issuer 此伺服器對外的網址，作為 OIDC issuer 與 Token 的 iss，未設定時使用第一個 Origin
+	Issuer string `yaml:"issuer"`
+
+	// AuthenticatorPolicy 註冊時允許的驗證器條件，其他租戶不會沿用預設租戶的設定
+	AuthenticatorPolicy AuthenticatorPolicy `yaml:"authenticatorPolicy"`
+
+	// EnterpriseAttestation 允許回傳企業 Attestation (含裝置序號) 的驗證器，其他租戶不會沿用預設租戶的設定
+	EnterpriseAttestation EnterpriseAttestation `yaml:"enterpriseAttestation"`
+
+	// AppID 舊版 U2F 的 AppID (FIDO AppID，為 https 網址)，設定後登入時要求 appid 擴充、註冊時要求 appidExclude 擴充
+	// 其他租戶不會沿用預設租戶的設定
+	AppID string `yaml:"appid"`
+}
+
+// EnterpriseAttestation 企業 Attestation 設定，只有 AAGUID 在清單中且憑證鏈串接到指定根憑證時才視為企業 Attestation
+type EnterpriseAttestation struct {
+	// AAGUIDs 允許企業 Attestation 的驗證器型號
+	AAGUIDs []string `yaml:"aaguids"`
+
+	// RootCertFiles 企業 Attestation 憑證的根憑證 (PEM) 檔案路徑
+	RootCertFiles []string `yaml:"rootCertFiles"`
+
+	// Required 是否所有註冊都必須是企業 Attestation
+	Required bool `yaml:"required"`
+}
+
+// Enabled 是否已設定企業 Attestation
+func (e *EnterpriseAttestation) Enabled() bool {
+	return len(e.AAGUIDs) > 0
+}
+
+// Validate 檢查企業 Attestation 設定是否完整
+func (e *EnterpriseAttestation) Validate() error {
+	for _, aaguid := range e.AAGUIDs {
+		if _, err := uuid.Parse(aaguid); err != nil {
+			return fmt.Errorf("invalid RP config: enterpriseAttestation AAGUID %q is not a UUID", aaguid)
+		}
+	}
+	if e.Enabled() != (len(e.RootCertFiles) > 0) {
+		return errors.New("invalid RP config: enterpriseAttestation requires both aaguids and rootCertFiles")
+	}
+	if e.Required && !e.Enabled() {
+		return errors.New("invalid RP config: enterpriseAttestation required needs aaguids and rootCertFiles")
+	}
+	return nil
+}
+
+// AuthenticatorPolicy 註冊時的驗證器允許 / 拒絕條件，未設定的條件不做限制
+type AuthenticatorPolicy struct {
+	// AllowedAAGUIDs 只允許這些 AAGUID 的驗證器，空值表示不限制
+	AllowedAAGUIDs []string `yaml:"allowedAAGUIDs"`
+
+	// DeniedAAGUIDs 拒絕這些 AAGUID 的驗證器
+	DeniedAAGUIDs []string `yaml:"deniedAAGUIDs"`
+
+	// MinCertificationLevel 要求 FIDO Metadata 中的最低認證等級 (L1 / L1plus / L2 / L2plus / L3 / L3plus)
+	MinCertificationLevel string `yaml:"minCertificationLevel"`
+
+	// AttestationTypes 允許的 Attestation 類型 (none / self / basic / attca)，空值表示不限制
+	AttestationTypes []string `yaml:"attestationTypes"`
+
+	// BackupEligibility 是否允許可備份 (同步) 的 Credential (required / forbidden)，空值表示不限制
+	BackupEligibility string `yaml:"backupEligibility"`
+
+	// Attachment 允許的驗證器連接方式 (platform / cross-platform)，空值表示不限制
+	Attachment string `yaml:"attachment"`
+
+	// CredentialProtection 註冊時要求的 credProtect 最低等級
+	// (userVerificationOptional / userVerificationOptionalWithCredentialIDList / userVerificationRequired)，空值表示不要求
+	CredentialProtection string `yaml:"credentialProtection"`
+
+	// MinPinLength 要求驗證器回報的最小 PIN 長度，0 表示不要求
+	MinPinLength int `yaml:"minPinLength"`
+}
+
+// CredentialProtectionPolicies credProtect 等級，依寬鬆到嚴格排序 (CTAP 2.1 §12.1)
+var CredentialProtectionPolicies = []string{"userVerificationOptional", "userVerificationOptionalWithCredentialIDList", "userVerificationRequired"}
+
+// maxMinPinLength CTAP 2.1 允許的最大 PIN 長度
+const maxMinPinLength = 63
+
+// CertificationLevels FIDO 認證等級，依高低排序
+var CertificationLevels = []string{"L1", "L1plus", "L2", "L2plus", "L3", "L3plus"}
+
+// Validate 檢查驗證器條件的值是否合法
+func (p *AuthenticatorPolicy) Validate() error {
+	for _, aaguid := range append(append([]string{}, p.AllowedAAGUIDs...), p.DeniedAAGUIDs...) {
+		if _, err := uuid.Parse(aaguid); err != nil {
+			return fmt.Errorf("invalid RP config: authenticatorPolicy AAGUID %q is not a UUID", aaguid)
+		}
+	}
+	if p.MinCertificationLevel != "" && !slices.Contains(CertificationLevels, p.MinCertificationLevel) {
+		return fmt.Errorf("invalid RP config: unknown authenticatorPolicy minCertificationLevel %q", p.MinCertificationLevel)
+	}
+	for _, attestationType := range p.AttestationTypes {
+		switch attestationType {
+		case "none", "self", "basic", "attca":
+		default:
+			return fmt.Errorf("invalid RP config: unknown authenticatorPolicy attestation type %q", attestationType)
+		}
+	}
+	switch p.BackupEligibility {
+	case "", "required", "forbidden":
+	default:
+		return fmt.Errorf("invalid RP config: unknown authenticatorPolicy backupEligibility %q", p.BackupEligibility)
+	}
+	switch p.Attachment {
+	case "", "platform", "cross-platform":
+	default:
+		return fmt.Errorf("invalid RP config: unknown authenticatorPolicy attachment %q", p.Attachment)
+	}
+	if p.CredentialProtection != "" && !slices.Contains(CredentialProtectionPolicies, p.CredentialProtection) {
+		return fmt.Errorf("invalid RP config: unknown authenticatorPolicy credentialProtection %q", p.CredentialProtection)
+	}
+	if p.MinPinLength < 0 || p.MinPinLength > maxMinPinLength {
+		return fmt.Errorf("invalid RP config: authenticatorPolicy minPinLength must be between 0 and %d", maxMinPinLength)
+	}
+	return nil
+}
+
+// defaultRPConfig 未於設定檔或環境變數指定時使用的預設值
+func defaultRPConfig() RPConfig {
+	return RPConfig{
+		DisplayName:         "my RP server",
+		TopOriginPolicy:     "ignore",
+		RegistrationTimeout: 5 * time.Minute,
+		LoginTimeout:        5 * time.Minute,
+		Algorithms:          []string{"ES256", "EdDSA", "RS256"},
+		Attestation:         "none",
+		UserVerification:    "preferred",
+	}
+}
+
+// applyEnv 以環境變數覆寫設定檔內容
+func (c *RPConfig) applyEnv() error {
+	if v := GetEnv("RP_ID"); v != "" {
+		c.ID = v
+	}
+	if v := GetEnv("RP_DISPLAY_NAME"); v != "" {
+		c.DisplayName = v
+	}
+	if v := GetEnv("RP_ORIGINS"); v != "" {
+		c.Origins = splitList(v)
+	}
+	if v := GetEnv("RP_TOP_ORIGINS"); v != "" {
+		c.TopOrigins = splitList(v)
+	}
+	if v := GetEnv("RP_TOP_ORIGIN_POLICY"); v != "" {
+		c.TopOriginPolicy = v
+	}
+	if v := GetEnv("RP_REGISTRATION_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid RP_REGISTRATION_TIMEOUT %q: %w", v, err)
+		}
+		c.RegistrationTimeout = d
+	}
+	if v := GetEnv("RP_LOGIN_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid RP_LOGIN_TIMEOUT %q: %w", v, err)
+		}
+		c.LoginTimeout = d
+	}
+	if v := GetEnv("RP_ENFORCE_TIMEOUTS"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid RP_ENFORCE_TIMEOUTS %q: %w", v, err)
+		}
+		c.EnforceTimeouts = b
+	}
+	if v := GetEnv("RP_ALGORITHMS"); v != "" {
+		c.Algorithms = splitList(v)
+	}
+	if v := GetEnv("RP_ATTESTATION"); v != "" {
+		c.Attestation = v
+	}
+	if v := GetEnv("RP_USER_VERIFICATION"); v != "" {
+		c.UserVerification = v
+	}
+	if v := GetEnv("OIDC_ISSUER"); v != "" {
+		c.Issuer = v
+	}
+	if v := GetEnv("RP_APPID"); v != "" {
+		c.AppID = v
+	}
+	return nil
+}
+
+// Validate 檢查設定是否完整且合法
+func (c *RPConfig) Validate() error {
+	if c.ID == "" {
+		return errors.New("invalid RP config: id (RP_ID) is required")
+	}
+	if strings.Contains(c.ID, "://") || strings.ContainsAny(c.ID, "/:") {
+		return fmt.Errorf("invalid RP config: id %q must be a domain without scheme, port or path", c.ID)
+	}
+	if c.DisplayName == "" {
+		return errors.New("invalid RP config: displayName (RP_DISPLAY_NAME) is required")
+	}
+	if len(c.Origins) == 0 {
+		return errors.New("invalid RP config: at least one origin (RP_ORIGINS) is required")
+	}
+	for _, origin := range append(append([]string{}, c.Origins...), c.TopOrigins...) {
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid RP config: origin %q must be a fully qualified origin", origin)
+		}
+	}
+	if c.Issuer != "" {
+		u, err := url.Parse(c.Issuer)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.RawQuery != "" || u.Fragment != "" || strings.HasSuffix(c.Issuer, "/") {
+			return fmt.Errorf("invalid RP config: issuer %q must be an absolute URL without query, fragment or trailing slash", c.Issuer)
+		}
+	}
+	if c.AppID != "" {
+		u, err := url.Parse(c.AppID)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("invalid RP config: appid %q must be an absolute https URL", c.AppID)
+		}
+	}
+	switch c.TopOriginPolicy {
+	case "ignore", "auto", "implicit", "explicit":
+	default:
+		return fmt.Errorf("invalid RP config: unknown topOriginPolicy %q", c.TopOriginPolicy)
+	}
+	if c.TopOriginPolicy == "explicit" && len(c.TopOrigins) == 0 {
+		return errors.New("invalid RP config: topOriginPolicy explicit requires topOrigins")
+	}
+	if c.RegistrationTimeout <= 0 || c.LoginTimeout <= 0 {
+		return errors.New("invalid RP config: timeouts must be positive")
+	}
+	if len(c.Algorithms) == 0 {
+		return errors.New("invalid RP config: at least one algorithm is required")
+	}
+	switch c.Attestation {
+	case "none", "indirect", "direct", "enterprise":
+	default:
+		return fmt.Errorf("invalid RP config: unknown attestation %q", c.Attestation)
+	}
+	switch c.UserVerification {
+	case "required", "preferred", "discouraged":
+	default:
+		return fmt.Errorf("invalid RP config: unknown userVerification %q", c.UserVerification)
+	}
+	if err := c.AuthenticatorPolicy.Validate(); err != nil {
+		return err
+	}
+	if err := c.EnterpriseAttestation.Validate(); err != nil {
+		return err
+	}
+	if c.Attestation == "enterprise" && !c.EnterpriseAttestation.Enabled() {
+		return errors.New("invalid RP config: attestation enterprise requires enterpriseAttestation")
+	}
+	if c.EnterpriseAttestation.Required && c.Attestation != "enterprise" {
+		return errors.New("invalid RP config: enterpriseAttestation required needs attestation enterprise")
+	}
+	if c.AuthenticatorPolicy.MinCertificationLevel != "" && c.Attestation == "none" {
+		return errors.New("invalid RP config: authenticatorPolicy minCertificationLevel requires attestation other than none")
+	}
+	return nil
+}
+
+// splitList 以逗號分隔並去除空白
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
\ No newline at end of file
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultRPConfigFile 預設的 Relying Party 設定檔路徑
+	DefaultRPConfigFile = "config/webauthn.yaml"
+
+	// DefaultTenantID 預設租戶 ID，Host 與路徑前綴都未符合其他租戶時使用
+	DefaultTenantID = "default"
+)
+
+// TenantConfig 單一租戶 (品牌) 的設定
+// 每個租戶有自己的 RP 設定、CORS 來源與 well-known 檔案
+type TenantConfig struct {
+	RPConfig `yaml:",inline"`
+
+	// TenantID 租戶 ID，會儲存在使用者與 Credential 上以隔離資料
+	TenantID string `yaml:"tenant"`
+
+	// Hosts 對應到此租戶的 Host header (不含 port)
+	Hosts []string `yaml:"hosts"`
+
+	// PathPrefix 對應到此租戶的路徑前綴，例如 /brand-a
+	PathPrefix string `yaml:"pathPrefix"`
+
+	// CORSOrigins 允許跨來源請求的 Origin，未設定時沿用 RP 的 Origins
+	CORSOrigins []string `yaml:"corsOrigins"`
+
+	// AppleAppSiteAssociationFile apple-app-site-association 檔案路徑
+	AppleAppSiteAssociationFile string `yaml:"appleAppSiteAssociation"`
+
+	// AssetLinksFile assetlinks.json 檔案路徑
+	AssetLinksFile string `yaml:"assetLinks"`
+}
+
+// fileConfig 設定檔結構，最上層為預設租戶，tenants 為其他租戶
+type fileConfig struct {
+	RPConfig `yaml:",inline"`
+	Tenants  []yaml.Node `yaml:"tenants"`
+}
+
+// LoadTenantConfigs 讀取所有租戶設定，第一筆固定為預設租戶
+// 設定檔路徑可由 RP_CONFIG_FILE 指定，未指定且預設檔案不存在時只使用環境變數
+// 其他租戶會繼承預設租戶的逾時、演算法與偏好設定，但 RP ID、Origins、Issuer、驗證器條件與企業 Attestation 必須自行設定
+func LoadTenantConfigs() ([]*TenantConfig, error) {
+	file := fileConfig{RPConfig: defaultRPConfig()}
+
+	path, explicit := os.LookupEnv("RP_CONFIG_FILE")
+	if !explicit || path == "" {
+		path = DefaultRPConfigFile
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse RP config file %s: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && !explicit:
+		// 未指定設定檔時允許只使用環境變數
+	default:
+		return nil, fmt.Errorf("failed to read RP config file %s: %w", path, err)
+	}
+
+	if err := file.RPConfig.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := file.RPConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	defaultTenant := &TenantConfig{
+		RPConfig:                    file.RPConfig,
+		TenantID:                    DefaultTenantID,
+		CORSOrigins:                 defaultCORSOrigins(),
+		AppleAppSiteAssociationFile: "apple-app-site-association",
+		AssetLinksFile:              "assetlinks.json",
+	}
+	tenants := []*TenantConfig{defaultTenant}
+
+	seenIDs := map[string]bool{DefaultTenantID: true}
+	seenHosts := map[string]bool{}
+	seenPrefixes := map[string]bool{}
+	for i := range file.Tenants {
+		tenant := &TenantConfig{RPConfig: file.RPConfig}
+		tenant.ID, tenant.Origins, tenant.TopOrigins, tenant.Issuer, tenant.AppID = "", nil, nil, "", ""
+		tenant.AuthenticatorPolicy, tenant.EnterpriseAttestation = AuthenticatorPolicy{}, EnterpriseAttestation{}
+
+		if err := file.Tenants[i].Decode(tenant); err != nil {
+			return nil, fmt.Errorf("failed to parse tenant #%d in %s: %w", i+1, path, err)
+		}
+
+		if tenant.TenantID == "" {
+			return nil, fmt.Errorf("invalid tenant #%d: tenant is required", i+1)
+		}
+		if seenIDs[tenant.TenantID] {
+			return nil, fmt.Errorf("invalid tenant %q: duplicate tenant ID", tenant.TenantID)
+		}
+		seenIDs[tenant.TenantID] = true
+
+		if len(tenant.Hosts) == 0 && tenant.PathPrefix == "" {
+			return nil, fmt.Errorf("invalid tenant %q: hosts or pathPrefix is required", tenant.TenantID)
+		}
+		for j, host := range tenant.Hosts {
+			host = strings.ToLower(host)
+			if seenHosts[host] {
+				return nil, fmt.Errorf("invalid tenant %q: host %q is already used", tenant.TenantID, host)
+			}
+			seenHosts[host] = true
+			tenant.Hosts[j] = host
+		}
+		if tenant.PathPrefix != "" {
+			if !strings.HasPrefix(tenant.PathPrefix, "/") || strings.HasSuffix(tenant.PathPrefix, "/") {
+				return nil, fmt.Errorf("invalid tenant %q: pathPrefix %q must start and not end with /", tenant.TenantID, tenant.PathPrefix)
+			}
+			if seenPrefixes[tenant.PathPrefix] {
+				return nil, fmt.Errorf("invalid tenant %q: pathPrefix %q is already used", tenant.TenantID, tenant.PathPrefix)
+			}
+			seenPrefixes[tenant.PathPrefix] = true
+		}
+
+		if err := tenant.RPConfig.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid tenant %q: %w", tenant.TenantID, err)
+		}
+		if len(tenant.CORSOrigins) == 0 {
+			tenant.CORSOrigins = tenant.Origins
+		}
+
+		tenants = append(tenants, tenant)
+	}
+
+	return tenants, nil
+}
+
+// defaultCORSOrigins 預設租戶的 CORS 來源，沿用 CORS_ALLOWED_ORIGINS 環境變數
+func defaultCORSOrigins() []string {
+	if v := GetEnv("CORS_ALLOWED_ORIGINS"); v != "" {
+		return splitList(v)
+	}
+	return []string{"http://localhost:3000"}
+}
\ No newline at end of file
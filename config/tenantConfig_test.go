@@ -0,0 +1,284 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeRPConfig 建立暫存的 RP 設定檔並以 RP_CONFIG_FILE 指定
+func writeRPConfig(t *testing.T, content string) {
+	path := filepath.Join(t.TempDir(), "webauthn.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("寫入設定檔失敗: %v", err)
+	}
+	t.Setenv("RP_CONFIG_FILE", path)
+}
+
+func TestLoadTenantConfigs_FileWithEnvOverride(t *testing.T) {
+	writeRPConfig(t, `
+id: example.com
+displayName: Example
+origins:
+  - https://example.com
+loginTimeout: 2m
+algorithms: [ES256]
+attestation: direct
+userVerification: required
+`)
+	t.Setenv("RP_DISPLAY_NAME", "Example RP")
+	t.Setenv("RP_ORIGINS", "https://example.com, https://app.example.com")
+	t.Setenv("OIDC_ISSUER", "https://auth.example.com")
+
+	tenants, err := LoadTenantConfigs()
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+	if len(tenants) != 1 || tenants[0].TenantID != DefaultTenantID {
+		t.Fatalf("未設定 tenants 時應只有預設租戶，got=%d", len(tenants))
+	}
+	cfg := tenants[0]
+	if cfg.ID != "example.com" {
+		t.Errorf("ID 錯誤，got=%q", cfg.ID)
+	}
+	if cfg.DisplayName != "Example RP" {
+		t.Errorf("環境變數應覆寫 DisplayName，got=%q", cfg.DisplayName)
+	}
+	if len(cfg.Origins) != 2 || cfg.Origins[1] != "https://app.example.com" {
+		t.Errorf("Origins 錯誤，got=%v", cfg.Origins)
+	}
+	if cfg.LoginTimeout != 2*time.Minute {
+		t.Errorf("LoginTimeout 錯誤，got=%v", cfg.LoginTimeout)
+	}
+	if cfg.RegistrationTimeout != 5*time.Minute {
+		t.Errorf("未設定的欄位應使用預設值，got=%v", cfg.RegistrationTimeout)
+	}
+	if cfg.Attestation != "direct" || cfg.UserVerification != "required" {
+		t.Errorf("偏好設定錯誤，got=%q / %q", cfg.Attestation, cfg.UserVerification)
+	}
+	if cfg.Issuer != "https://auth.example.com" {
+		t.Errorf("環境變數應覆寫 Issuer，got=%q", cfg.Issuer)
+	}
+}
+
+func TestLoadTenantConfigs_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+	}{
+		{
+			name:    "缺少 RP ID",
+			content: "origins: [https://example.com]",
+		},
+		{
+			name:    "RP ID 含 scheme",
+			content: "id: https://example.com\norigins: [https://example.com]",
+		},
+		{
+			name:    "缺少 Origin",
+			content: "id: example.com",
+		},
+		{
+			name:    "Origin 不完整",
+			content: "id: example.com\norigins: [example.com]",
+		},
+		{
+			name:    "未知的 Attestation",
+			content: "id: example.com\norigins: [https://example.com]\nattestation: always",
+		},
+		{
+			name:    "無效的逾時環境變數",
+			content: "id: example.com\norigins: [https://example.com]",
+			env:     map[string]string{"RP_LOGIN_TIMEOUT": "soon"},
+		},
+		{
+			name:    "Issuer 結尾有斜線",
+			content: "id: example.com\norigins: [https://example.com]\nissuer: https://auth.example.com/",
+		},
+		{
+			name:    "AppID 不是 https 網址",
+			content: "id: example.com\norigins: [https://example.com]\nappid: http://example.com/app-id.json",
+		},
+		{
+			name:    "驗證器條件的 AAGUID 格式錯誤",
+			content: "id: example.com\norigins: [https://example.com]\nauthenticatorPolicy:\n  deniedAAGUIDs: [yubikey]",
+		},
+		{
+			name:    "未知的認證等級",
+			content: "id: example.com\norigins: [https://example.com]\nattestation: direct\nauthenticatorPolicy:\n  minCertificationLevel: L4",
+		},
+		{
+			name:    "要求認證等級但不要求 Attestation",
+			content: "id: example.com\norigins: [https://example.com]\nauthenticatorPolicy:\n  minCertificationLevel: L2",
+		},
+		{
+			name:    "未知的 Attestation 類型",
+			content: "id: example.com\norigins: [https://example.com]\nauthenticatorPolicy:\n  attestationTypes: [ecdaa]",
+		},
+		{
+			name:    "未知的連接方式",
+			content: "id: example.com\norigins: [https://example.com]\nauthenticatorPolicy:\n  attachment: usb",
+		},
+		{
+			name:    "未知的 credProtect 等級",
+			content: "id: example.com\norigins: [https://example.com]\nauthenticatorPolicy:\n  credentialProtection: always",
+		},
+		{
+			name:    "minPinLength 超出範圍",
+			content: "id: example.com\norigins: [https://example.com]\nauthenticatorPolicy:\n  minPinLength: 64",
+		},
+		{
+			name:    "未設定企業 Attestation 卻預設 enterprise",
+			content: "id: example.com\norigins: [https://example.com]\nattestation: enterprise",
+		},
+		{
+			name:    "企業 Attestation 缺少根憑證",
+			content: "id: example.com\norigins: [https://example.com]\nenterpriseAttestation:\n  aaguids: [cb69481e-8ff7-4039-93ec-0a2729a154a8]",
+		},
+		{
+			name:    "要求企業 Attestation 但偏好不是 enterprise",
+			content: "id: example.com\norigins: [https://example.com]\nenterpriseAttestation:\n  aaguids: [cb69481e-8ff7-4039-93ec-0a2729a154a8]\n  rootCertFiles: [root.pem]\n  required: true",
+		},
+		{
+			name:    "YAML 格式錯誤",
+			content: "id: [example.com",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			writeRPConfig(t, tc.content)
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+			if _, err := LoadTenantConfigs(); err == nil {
+				t.Errorf("預期錯誤但實際沒有")
+			}
+		})
+	}
+}
+
+func TestLoadTenantConfigs_MissingExplicitFile(t *testing.T) {
+	t.Setenv("RP_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	t.Setenv("RP_ID", "example.com")
+	t.Setenv("RP_ORIGINS", "https://example.com")
+
+	if _, err := LoadTenantConfigs(); err == nil {
+		t.Errorf("指定的設定檔不存在時應回傳錯誤")
+	}
+}
+
+func TestLoadTenantConfigs_Tenants(t *testing.T) {
+	writeRPConfig(t, `
+id: example.com
+origins: [https://example.com]
+loginTimeout: 2m
+attestation: direct
+appid: https://example.com/u2f/app-id.json
+authenticatorPolicy:
+  minCertificationLevel: L2
+  attestationTypes: [basic, attca]
+tenants:
+  - tenant: brand-a
+    hosts: [Login.Brand-A.com]
+    id: brand-a.com
+    origins: [https://login.brand-a.com]
+    appleAppSiteAssociation: config/brand-a/apple-app-site-association
+  - tenant: brand-b
+    pathPrefix: /brand-b
+    id: brand-b.com
+    displayName: Brand B
+    origins: [https://brand-b.com]
+    corsOrigins: [https://www.brand-b.com]
+    authenticatorPolicy:
+      backupEligibility: forbidden
+`)
+
+	tenants, err := LoadTenantConfigs()
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+	if len(tenants) != 3 {
+		t.Fatalf("租戶數量錯誤，got=%d", len(tenants))
+	}
+
+	if tenants[0].AuthenticatorPolicy.MinCertificationLevel != "L2" || len(tenants[0].AuthenticatorPolicy.AttestationTypes) != 2 {
+		t.Errorf("預設租戶的驗證器條件錯誤，got=%+v", tenants[0].AuthenticatorPolicy)
+	}
+
+	brandA := tenants[1]
+	if brandA.TenantID != "brand-a" || brandA.ID != "brand-a.com" {
+		t.Errorf("brand-a 設定錯誤，got=%q / %q", brandA.TenantID, brandA.ID)
+	}
+	if brandA.Hosts[0] != "login.brand-a.com" {
+		t.Errorf("Host 應轉為小寫，got=%q", brandA.Hosts[0])
+	}
+	if brandA.LoginTimeout != 2*time.Minute || brandA.DisplayName != "my RP server" {
+		t.Errorf("未設定的欄位應繼承預設租戶，got=%v / %q", brandA.LoginTimeout, brandA.DisplayName)
+	}
+	if brandA.AuthenticatorPolicy.MinCertificationLevel != "" || len(brandA.AuthenticatorPolicy.AttestationTypes) != 0 {
+		t.Errorf("驗證器條件不應繼承預設租戶，got=%+v", brandA.AuthenticatorPolicy)
+	}
+	if tenants[0].AppID != "https://example.com/u2f/app-id.json" || brandA.AppID != "" {
+		t.Errorf("AppID 只屬於預設租戶，got=%q / %q", tenants[0].AppID, brandA.AppID)
+	}
+	if len(brandA.CORSOrigins) != 1 || brandA.CORSOrigins[0] != "https://login.brand-a.com" {
+		t.Errorf("未設定 corsOrigins 時應沿用 origins，got=%v", brandA.CORSOrigins)
+	}
+
+	brandB := tenants[2]
+	if brandB.PathPrefix != "/brand-b" || brandB.DisplayName != "Brand B" {
+		t.Errorf("brand-b 設定錯誤，got=%q / %q", brandB.PathPrefix, brandB.DisplayName)
+	}
+	if brandB.CORSOrigins[0] != "https://www.brand-b.com" {
+		t.Errorf("corsOrigins 錯誤，got=%v", brandB.CORSOrigins)
+	}
+	if brandB.AuthenticatorPolicy.BackupEligibility != "forbidden" {
+		t.Errorf("backupEligibility 錯誤，got=%q", brandB.AuthenticatorPolicy.BackupEligibility)
+	}
+}
+
+func TestLoadTenantConfigs_InvalidTenants(t *testing.T) {
+	base := "id: example.com\norigins: [https://example.com]\n"
+	tests := []struct {
+		name    string
+		tenants string
+	}{
+		{
+			name:    "缺少租戶 ID",
+			tenants: "tenants:\n  - hosts: [a.com]\n    id: a.com\n    origins: [https://a.com]\n",
+		},
+		{
+			name:    "重複的租戶 ID",
+			tenants: "tenants:\n  - tenant: default\n    hosts: [a.com]\n    id: a.com\n    origins: [https://a.com]\n",
+		},
+		{
+			name:    "缺少 Host 與路徑前綴",
+			tenants: "tenants:\n  - tenant: a\n    id: a.com\n    origins: [https://a.com]\n",
+		},
+		{
+			name:    "未繼承 RP ID",
+			tenants: "tenants:\n  - tenant: a\n    hosts: [a.com]\n    origins: [https://a.com]\n",
+		},
+		{
+			name:    "路徑前綴格式錯誤",
+			tenants: "tenants:\n  - tenant: a\n    pathPrefix: a/\n    id: a.com\n    origins: [https://a.com]\n",
+		},
+		{
+			name: "重複的 Host",
+			tenants: "tenants:\n  - tenant: a\n    hosts: [a.com]\n    id: a.com\n    origins: [https://a.com]\n" +
+				"  - tenant: b\n    hosts: [A.com]\n    id: b.com\n    origins: [https://b.com]\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			writeRPConfig(t, base+tc.tenants)
+			if _, err := LoadTenantConfigs(); err == nil {
+				t.Errorf("預期錯誤但實際沒有")
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"errors"
+	"fido2/internal/dto"
+	"fido2/internal/platform/tenant"
+	"fido2/internal/platform/username"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/internal/repository"
+	"fido2/internal/usecase"
+	"fido2/pkg/utils"
+	"fido2/pkg/utils/common"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AdminController struct {
+	UserUC       usecase.UserUseCase
+	CredentialUC usecase.CredentialUseCase
+	OIDCUC       usecase.OIDCUseCase
+}
+
+func NewAdminController(u usecase.UserUseCase, cr usecase.CredentialUseCase, o usecase.OIDCUseCase) *AdminController {
+	return &AdminController{UserUC: u, CredentialUC: cr, OIDCUC: o}
+}
+
+// FlaggedCredentialsHandler 管理者報表
+// 列出租戶內計數器倒退 (疑似被複製) 而被標記的 Credential，以及依複製政策被標記的帳號
+func (c *AdminController) FlaggedCredentialsHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("FlaggedCredentialsHandler called")
+
+	rp := tenant.FromContext(ctx)
+
+	credentials, err := c.CredentialUC.GetFlaggedCredentials(rp.ID)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get flagged credentials, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	users, err := c.UserUC.GetFlaggedUsers(rp.ID)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get flagged users, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		dto.FlaggedCredentialsResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			Credentials: credentials,
+			Users:       users,
+		},
+	)
+}
+
+// CredentialDeviceHandler 查詢 Credential 所在的裝置
+// 只有以企業 Attestation 註冊的 Credential 才有裝置序號與 Attestation 憑證
+func (c *AdminController) CredentialDeviceHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("CredentialDeviceHandler called")
+
+	rp := tenant.FromContext(ctx)
+
+	credential, err := c.CredentialUC.GetCredentialByID(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get credential, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	if credential == nil || credential.TenantID != rp.ID {
+		ctx.JSON(
+			http.StatusNotFound,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "credential not found",
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		dto.CredentialDeviceResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			CredentialID:           credential.ID,
+			UserID:                 credential.UserID,
+			AAGUID:                 wAuth.FormatAAGUID(credential.AAGUID),
+			AuthenticatorName:      wAuth.AuthenticatorName(credential.AAGUID),
+			AttestationType:        credential.AttestationType,
+			EnterpriseAttestation:  credential.EnterpriseAttestation,
+			DeviceSerialNumber:     credential.DeviceSerialNumber,
+			AttestationCertificate: wAuth.CertificatePEM(credential.AttestationCertificate),
+		},
+	)
+}
+
+// ImportU2FCredentialHandler 匯入舊版 U2F 金鑰
+// 以 U2F 註冊時取得的 key handle 與公鑰建立 fido-u2f Credential，使用者不存在時建立新帳號
+// 租戶需設定 AppID，登入時驗證器才會以 AppID 的 rpIdHash 簽章
+func (c *AdminController) ImportU2FCredentialHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("ImportU2FCredentialHandler called")
+
+	rp := tenant.FromContext(ctx)
+
+	var request dto.ImportU2FCredentialRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to parse request body, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	// 先檢查金鑰格式，避免金鑰無效時仍保留使用者名稱
+	credential, err := wAuth.NewU2FCredentialEntity(rp.ID, "", request.KeyHandle, request.PublicKey, request.SignCount)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "invalid u2f key, error: " + err.Error(),
+				ErrorCode:    common.ErrorCodeInvalidU2FKey,
+			},
+		)
+		return
+	}
+
+	user, err := c.UserUC.GetUserByUsername(rp.ID, request.Username)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	// 已完成註冊的帳號直接附加 Credential，否則與一般註冊相同先保留使用者名稱
+	if user == nil || user.ReservedUntil != nil {
+		user, err = c.UserUC.ReserveUsername(rp.ID, request.Username, request.DisplayName, rp.RegistrationTimeout)
+		if err != nil {
+			switch {
+			case errors.Is(err, username.ErrInvalidUsername):
+				ctx.JSON(
+					http.StatusBadRequest,
+					common.CommonResponse{
+						Status:       "failed",
+						ErrorMessage: "invalid username, error: " + err.Error(),
+						ErrorCode:    common.ErrorCodeInvalidUsername,
+					},
+				)
+			case errors.Is(err, repository.ErrUsernameTaken):
+				ctx.JSON(
+					http.StatusConflict,
+					common.CommonResponse{
+						Status:       "failed",
+						ErrorMessage: "username is being registered, try again later",
+						ErrorCode:    common.ErrorCodeUsernameTaken,
+					},
+				)
+			default:
+				ctx.JSON(
+					http.StatusInternalServerError,
+					common.CommonResponse{
+						Status:       "failed",
+						ErrorMessage: "failed to reserve username, error: " + err.Error(),
+					},
+				)
+			}
+			return
+		}
+	}
+
+	credential.UserID = user.ID
+
+	// Credential 與解除使用者名稱保留在同一個交易中寫入
+	if err = c.UserUC.CompleteRegistration(user, credential); err != nil {
+		if errors.Is(err, repository.ErrCredentialExists) {
+			ctx.JSON(
+				http.StatusConflict,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "credential has already been imported",
+					ErrorCode:    common.ErrorCodeCredentialExists,
+				},
+			)
+			return
+		}
+		if errors.Is(err, repository.ErrReservationLost) {
+			ctx.JSON(
+				http.StatusConflict,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to complete registration, error: " + err.Error(),
+					ErrorCode:    common.ErrorCodeUsernameTaken,
+				},
+			)
+			return
+		}
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to complete registration, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	utils.GetLogger().Infof("Imported U2F credential %s for user %s", credential.ID, user.ID)
+
+	ctx.JSON(
+		http.StatusCreated,
+		dto.ImportU2FCredentialResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			CredentialID: credential.ID,
+			UserID:       user.ID,
+		},
+	)
+}
+
+// RegisterClientHandler 註冊 OIDC Client
+// Client Secret 只會在此回應中出現一次，Public Client 不會產生 Secret
+func (c *AdminController) RegisterClientHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("RegisterClientHandler called")
+
+	rp := tenant.FromContext(ctx)
+
+	var request dto.RegisterClientRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to parse request body, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	client, secret, err := c.OIDCUC.RegisterClient(rp.ID, request.Name, request.RedirectURIs, request.Public)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to register client, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusCreated,
+		dto.RegisterClientResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			Client:       client,
+			ClientSecret: secret,
+		},
+	)
+}
\ No newline at end of file
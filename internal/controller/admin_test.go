@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fido2/internal/dto"
+	"fido2/internal/entity"
+	"fido2/internal/platform/tenant"
+	"fido2/internal/repository"
+	mocks "fido2/internal/usecase"
+	"fido2/pkg/utils/common"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFlaggedCredentialsHandler_Success(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	c := NewAdminController(mockUC, mockCredUC, nil)
+
+	// 只查詢目前租戶的 Credential 與帳號
+	mockCredUC.EXPECT().
+		GetFlaggedCredentials("default").
+		Return([]*entity.Credential{{ID: "Y3JlZGlk", UserID: "1", SignCount: 10, CloneWarning: true}}, nil)
+	mockUC.EXPECT().
+		GetFlaggedUsers("default").
+		Return([]*entity.User{{ID: "1", UserName: "testuser", Flagged: true}}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/admin/credentials/flagged", nil)
+
+	c.FlaggedCredentialsHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.FlaggedCredentialsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Credentials, 1)
+	assert.Equal(t, "1", response.Credentials[0].UserID)
+	assert.True(t, response.Credentials[0].CloneWarning)
+	assert.Len(t, response.Users, 1)
+	assert.True(t, response.Users[0].Flagged)
+}
+
+func TestFlaggedCredentialsHandler_Error(t *testing.T) {
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	c := NewAdminController(nil, mockCredUC, nil)
+
+	mockCredUC.EXPECT().
+		GetFlaggedCredentials("default").
+		Return(nil, errors.New("db error"))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/admin/credentials/flagged", nil)
+
+	c.FlaggedCredentialsHandler(ctx)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+func TestRegisterClientHandler(t *testing.T) {
+	mockOIDCUC := mocks.NewMockOIDCUseCase(t)
+	c := NewAdminController(nil, nil, mockOIDCUC)
+
+	body, _ := json.Marshal(dto.RegisterClientRequest{Name: "Example App", RedirectURIs: []string{"https://app.example.com/callback"}})
+
+	mockOIDCUC.EXPECT().
+		RegisterClient("default", "Example App", []string{"https://app.example.com/callback"}, false).
+		Return(&entity.OIDCClient{ID: "client-1", Name: "Example App", SecretHash: "hash"}, "secret", nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/admin/oidc/clients", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.RegisterClientHandler(ctx)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "hash")
+
+	var response dto.RegisterClientResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "client-1", response.Client.ID)
+	assert.Equal(t, "secret", response.ClientSecret)
+}
+func TestCredentialDeviceHandler_Success(t *testing.T) {
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	c := NewAdminController(nil, mockCredUC, nil)
+
+	mockCredUC.EXPECT().
+		GetCredentialByID("Y3JlZGlk").
+		Return(&entity.Credential{
+			ID:                     "Y3JlZGlk",
+			TenantID:               "default",
+			UserID:                 "1",
+			AttestationType:        "packed",
+			EnterpriseAttestation:  true,
+			DeviceSerialNumber:     "12345678",
+			AttestationCertificate: []byte{0x30, 0x00},
+		}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/admin/credentials/Y3JlZGlk/device", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "Y3JlZGlk"}}
+
+	c.CredentialDeviceHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.CredentialDeviceResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "1", response.UserID)
+	assert.True(t, response.EnterpriseAttestation)
+	assert.Equal(t, "12345678", response.DeviceSerialNumber)
+	assert.Contains(t, response.AttestationCertificate, "BEGIN CERTIFICATE")
+}
+
+// 其他租戶的 Credential 視為不存在
+func TestCredentialDeviceHandler_NotFound(t *testing.T) {
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	c := NewAdminController(nil, mockCredUC, nil)
+
+	mockCredUC.EXPECT().
+		GetCredentialByID("Y3JlZGlk").
+		Return(&entity.Credential{ID: "Y3JlZGlk", TenantID: "brand-a", UserID: "1"}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/admin/credentials/Y3JlZGlk/device", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "Y3JlZGlk"}}
+
+	c.CredentialDeviceHandler(ctx)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestImportU2FCredentialHandler(t *testing.T) {
+	publicKey := newVirtualAuthenticator(t).privateKey.PublicKey
+	raw, _ := publicKey.ECDH()
+	body, _ := json.Marshal(dto.ImportU2FCredentialRequest{
+		Username:  "testuser",
+		KeyHandle: []byte("key-handle"),
+		PublicKey: raw.Bytes(),
+		SignCount: 12,
+	})
+
+	tests := []struct {
+		name     string
+		setup    func(mockUC *mocks.MockUserUseCase, mockCredUC *mocks.MockCredentialUseCase)
+		expected int
+	}{
+		{
+			// 已註冊的帳號直接附加 Credential
+			name: "既有使用者",
+			setup: func(mockUC *mocks.MockUserUseCase, mockCredUC *mocks.MockCredentialUseCase) {
+				user := &entity.User{ID: "1", TenantID: "default", UserName: "testuser"}
+				mockUC.EXPECT().GetUserByUsername("default", "testuser").Return(user, nil)
+				mockUC.EXPECT().
+					CompleteRegistration(user, mock.MatchedBy(func(credential *entity.Credential) bool {
+						return credential.ID == "a2V5LWhhbmRsZQ" && credential.UserID == "1" && credential.AttestationType == "fido-u2f" && credential.SignCount == 12
+					})).
+					Return(nil)
+			},
+			expected: http.StatusCreated,
+		},
+		{
+			name: "新使用者",
+			setup: func(mockUC *mocks.MockUserUseCase, mockCredUC *mocks.MockCredentialUseCase) {
+				user := &entity.User{ID: "2", TenantID: "default", UserName: "testuser"}
+				mockUC.EXPECT().GetUserByUsername("default", "testuser").Return(nil, nil)
+				mockUC.EXPECT().ReserveUsername("default", "testuser", "", tenant.Default().RegistrationTimeout).Return(user, nil)
+				mockUC.EXPECT().CompleteRegistration(user, mock.Anything).Return(nil)
+			},
+			expected: http.StatusCreated,
+		},
+		{
+			name: "重複匯入",
+			setup: func(mockUC *mocks.MockUserUseCase, mockCredUC *mocks.MockCredentialUseCase) {
+				mockUC.EXPECT().GetUserByUsername("default", "testuser").Return(&entity.User{ID: "1", TenantID: "default"}, nil)
+				mockUC.EXPECT().CompleteRegistration(mock.Anything, mock.Anything).Return(repository.ErrCredentialExists)
+			},
+			expected: http.StatusConflict,
+		},
+		{
+			// 保留過期並被其他註冊接手時不寫入 Credential
+			name: "保留已被接手",
+			setup: func(mockUC *mocks.MockUserUseCase, mockCredUC *mocks.MockCredentialUseCase) {
+				user := &entity.User{ID: "2", TenantID: "default", UserName: "testuser"}
+				mockUC.EXPECT().GetUserByUsername("default", "testuser").Return(nil, nil)
+				mockUC.EXPECT().ReserveUsername("default", "testuser", "", tenant.Default().RegistrationTimeout).Return(user, nil)
+				mockUC.EXPECT().CompleteRegistration(user, mock.Anything).Return(repository.ErrReservationLost)
+			},
+			expected: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUC := mocks.NewMockUserUseCase(t)
+			mockCredUC := mocks.NewMockCredentialUseCase(t)
+			c := NewAdminController(mockUC, mockCredUC, nil)
+			tc.setup(mockUC, mockCredUC)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/admin/credentials/u2f", bytes.NewBuffer(body))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			c.ImportU2FCredentialHandler(ctx)
+
+			assert.Equal(t, tc.expected, w.Code, w.Body.String())
+		})
+	}
+}
+
+// 金鑰格式錯誤時不應保留使用者名稱
+func TestImportU2FCredentialHandler_InvalidKey(t *testing.T) {
+	c := NewAdminController(mocks.NewMockUserUseCase(t), mocks.NewMockCredentialUseCase(t), nil)
+
+	body, _ := json.Marshal(dto.ImportU2FCredentialRequest{
+		Username:  "testuser",
+		KeyHandle: []byte("key-handle"),
+		PublicKey: []byte("not-a-key"),
+	})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/admin/credentials/u2f", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.ImportU2FCredentialHandler(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response common.CommonResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, common.ErrorCodeInvalidU2FKey, response.ErrorCode)
+}
\ No newline at end of file
@@ -1,10 +1,15 @@
 package controller
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fido2/internal/dto"
 	"fido2/internal/entity"
+	"fido2/internal/platform/oidc"
+	"fido2/internal/platform/session"
+	"fido2/internal/platform/tenant"
 	wAuth "fido2/internal/platform/webauthn"
 	"fido2/internal/usecase"
 	"fido2/pkg/utils"
@@ -12,17 +17,29 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"maps"
 	"net/http"
+	"time"
 )
 
-var assertionSessionData *webauthn.SessionData
+var (
+	errUserNotFound       = errors.New("user not found")
+	errCredentialNotOwned = errors.New("credential is not registered to this user")
+	errCredentialCloned   = errors.New("signature counter went backwards, authenticator may be cloned")
+)
 
 type AuthController struct {
-	UserUC usecase.UserUseCase
+	UserUC       usecase.UserUseCase
+	CredentialUC usecase.CredentialUseCase
+	TokenUC      usecase.TokenUseCase
+	OIDCUC       usecase.OIDCUseCase
+	Sessions     session.SessionStore
+	ClonePolicy  wAuth.ClonePolicy
 }
 
-func NewAuthController(u usecase.UserUseCase) *AuthController {
-	return &AuthController{UserUC: u}
+func NewAuthController(u usecase.UserUseCase, cr usecase.CredentialUseCase, t usecase.TokenUseCase, o usecase.OIDCUseCase, s session.SessionStore) *AuthController {
+	return &AuthController{UserUC: u, CredentialUC: cr, TokenUC: t, OIDCUC: o, Sessions: s, ClonePolicy: wAuth.GetClonePolicy()}
 }
 
 // StartAssertionHandler Credential Get Options
@@ -30,6 +47,8 @@ func NewAuthController(u usecase.UserUseCase) *AuthController {
 func (c *AuthController) StartAssertionHandler(ctx *gin.Context) {
 	utils.GetLogger().Info("StartAssertionHandler called")
 
+	rp := tenant.FromContext(ctx)
+
 	var request *dto.CredentialGetOptionsRequest
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -43,61 +62,186 @@ func (c *AuthController) StartAssertionHandler(ctx *gin.Context) {
 		return
 	}
 
-	foundUser, err := c.UserUC.GetUserByUsername(request.Username)
+	// 條件式 (autofill) 登入不綁定使用者名稱，於 /assertion/result 時才反查使用者
+	if protocol.CredentialMediationRequirement(request.Mediation) == protocol.MediationConditional {
+		c.startConditionalAssertion(ctx, request.UserVerification)
+		return
+	}
+
+	authenticatorSelection := func(options *protocol.PublicKeyCredentialRequestOptions) {
+		if request.UserVerification != "" {
+			options.UserVerification = protocol.UserVerificationRequirement(request.UserVerification)
+		}
+	}
+
+	var (
+		foundUser   *entity.User
+		options     *protocol.CredentialAssertion
+		sessionData *webauthn.SessionData
+		err         error
+	)
+
+	if request.Username == "" {
+		// 未提供使用者名稱時改走 discoverable credential 登入，allowCredentials 為空
+		extensions, err := assertionExtensions(request.Extensions, nil)
+		if err != nil {
+			invalidExtensionResponse(ctx, err)
+			return
+		}
+
+		options, sessionData, err = rp.WebAuthn.BeginDiscoverableLogin(authenticatorSelection, webauthn.WithAssertionExtensions(extensions))
+	} else {
+		foundUser, err = c.UserUC.GetUserByUsername(rp.ID, request.Username)
+		if err != nil {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to get user by name, error: " + err.Error(),
+				},
+			)
+			return
+		}
+
+		if foundUser == nil {
+			ctx.JSON(
+				http.StatusBadRequest,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "user not found",
+				},
+			)
+			return
+		}
+
+		credentials, err := c.CredentialUC.GetCredentialsByUserID(foundUser.ID)
+		if err != nil {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to get user credentials, error: " + err.Error(),
+				},
+			)
+			return
+		}
+
+		extensions, err := assertionExtensions(request.Extensions, credentials)
+		if err != nil {
+			invalidExtensionResponse(ctx, err)
+			return
+		}
+
+		webauthnUser := wAuth.NewUserWebAuthn(foundUser, credentials)
+
+		loginOptions := []webauthn.LoginOption{authenticatorSelection, webauthn.WithAssertionExtensions(extensions)}
+		if rp.AppID != "" {
+			// 只有匯入的 U2F Credential 需要 appid 擴充，須放在 WithAssertionExtensions 之後才不會被覆寫
+			loginOptions = append(loginOptions, webauthn.WithAppIdExtension(rp.AppID))
+		}
+
+		options, sessionData, err = rp.WebAuthn.BeginLogin(webauthnUser, loginOptions...)
+	}
+
 	if err != nil {
 		ctx.JSON(
 			http.StatusInternalServerError,
 			common.CommonResponse{
 				Status:       "failed",
-				ErrorMessage: "failed to get user by name, error: " + err.Error(),
+				ErrorMessage: "failed to begin login, error: " + err.Error(),
 			},
 		)
 		return
 	}
 
-	utils.GetLogger().Infof("Found user: %s", foundUser.Challenge)
+	utils.GetLogger().Infof("Session data: %+v", sessionData)
 
-	webauthnUser := wAuth.NewUserWebAuthn(foundUser)
+	ceremonyID := uuid.New().String()
+	if err := c.Sessions.Save(ctx.Request.Context(), ceremonyID, sessionData, rp.LoginTimeout); err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to save session data, error: " + err.Error(),
+			},
+		)
+		return
+	}
 
-	authenticatorSelection := func(options *protocol.PublicKeyCredentialRequestOptions) {
-		options.UserVerification = protocol.UserVerificationRequirement(request.UserVerification)
+	// 更新使用者 Challenge 並呼叫 UpdateUser
+	if foundUser != nil {
+		if err = c.UserUC.SetChallenge(foundUser, options.Response.Challenge.String(), rp.LoginTimeout); err != nil {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to update user, error: " + err.Error(),
+				},
+			)
+			return
+		}
+
+		utils.GetLogger().Infof("Updated user challenge: %s", foundUser.Challenge)
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		dto.CredentialGetOptionsResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			PublicKeyCredentialRequestOptions: options.Response,
+			Mediation:                         options.Mediation,
+			CeremonyID:                        ceremonyID,
+		},
+	)
+}
+
+// StartConditionalAssertionHandler Conditional Mediation Credential Get Options
+// 頁面載入時預先產生條件式 (passkey autofill) 登入所需的 Challenge
+func (c *AuthController) StartConditionalAssertionHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("StartConditionalAssertionHandler called")
+
+	c.startConditionalAssertion(ctx, ctx.Query("userVerification"))
+}
+
+// startConditionalAssertion 產生 mediation 為 conditional 的登入選項
+// Challenge 不綁定使用者，並使用較長的 Session 存活時間
+func (c *AuthController) startConditionalAssertion(ctx *gin.Context, userVerification string) {
+	rp := tenant.FromContext(ctx)
+
+	opts := func(options *protocol.PublicKeyCredentialRequestOptions) {
+		if userVerification != "" {
+			options.UserVerification = protocol.UserVerificationRequirement(userVerification)
+		}
+		options.Timeout = int(session.ConditionalTTL.Milliseconds())
 	}
 
-	options, sessionData, err := wAuth.WebAuthn.BeginLogin(webauthnUser, authenticatorSelection)
+	options, sessionData, err := rp.WebAuthn.BeginDiscoverableMediatedLogin(protocol.MediationConditional, opts)
 	if err != nil {
 		ctx.JSON(
 			http.StatusInternalServerError,
 			common.CommonResponse{
 				Status:       "failed",
-				ErrorMessage: "failed to begin login, error: " + err.Error(),
+				ErrorMessage: "failed to begin conditional login, error: " + err.Error(),
 			},
 		)
 		return
 	}
 
-	sessionData.Challenge = base64.RawStdEncoding.EncodeToString([]byte(sessionData.Challenge))
-
-	utils.GetLogger().Infof("Session data: %+v", sessionData)
-
-	assertionSessionData = sessionData
-	// 更新使用者 Challenge 並呼叫 UpdateUser
-	if err = c.UserUC.UpdateUser(
-		foundUser, &entity.User{
-			Challenge: options.Response.Challenge.String(),
-		},
-	); err != nil {
+	ceremonyID := uuid.New().String()
+	if err := c.Sessions.Save(ctx.Request.Context(), ceremonyID, sessionData, session.ConditionalTTL); err != nil {
 		ctx.JSON(
 			http.StatusInternalServerError,
 			common.CommonResponse{
 				Status:       "failed",
-				ErrorMessage: "failed to update user, error: " + err.Error(),
+				ErrorMessage: "failed to save session data, error: " + err.Error(),
 			},
 		)
 		return
 	}
 
-	utils.GetLogger().Infof("Updated user challenge: %s", foundUser.Challenge)
-
 	ctx.JSON(
 		http.StatusOK,
 		dto.CredentialGetOptionsResponse{
@@ -106,6 +250,8 @@ func (c *AuthController) StartAssertionHandler(ctx *gin.Context) {
 				ErrorMessage: "",
 			},
 			PublicKeyCredentialRequestOptions: options.Response,
+			Mediation:                         options.Mediation,
+			CeremonyID:                        ceremonyID,
 		},
 	)
 }
@@ -115,6 +261,8 @@ func (c *AuthController) StartAssertionHandler(ctx *gin.Context) {
 func (c *AuthController) FinishAssertionHandler(ctx *gin.Context) {
 	utils.GetLogger().Info("FinishAssertionHandler called")
 
+	rp := tenant.FromContext(ctx)
+
 	var request *dto.AuthenticatorAssertionResponseRequest
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -130,6 +278,11 @@ func (c *AuthController) FinishAssertionHandler(ctx *gin.Context) {
 
 	utils.GetLogger().Infof("Request: %+v", request)
 
+	sessionData, ok := takeSession(ctx, c.Sessions, request.CeremonyID)
+	if !ok {
+		return
+	}
+
 	// 將請求物件序列化為 JSON 字串紀錄
 	if reqBodyBytes, err := json.Marshal(request); err != nil {
 		utils.GetLogger().Errorf("failed to marshal request: %v", err)
@@ -197,21 +350,19 @@ func (c *AuthController) FinishAssertionHandler(ctx *gin.Context) {
 		return
 	}
 
-	utils.GetLogger().Infof("ClientDataJSON: %+v", clientDataJSON["challenge"].(string))
-
 	challenge, ok := clientDataJSON["challenge"].(string)
-	if !ok {
+	if !ok || challenge == "" {
 		ctx.JSON(
 			http.StatusBadRequest,
 			common.CommonResponse{
 				Status:       "failed",
-				ErrorMessage: "challenge not found",
+				ErrorMessage: "challenge is missing or not a string",
 			},
 		)
 		return
 	}
 
-	if challenge != assertionSessionData.Challenge {
+	if challenge != sessionData.Challenge {
 		ctx.JSON(
 			http.StatusBadRequest,
 			common.CommonResponse{
@@ -222,31 +373,6 @@ func (c *AuthController) FinishAssertionHandler(ctx *gin.Context) {
 		return
 	}
 
-	decodedChallenge, err := base64.RawURLEncoding.DecodeString(challenge)
-	if err != nil {
-		ctx.JSON(
-			http.StatusBadRequest,
-			common.CommonResponse{
-				Status:       "failed",
-				ErrorMessage: "failed to decode challenge, error: " + err.Error(),
-			},
-		)
-		return
-	}
-	challenge = string(decodedChallenge)
-
-	foundUser, err := c.UserUC.GetUserByChallenge(challenge)
-	if err != nil {
-		ctx.JSON(
-			http.StatusInternalServerError,
-			common.CommonResponse{
-				Status:       "failed",
-				ErrorMessage: "failed to get user by challenge, error: " + err.Error(),
-			},
-		)
-		return
-	}
-
 	credentialRawID, err := utils.DecodeCredentialRawID(request.Id)
 	if err != nil {
 		ctx.JSON(
@@ -259,7 +385,76 @@ func (c *AuthController) FinishAssertionHandler(ctx *gin.Context) {
 		return
 	}
 
-	utils.GetLogger().Infof("Found user: %s", foundUser.TableName())
+	// 有指定使用者的登入：先消耗 Options 階段保存的 Challenge，驗證器有回傳 userHandle 時需屬於同一個使用者
+	var webauthnUser *wAuth.UserWebAuthn
+	if len(sessionData.UserID) > 0 {
+		challengeUser, ok := c.consumeChallenge(ctx, rp.ID, challenge)
+		if !ok {
+			return
+		}
+
+		foundUser := challengeUser
+		if len(authenticatorUserHandle) > 0 {
+			foundUser, err = c.UserUC.GetUserByUserHandle(rp.ID, authenticatorUserHandle)
+			if foundUser != nil && foundUser.ID != challengeUser.ID {
+				foundUser = nil
+			}
+		}
+		if err != nil {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to get user, error: " + err.Error(),
+				},
+			)
+			return
+		}
+
+		if foundUser == nil {
+			ctx.JSON(
+				http.StatusBadRequest,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "user not found",
+				},
+			)
+			return
+		}
+
+		webauthnUser, err = c.loadWebAuthnUser(foundUser, credentialRawID)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errCredentialNotOwned) {
+				status = http.StatusBadRequest
+			}
+			ctx.JSON(
+				status,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to load user credentials, error: " + err.Error(),
+				},
+			)
+			return
+		}
+
+		if len(authenticatorUserHandle) > 0 && webauthnUser.MatchUserHandle(authenticatorUserHandle) && webauthnUser.UsesLegacyHandle() {
+			// 既有 Passkey 以使用者名稱作為 user handle 註冊，Session 需改用舊版別名比對
+			if !bytes.Equal(sessionData.UserID, foundUser.UserHandle) {
+				ctx.JSON(
+					http.StatusBadRequest,
+					common.CommonResponse{
+						Status:       "failed",
+						ErrorMessage: "session does not belong to this user",
+					},
+				)
+				return
+			}
+			sessionData.UserID = webauthnUser.WebAuthnID()
+		}
+
+		utils.GetLogger().Infof("Found user: %s", foundUser.ID)
+	}
 
 	car := protocol.CredentialAssertionResponse{
 		PublicKeyCredential: protocol.PublicKeyCredential{
@@ -268,7 +463,7 @@ func (c *AuthController) FinishAssertionHandler(ctx *gin.Context) {
 				Type: request.Type,
 			},
 			RawID:                  protocol.URLEncodedBase64(credentialRawID),
-			ClientExtensionResults: request.GetClientExtensionResults,
+			ClientExtensionResults: request.GetClientExtensionResults.ToProtocol(),
 		},
 		AssertionResponse: protocol.AuthenticatorAssertionResponse{
 			AuthenticatorResponse: protocol.AuthenticatorResponse{
@@ -291,11 +486,41 @@ func (c *AuthController) FinishAssertionHandler(ctx *gin.Context) {
 		return
 	}
 
-	utils.GetLogger().Infof("Parsed PublicKeyCredential: %+v", pca)
+	// Secure Payment Confirmation 的 clientDataJSON 需與發出的交易相符
+	transaction := wAuth.PaymentTransactionFromSession(sessionData.Extensions)
+	if transaction != nil && !verifyPaymentClientData(ctx, rp, transaction, authenticatorClientDataJSON, pca) {
+		return
+	}
 
-	webauthnUser := wAuth.NewUserWebAuthn(foundUser)
+	utils.GetLogger().Infof("Parsed PublicKeyCredential: %+v", pca)
 
-	if _, err := wAuth.WebAuthn.ValidateLogin(webauthnUser, *assertionSessionData, pca); err != nil {
+	var credential *webauthn.Credential
+	if webauthnUser != nil {
+		credential, err = rp.WebAuthn.ValidateLogin(webauthnUser, *sessionData, pca)
+	} else {
+		// 無使用者名稱 (discoverable credential) 登入：依驗證器回傳的 userHandle 反查使用者
+		credential, err = rp.WebAuthn.ValidateDiscoverableLogin(
+			func(rawID, userHandle []byte) (webauthn.User, error) {
+				foundUser, err := c.UserUC.GetUserByUserHandle(rp.ID, userHandle)
+				if err != nil {
+					return nil, err
+				}
+				if foundUser == nil {
+					return nil, errUserNotFound
+				}
+
+				webauthnUser, err = c.loadWebAuthnUser(foundUser, rawID)
+				if err != nil {
+					return nil, err
+				}
+				webauthnUser.MatchUserHandle(userHandle)
+				return webauthnUser, nil
+			},
+			*sessionData,
+			pca,
+		)
+	}
+	if err != nil {
 		ctx.JSON(
 			http.StatusInternalServerError,
 			common.CommonResponse{
@@ -306,13 +531,136 @@ func (c *AuthController) FinishAssertionHandler(ctx *gin.Context) {
 		return
 	}
 
-	utils.GetLogger().Infof("User %s logged in successfully", foundUser.TableName())
+	// 計數器倒退，依設定的策略處理疑似被複製的驗證器
+	credentialID := base64.RawURLEncoding.EncodeToString(credential.ID)
+	if credential.Authenticator.CloneWarning && !handleCloneWarning(ctx, c.ClonePolicy, c.UserUC, c.CredentialUC, webauthnUser.User, credentialID) {
+		return
+	}
 
-	ctx.JSON(
-		http.StatusOK,
-		common.CommonResponse{
-			Status:       "ok",
-			ErrorMessage: "",
-		},
-	)
+	// 登入被接受後才寫回計數器、Backup 狀態、擴充支援與最後使用時間
+	update := wAuth.CredentialUsageUpdate(credential, time.Now())
+	maps.Copy(update, assertionExtensionUpdate(request.GetClientExtensionResults))
+	if err := c.CredentialUC.UpdateCredential(&entity.Credential{ID: credentialID}, update); err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to update credential, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	if transaction != nil {
+		utils.GetLogger().Infof("User %s confirmed payment of %s %s with credential ID: %s", webauthnUser.ID, transaction.Total.Value, transaction.Total.Currency, request.Id)
+		paymentResultResponse(ctx, webauthnUser.User, credentialID, credential, transaction)
+		return
+	}
+
+	utils.GetLogger().Infof("User %s logged in successfully with credential ID: %s", webauthnUser.ID, request.Id)
+
+	// 由 OIDC /authorize 頁面發起的登入，改為產生 Authorization Code 並導回 Client
+	if request.AuthorizationRequestID != "" {
+		redirectURI, err := c.OIDCUC.CompleteAuthorization(rp.ID, request.AuthorizationRequestID, webauthnUser.User, credentialID, credential.Flags)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, oidc.ErrAuthorizationRequestNotFound) {
+				status = http.StatusBadRequest
+			}
+			ctx.JSON(
+				status,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to complete authorization, error: " + err.Error(),
+				},
+			)
+			return
+		}
+
+		ctx.JSON(
+			http.StatusOK,
+			dto.AuthorizationResultResponse{
+				CommonResponse: common.CommonResponse{
+					Status:       "ok",
+					ErrorMessage: "",
+				},
+				RedirectURI: redirectURI,
+			},
+		)
+		return
+	}
+
+	// 簽發 Access Token 與 Refresh Token
+	pair, err := c.TokenUC.IssueTokens(rp.Issuer, webauthnUser.User, credentialID, credential.Flags.UserVerified)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to issue tokens, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newTokenResponse(pair))
+}
+
+// loadWebAuthnUser 載入使用者的 Credential 並包裝為 WebAuthn User
+// 透過 Credential ID 索引查詢，確認登入使用的 Credential 屬於該使用者
+func (c *AuthController) loadWebAuthnUser(user *entity.User, credentialID []byte) (*wAuth.UserWebAuthn, error) {
+	storedCredential, err := c.CredentialUC.GetCredentialByID(base64.RawURLEncoding.EncodeToString(credentialID))
+	if err != nil {
+		return nil, err
+	}
+	if storedCredential == nil || storedCredential.UserID != user.ID {
+		return nil, errCredentialNotOwned
+	}
+
+	credentials, err := c.CredentialUC.GetCredentialsByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return wAuth.NewUserWebAuthn(user, credentials), nil
+}
+
+// handleCloneWarning 依設定的策略處理計數器倒退 (疑似被複製) 的驗證器
+// 拒絕登入時只記錄複製警告，不寫回計數器與最後使用時間；拒絕登入或標記使用者失敗時會直接寫入回應並回傳 false
+func handleCloneWarning(ctx *gin.Context, policy wAuth.ClonePolicy, userUC usecase.UserUseCase, credentialUC usecase.CredentialUseCase, user *entity.User, credentialID string) bool {
+	utils.GetLogger().Warnf("Possible cloned authenticator for user %s, credential ID: %s", user.ID, credentialID)
+
+	switch policy {
+	case wAuth.ClonePolicyFlag:
+		if err := userUC.UpdateUser(user, map[string]interface{}{"flagged": true}); err != nil {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to flag user, error: " + err.Error(),
+				},
+			)
+			return false
+		}
+	case wAuth.ClonePolicyReject:
+		if err := credentialUC.UpdateCredential(&entity.Credential{ID: credentialID}, map[string]interface{}{"clone_warning": true}); err != nil {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to update credential, error: " + err.Error(),
+				},
+			)
+			return false
+		}
+		ctx.JSON(
+			http.StatusUnauthorized,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "credential rejected, error: " + errCredentialCloned.Error(),
+			},
+		)
+		return false
+	}
+	return true
 }
\ No newline at end of file
@@ -2,25 +2,37 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fido2/config"
 	"fido2/internal/dto"
 	"fido2/internal/entity"
+	"fido2/internal/platform/session"
+	"fido2/internal/platform/tenant"
+	"fido2/internal/platform/token"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/internal/repository"
 	mocks "fido2/internal/usecase"
+	"fido2/pkg/utils/common"
 	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestStartAssertionHandler_Success(t *testing.T) {
 	// Arrange
 	mockUC := mocks.NewMockUserUseCase(t)
-	c := NewAuthController(mockUC)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
 
 	// 建立 input
 	reqBody := dto.CredentialGetOptionsRequest{
@@ -31,7 +43,7 @@ func TestStartAssertionHandler_Success(t *testing.T) {
 
 	// 模擬 usecase 行為
 	mockUC.EXPECT().
-		GetUserByUsername("testuser").
+		GetUserByUsername("default", "testuser").
 		Return(&entity.User{
 			ID:          "1",
 			UserName:    "testuser",
@@ -39,8 +51,14 @@ func TestStartAssertionHandler_Success(t *testing.T) {
 			Challenge:   "",
 		}, nil)
 
+	mockCredUC.EXPECT().
+		GetCredentialsByUserID("1").
+		Return([]*entity.Credential{
+			{ID: "Y3JlZGlk", UserID: "1"},
+		}, nil)
+
 	mockUC.EXPECT().
-		UpdateUser(mock.Anything).
+		SetChallenge(mock.Anything, mock.AnythingOfType("string"), tenant.Default().LoginTimeout).
 		Return(nil)
 
 	// gin context
@@ -59,7 +77,9 @@ func TestStartAssertionHandler_Success(t *testing.T) {
 
 func TestStartAssertionHandler_UserNotFound(t *testing.T) {
 	mockUC := mocks.NewMockUserUseCase(t)
-	c := NewAuthController(mockUC)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
 
 	reqBody := dto.CredentialGetOptionsRequest{
 		Username:         "no_such_user",
@@ -68,7 +88,7 @@ func TestStartAssertionHandler_UserNotFound(t *testing.T) {
 	body, _ := json.Marshal(reqBody)
 
 	mockUC.EXPECT().
-		GetUserByUsername("no_such_user").
+		GetUserByUsername("default", "no_such_user").
 		Return(nil, errors.New("not found"))
 
 	w := httptest.NewRecorder()
@@ -81,46 +101,111 @@ func TestStartAssertionHandler_UserNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
-func TestFinishAssertionHandler_Success(t *testing.T) {
+func TestStartAssertionHandler_Tenant(t *testing.T) {
 	mockUC := mocks.NewMockUserUseCase(t)
-	c := NewAuthController(mockUC)
-
-	// 設定全域 assertionSessionData（模擬 Challenge）
-	assertionSessionData = &webauthn.SessionData{
-		Challenge: "test-challenge",
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	// 建立另一個租戶，使用者查詢與 RP ID 都應以該租戶為準
+	brandConfig := &config.TenantConfig{
+		RPConfig: config.RPConfig{
+			ID:                  "brand-a.com",
+			DisplayName:         "Brand A",
+			Origins:             []string{"https://brand-a.com"},
+			TopOriginPolicy:     "ignore",
+			RegistrationTimeout: time.Minute,
+			LoginTimeout:        time.Minute,
+			Algorithms:          []string{"ES256"},
+			Attestation:         "none",
+			UserVerification:    "preferred",
+		},
+		TenantID: "brand-a",
 	}
+	brands, err := tenant.New([]*config.TenantConfig{brandConfig}, nil)
+	assert.NoError(t, err)
 
-	// clientDataJSON payload 構造
-	clientData := map[string]interface{}{
-		"challenge": "test-challenge",
+	body, _ := json.Marshal(dto.CredentialGetOptionsRequest{Username: "testuser"})
+
+	mockUC.EXPECT().
+		GetUserByUsername("brand-a", "testuser").
+		Return(nil, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/assertion/options", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	tenant.Set(ctx, brands[0])
+
+	c.StartAssertionHandler(ctx)
+
+	// 其他租戶的同名使用者不應被找到
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestFinishAssertionHandler_Success(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	user := &entity.User{
+		ID:          "1",
+		UserHandle:  []byte("user-handle"),
+		UserName:    "testuser",
+		DisplayName: "Test User",
 	}
-	clientDataJSON, _ := json.Marshal(clientData)
-	encodedClientData := base64.RawURLEncoding.EncodeToString(clientDataJSON)
+	authenticator := newVirtualAuthenticator(t)
+	storedCredential := authenticator.storedCredential(t, user.ID)
+
+	// 以 Ceremony ID 存入 BeginLogin 產生的 SessionData
+	_, sessionData, err := tenant.Default().WebAuthn.BeginLogin(wAuth.NewUserWebAuthn(user, []*entity.Credential{storedCredential}))
+	assert.NoError(t, err)
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", sessionData, time.Minute)
 
 	// request input
 	req := dto.AuthenticatorAssertionResponseRequest{
-		Id: "credid",
-		Response: dto.AuthenticatorAssertionResponse{
-			ClientDataJSON:    encodedClientData,
-			AuthenticatorData: base64.RawURLEncoding.EncodeToString([]byte("data")),
-			Signature:         base64.RawURLEncoding.EncodeToString([]byte("sig")),
-			UserHandle:        base64.RawURLEncoding.EncodeToString([]byte("user-handle")),
-		},
-		GetClientExtensionResults: map[string]interface{}{},
+		CeremonyID:                "test-ceremony",
+		Id:                        authenticator.id(),
+		Response:                  authenticator.assertion(t, sessionData.Challenge, user.UserHandle),
+		GetClientExtensionResults: dto.ClientExtensionResults{},
 		Type:                      "public-key",
 	}
 	body, _ := json.Marshal(req)
 
 	// mock UserUseCase
 	mockUC.EXPECT().
-		GetUserByChallenge("test-challenge").
-		Return(&entity.User{
-			ID:          "1",
-			UserName:    "testuser",
-			DisplayName: "Test User",
-		}, nil)
+		ConsumeChallenge("default", sessionData.Challenge).
+		Return(user, nil)
 
-	// 這邊其實不需要 UpdateUser, 因為 handler 沒有呼叫，但如果有要 mock
+	mockUC.EXPECT().
+		GetUserByUserHandle("default", user.UserHandle).
+		Return(user, nil)
+
+	// 模擬透過 Credential ID 找到屬於該使用者的 Credential
+	mockCredUC.EXPECT().
+		GetCredentialByID(authenticator.id()).
+		Return(storedCredential, nil)
+
+	mockCredUC.EXPECT().
+		GetCredentialsByUserID("1").
+		Return([]*entity.Credential{storedCredential}, nil)
+
+	// 登入成功後寫回新的計數器與最後使用時間
+	mockCredUC.EXPECT().
+		UpdateCredential(&entity.Credential{ID: authenticator.id()}, mock.Anything).
+		Run(func(_ *entity.Credential, updateData interface{}) {
+			fields := updateData.(map[string]interface{})
+			assert.Equal(t, uint32(1), fields["sign_count"])
+			assert.Equal(t, false, fields["clone_warning"])
+			assert.NotNil(t, fields["last_used_at"])
+		}).
+		Return(nil)
+
+	// 登入成功後簽發 Token，UV 旗標決定 ACR
+	mockTokenUC.EXPECT().
+		IssueTokens("http://localhost:3000", user, authenticator.id(), true).
+		Return(&token.Pair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 15 * time.Minute}, nil)
 
 	// gin context
 	w := httptest.NewRecorder()
@@ -133,15 +218,272 @@ func TestFinishAssertionHandler_Success(t *testing.T) {
 
 	// Assert
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.TokenResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "access", response.AccessToken)
+	assert.Equal(t, "Bearer", response.TokenType)
+	assert.Equal(t, 900, response.ExpiresIn)
+	assert.Equal(t, "refresh", response.RefreshToken)
+}
+
+func TestStartAssertionHandler_Usernameless(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	// 未提供使用者名稱，不應查詢任何使用者
+	body, _ := json.Marshal(dto.CredentialGetOptionsRequest{
+		UserVerification: "required",
+	})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/assertion/options", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.StartAssertionHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.CredentialGetOptionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.AllowedCredentials)
+	assert.NotEmpty(t, response.CeremonyID)
+
+	// Session 未綁定使用者，結果需以 userHandle 反查
+	sessionData, err := c.Sessions.Get(context.Background(), response.CeremonyID)
+	assert.NoError(t, err)
+	assert.Empty(t, sessionData.UserID)
+}
+
+func TestStartAssertionHandler_Conditional(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	// 條件式登入忽略使用者名稱，不應查詢任何使用者
+	body, _ := json.Marshal(dto.CredentialGetOptionsRequest{
+		Username:  "testuser",
+		Mediation: "conditional",
+	})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/assertion/options", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.StartAssertionHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.CredentialGetOptionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, protocol.MediationConditional, response.Mediation)
+	assert.Empty(t, response.AllowedCredentials)
+	assert.Equal(t, int(session.ConditionalTTL.Milliseconds()), response.Timeout)
+	assert.NotEmpty(t, response.CeremonyID)
+
+	sessionData, err := c.Sessions.Get(context.Background(), response.CeremonyID)
+	assert.NoError(t, err)
+	assert.Empty(t, sessionData.UserID)
+}
+
+func TestStartConditionalAssertionHandler_PageLoad(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/assertion/options/conditional?userVerification=preferred", nil)
+
+	c.StartConditionalAssertionHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.CredentialGetOptionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, protocol.MediationConditional, response.Mediation)
+	assert.Equal(t, protocol.VerificationPreferred, response.UserVerification)
+	assert.NotEmpty(t, response.CeremonyID)
+}
+
+func TestFinishAssertionHandler_Usernameless(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	user := &entity.User{
+		ID:          "1",
+		UserHandle:  []byte("user-handle"),
+		UserName:    "testuser",
+		DisplayName: "Test User",
+	}
+	authenticator := newVirtualAuthenticator(t)
+	storedCredential := authenticator.storedCredential(t, user.ID)
+
+	_, sessionData, err := tenant.Default().WebAuthn.BeginDiscoverableLogin()
+	assert.NoError(t, err)
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", sessionData, time.Minute)
+
+	req := dto.AuthenticatorAssertionResponseRequest{
+		CeremonyID:                "test-ceremony",
+		Id:                        authenticator.id(),
+		Response:                  authenticator.assertion(t, sessionData.Challenge, user.UserHandle),
+		GetClientExtensionResults: dto.ClientExtensionResults{},
+		Type:                      "public-key",
+	}
+	body, _ := json.Marshal(req)
+
+	// 由驗證器回傳的 userHandle 反查使用者
+	mockUC.EXPECT().
+		GetUserByUserHandle("default", user.UserHandle).
+		Return(user, nil)
+
+	mockCredUC.EXPECT().
+		GetCredentialByID(authenticator.id()).
+		Return(storedCredential, nil)
+
+	mockCredUC.EXPECT().
+		GetCredentialsByUserID("1").
+		Return([]*entity.Credential{storedCredential}, nil)
+
+	mockCredUC.EXPECT().
+		UpdateCredential(&entity.Credential{ID: authenticator.id()}, mock.Anything).
+		Return(nil)
+
+	mockTokenUC.EXPECT().
+		IssueTokens(mock.Anything, user, authenticator.id(), true).
+		Return(&token.Pair{AccessToken: "access", RefreshToken: "refresh"}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/assertion/result", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.FinishAssertionHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// newClonedAssertionRequest 建立計數器倒退 (小於已儲存值) 的登入請求
+func newClonedAssertionRequest(t *testing.T, c *AuthController, user *entity.User) ([]byte, *virtualAuthenticator, *entity.Credential) {
+	authenticator := newVirtualAuthenticator(t)
+	storedCredential := authenticator.storedCredential(t, user.ID)
+	storedCredential.SignCount = 10
+
+	_, sessionData, err := tenant.Default().WebAuthn.BeginLogin(wAuth.NewUserWebAuthn(user, []*entity.Credential{storedCredential}))
+	assert.NoError(t, err)
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", sessionData, time.Minute)
+
+	body, _ := json.Marshal(dto.AuthenticatorAssertionResponseRequest{
+		CeremonyID:                "test-ceremony",
+		Id:                        authenticator.id(),
+		Response:                  authenticator.assertion(t, sessionData.Challenge, user.UserHandle),
+		GetClientExtensionResults: dto.ClientExtensionResults{},
+		Type:                      "public-key",
+	})
+	return body, authenticator, storedCredential
+}
+
+func TestFinishAssertionHandler_ClonePolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     wAuth.ClonePolicy
+		wantStatus int
+		wantFlag   bool
+	}{
+		{name: "Log", policy: wAuth.ClonePolicyLog, wantStatus: http.StatusOK},
+		{name: "Flag", policy: wAuth.ClonePolicyFlag, wantStatus: http.StatusOK, wantFlag: true},
+		{name: "Reject", policy: wAuth.ClonePolicyReject, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := mocks.NewMockUserUseCase(t)
+			mockCredUC := mocks.NewMockCredentialUseCase(t)
+			mockTokenUC := mocks.NewMockTokenUseCase(t)
+			c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+			c.ClonePolicy = tt.policy
+
+			user := &entity.User{
+				ID:          "1",
+				UserHandle:  []byte("user-handle"),
+				UserName:    "testuser",
+				DisplayName: "Test User",
+			}
+			body, authenticator, storedCredential := newClonedAssertionRequest(t, c, user)
+
+			mockUC.EXPECT().
+				ConsumeChallenge("default", mock.AnythingOfType("string")).
+				Return(user, nil)
+
+			mockUC.EXPECT().
+				GetUserByUserHandle("default", user.UserHandle).
+				Return(user, nil)
+
+			mockCredUC.EXPECT().
+				GetCredentialByID(authenticator.id()).
+				Return(storedCredential, nil)
+
+			mockCredUC.EXPECT().
+				GetCredentialsByUserID("1").
+				Return([]*entity.Credential{storedCredential}, nil)
+
+			if tt.wantStatus == http.StatusOK {
+				// 接受登入時保留複製警告且不覆寫原本的計數器
+				mockCredUC.EXPECT().
+					UpdateCredential(&entity.Credential{ID: authenticator.id()}, mock.Anything).
+					Run(func(_ *entity.Credential, updateData interface{}) {
+						fields := updateData.(map[string]interface{})
+						assert.Equal(t, uint32(10), fields["sign_count"])
+						assert.Equal(t, true, fields["clone_warning"])
+					}).
+					Return(nil)
+			} else {
+				// 拒絕登入時只記錄複製警告，不寫回最後使用時間
+				mockCredUC.EXPECT().
+					UpdateCredential(&entity.Credential{ID: authenticator.id()}, map[string]interface{}{"clone_warning": true}).
+					Return(nil)
+			}
+
+			if tt.wantFlag {
+				mockUC.EXPECT().
+					UpdateUser(user, map[string]interface{}{"flagged": true}).
+					Return(nil)
+			}
+			if tt.wantStatus == http.StatusOK {
+				mockTokenUC.EXPECT().
+					IssueTokens(mock.Anything, user, authenticator.id(), true).
+					Return(&token.Pair{AccessToken: "access", RefreshToken: "refresh"}, nil)
+			}
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/assertion/result", bytes.NewBuffer(body))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			c.FinishAssertionHandler(ctx)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
 }
 
 func TestFinishAssertionHandler_ChallengeMismatch(t *testing.T) {
 	mockUC := mocks.NewMockUserUseCase(t)
-	c := NewAuthController(mockUC)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
 
-	assertionSessionData = &webauthn.SessionData{
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", &webauthn.SessionData{
 		Challenge: "correct-challenge",
-	}
+	}, time.Minute)
 
 	// clientData challenge 與 session 不同
 	clientData := map[string]interface{}{
@@ -151,14 +493,15 @@ func TestFinishAssertionHandler_ChallengeMismatch(t *testing.T) {
 	encodedClientData := base64.RawURLEncoding.EncodeToString(clientDataJSON)
 
 	req := dto.AuthenticatorAssertionResponseRequest{
-		Id: "credid",
+		CeremonyID: "test-ceremony",
+		Id:         "credid",
 		Response: dto.AuthenticatorAssertionResponse{
 			ClientDataJSON:    encodedClientData,
 			AuthenticatorData: base64.RawURLEncoding.EncodeToString([]byte("data")),
 			Signature:         base64.RawURLEncoding.EncodeToString([]byte("sig")),
 			UserHandle:        base64.RawURLEncoding.EncodeToString([]byte("user-handle")),
 		},
-		GetClientExtensionResults: map[string]interface{}{},
+		GetClientExtensionResults: dto.ClientExtensionResults{},
 		Type:                      "public-key",
 	}
 	body, _ := json.Marshal(req)
@@ -173,13 +516,64 @@ func TestFinishAssertionHandler_ChallengeMismatch(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+// clientDataJSON 的 challenge 缺少、不是字串或為空時應回傳 400 而非 panic
+func TestFinishAssertionHandler_MalformedChallenge(t *testing.T) {
+	tests := []struct {
+		name       string
+		clientData map[string]interface{}
+	}{
+		{name: "缺少 challenge", clientData: map[string]interface{}{"type": "webauthn.get"}},
+		{name: "challenge 不是字串", clientData: map[string]interface{}{"challenge": 123}},
+		{name: "challenge 為空", clientData: map[string]interface{}{"challenge": ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := mocks.NewMockUserUseCase(t)
+			mockCredUC := mocks.NewMockCredentialUseCase(t)
+			mockTokenUC := mocks.NewMockTokenUseCase(t)
+			c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+			_ = c.Sessions.Save(context.Background(), "test-ceremony", &webauthn.SessionData{
+				Challenge: "test-challenge",
+			}, time.Minute)
+
+			clientDataJSON, _ := json.Marshal(tt.clientData)
+			body, _ := json.Marshal(dto.AuthenticatorAssertionResponseRequest{
+				CeremonyID: "test-ceremony",
+				Id:         "credid",
+				Response: dto.AuthenticatorAssertionResponse{
+					ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+					AuthenticatorData: base64.RawURLEncoding.EncodeToString([]byte("data")),
+					Signature:         base64.RawURLEncoding.EncodeToString([]byte("sig")),
+					UserHandle:        base64.RawURLEncoding.EncodeToString([]byte("user-handle")),
+				},
+				GetClientExtensionResults: dto.ClientExtensionResults{},
+				Type:                      "public-key",
+			})
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/assertion/result", bytes.NewBuffer(body))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			c.FinishAssertionHandler(ctx)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
 func TestFinishAssertionHandler_UserNotFound(t *testing.T) {
 	mockUC := mocks.NewMockUserUseCase(t)
-	c := NewAuthController(mockUC)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
 
-	assertionSessionData = &webauthn.SessionData{
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", &webauthn.SessionData{
 		Challenge: "test-challenge",
-	}
+		UserID:    []byte("user-handle"),
+	}, time.Minute)
 
 	clientData := map[string]interface{}{
 		"challenge": "test-challenge",
@@ -188,21 +582,26 @@ func TestFinishAssertionHandler_UserNotFound(t *testing.T) {
 	encodedClientData := base64.RawURLEncoding.EncodeToString(clientDataJSON)
 
 	req := dto.AuthenticatorAssertionResponseRequest{
-		Id: "credid",
+		CeremonyID: "test-ceremony",
+		Id:         "credid",
 		Response: dto.AuthenticatorAssertionResponse{
 			ClientDataJSON:    encodedClientData,
 			AuthenticatorData: base64.RawURLEncoding.EncodeToString([]byte("data")),
 			Signature:         base64.RawURLEncoding.EncodeToString([]byte("sig")),
 			UserHandle:        base64.RawURLEncoding.EncodeToString([]byte("user-handle")),
 		},
-		GetClientExtensionResults: map[string]interface{}{},
+		GetClientExtensionResults: dto.ClientExtensionResults{},
 		Type:                      "public-key",
 	}
 	body, _ := json.Marshal(req)
 
 	// mock UserUseCase 查無資料
 	mockUC.EXPECT().
-		GetUserByChallenge("test-challenge").
+		ConsumeChallenge("default", "test-challenge").
+		Return(&entity.User{ID: "1"}, nil)
+
+	mockUC.EXPECT().
+		GetUserByUserHandle("default", []byte("user-handle")).
 		Return(nil, errors.New("not found"))
 
 	w := httptest.NewRecorder()
@@ -215,23 +614,85 @@ func TestFinishAssertionHandler_UserNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
-func TestFinishAssertionHandler_InvalidBase64(t *testing.T) {
+func TestFinishAssertionHandler_CredentialOfAnotherUser(t *testing.T) {
 	mockUC := mocks.NewMockUserUseCase(t)
-	c := NewAuthController(mockUC)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
 
-	assertionSessionData = &webauthn.SessionData{
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", &webauthn.SessionData{
 		Challenge: "test-challenge",
+		UserID:    []byte("user-handle"),
+	}, time.Minute)
+
+	clientData := map[string]interface{}{
+		"challenge": "test-challenge",
 	}
+	clientDataJSON, _ := json.Marshal(clientData)
+	encodedClientData := base64.RawURLEncoding.EncodeToString(clientDataJSON)
 
 	req := dto.AuthenticatorAssertionResponseRequest{
-		Id: "credid",
+		CeremonyID: "test-ceremony",
+		Id:         "Y3JlZGlk",
+		Response: dto.AuthenticatorAssertionResponse{
+			ClientDataJSON:    encodedClientData,
+			AuthenticatorData: base64.RawURLEncoding.EncodeToString([]byte("data")),
+			Signature:         base64.RawURLEncoding.EncodeToString([]byte("sig")),
+			UserHandle:        base64.RawURLEncoding.EncodeToString([]byte("user-handle")),
+		},
+		GetClientExtensionResults: dto.ClientExtensionResults{},
+		Type:                      "public-key",
+	}
+	body, _ := json.Marshal(req)
+
+	mockUC.EXPECT().
+		ConsumeChallenge("default", "test-challenge").
+		Return(&entity.User{ID: "1"}, nil)
+
+	mockUC.EXPECT().
+		GetUserByUserHandle("default", []byte("user-handle")).
+		Return(&entity.User{
+			ID:          "1",
+			UserName:    "testuser",
+			DisplayName: "Test User",
+		}, nil)
+
+	// Credential 屬於其他使用者
+	mockCredUC.EXPECT().
+		GetCredentialByID("Y3JlZGlk").
+		Return(&entity.Credential{ID: "Y3JlZGlk", UserID: "2"}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/assertion/result", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.FinishAssertionHandler(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestFinishAssertionHandler_InvalidBase64(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", &webauthn.SessionData{
+		Challenge: "test-challenge",
+		UserID:    []byte("user-handle"),
+	}, time.Minute)
+
+	req := dto.AuthenticatorAssertionResponseRequest{
+		CeremonyID: "test-ceremony",
+		Id:         "credid",
 		Response: dto.AuthenticatorAssertionResponse{
 			ClientDataJSON:    "!!!invalidbase64!!!",
 			AuthenticatorData: base64.RawURLEncoding.EncodeToString([]byte("data")),
 			Signature:         base64.RawURLEncoding.EncodeToString([]byte("sig")),
 			UserHandle:        base64.RawURLEncoding.EncodeToString([]byte("user-handle")),
 		},
-		GetClientExtensionResults: map[string]interface{}{},
+		GetClientExtensionResults: dto.ClientExtensionResults{},
 		Type:                      "public-key",
 	}
 	body, _ := json.Marshal(req)
@@ -244,4 +705,230 @@ func TestFinishAssertionHandler_InvalidBase64(t *testing.T) {
 	c.FinishAssertionHandler(ctx)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+func TestFinishAssertionHandler_Authorization(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	mockOIDCUC := mocks.NewMockOIDCUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, mockOIDCUC, session.NewMemoryStore(time.Minute))
+
+	user := &entity.User{ID: "1", UserHandle: []byte("user-handle"), UserName: "testuser"}
+	authenticator := newVirtualAuthenticator(t)
+	storedCredential := authenticator.storedCredential(t, user.ID)
+
+	_, sessionData, err := tenant.Default().WebAuthn.BeginLogin(wAuth.NewUserWebAuthn(user, []*entity.Credential{storedCredential}))
+	assert.NoError(t, err)
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", sessionData, time.Minute)
+
+	// 由 OIDC /authorize 頁面發起的登入帶有授權請求 ID
+	req := dto.AuthenticatorAssertionResponseRequest{
+		CeremonyID:             "test-ceremony",
+		AuthorizationRequestID: "request-1",
+		Id:                     authenticator.id(),
+		Response:               authenticator.assertion(t, sessionData.Challenge, user.UserHandle),
+		Type:                   "public-key",
+	}
+	body, _ := json.Marshal(req)
+
+	mockUC.EXPECT().ConsumeChallenge("default", sessionData.Challenge).Return(user, nil)
+	mockUC.EXPECT().GetUserByUserHandle("default", user.UserHandle).Return(user, nil)
+	mockCredUC.EXPECT().GetCredentialByID(authenticator.id()).Return(storedCredential, nil)
+	mockCredUC.EXPECT().GetCredentialsByUserID("1").Return([]*entity.Credential{storedCredential}, nil)
+	mockCredUC.EXPECT().UpdateCredential(&entity.Credential{ID: authenticator.id()}, mock.Anything).Return(nil)
+
+	// 改為產生 Authorization Code，不直接簽發 Token
+	mockOIDCUC.EXPECT().
+		CompleteAuthorization("default", "request-1", user, authenticator.id(), mock.Anything).
+		Run(func(_, _ string, _ *entity.User, _ string, flags webauthn.CredentialFlags) {
+			assert.True(t, flags.UserVerified)
+		}).
+		Return("https://app.example.com/callback?code=abc&state=xyz", nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/assertion/result", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.FinishAssertionHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.AuthorizationResultResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "https://app.example.com/callback?code=abc&state=xyz", response.RedirectURI)
+}
+
+func TestFinishAssertionHandler_ChallengeExpired(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", &webauthn.SessionData{
+		Challenge: "test-challenge",
+		UserID:    []byte("user-handle"),
+	}, time.Minute)
+
+	clientDataJSON, _ := json.Marshal(map[string]interface{}{"challenge": "test-challenge"})
+	body, _ := json.Marshal(dto.AuthenticatorAssertionResponseRequest{
+		CeremonyID: "test-ceremony",
+		Id:         "Y3JlZGlk",
+		Response: dto.AuthenticatorAssertionResponse{
+			ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+			AuthenticatorData: base64.RawURLEncoding.EncodeToString([]byte("data")),
+			Signature:         base64.RawURLEncoding.EncodeToString([]byte("sig")),
+		},
+		GetClientExtensionResults: dto.ClientExtensionResults{},
+		Type:                      "public-key",
+	})
+
+	// Challenge 已過期或已被其他請求消耗
+	mockUC.EXPECT().
+		ConsumeChallenge("default", "test-challenge").
+		Return(nil, repository.ErrChallengeExpired)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/assertion/result", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.FinishAssertionHandler(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response common.CommonResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, common.ErrorCodeChallengeExpired, response.ErrorCode)
+}
+
+// 設定 AppID 的租戶，使用者有 U2F Credential 時才要求 appid 擴充
+func TestStartAssertionHandler_AppID(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, nil, nil, session.NewMemoryStore(time.Minute))
+
+	rp := *tenant.Default()
+	rp.AppID = "https://localhost/u2f/app-id.json"
+
+	tests := []struct {
+		name            string
+		attestationType string
+		expected        bool
+	}{
+		{name: "U2F Credential", attestationType: "fido-u2f", expected: true},
+		{name: "Passkey", attestationType: "none", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := &entity.User{ID: "1", UserHandle: []byte("user-handle"), UserName: "testuser"}
+			mockUC.EXPECT().GetUserByUsername("default", "testuser").Return(user, nil).Once()
+			mockCredUC.EXPECT().
+				GetCredentialsByUserID("1").
+				Return([]*entity.Credential{{ID: "Y3JlZGlk", UserID: "1", AttestationType: tc.attestationType}}, nil).
+				Once()
+			mockUC.EXPECT().SetChallenge(user, mock.AnythingOfType("string"), rp.LoginTimeout).Return(nil).Once()
+
+			body, _ := json.Marshal(dto.CredentialGetOptionsRequest{Username: "testuser"})
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/assertion/options", bytes.NewBuffer(body))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+			tenant.Set(ctx, &rp)
+
+			c.StartAssertionHandler(ctx)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response dto.CredentialGetOptionsResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			appID, ok := response.Extensions[protocol.ExtensionAppID]
+			assert.Equal(t, tc.expected, ok)
+			if tc.expected {
+				assert.Equal(t, rp.AppID, appID)
+			}
+		})
+	}
+}
+
+// 匯入的 U2F 金鑰以 AppID 計算 rpIdHash，用戶端回報 appid 後才可通過驗證
+func TestFinishAssertionHandler_U2F(t *testing.T) {
+	rp := *tenant.Default()
+	rp.AppID = "https://localhost/u2f/app-id.json"
+
+	for _, appIDUsed := range []bool{true, false} {
+		mockUC := mocks.NewMockUserUseCase(t)
+		mockCredUC := mocks.NewMockCredentialUseCase(t)
+		mockTokenUC := mocks.NewMockTokenUseCase(t)
+		c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+		user := &entity.User{ID: "1", UserHandle: []byte("user-handle"), UserName: "testuser"}
+		authenticator := newVirtualAuthenticator(t)
+		authenticator.flags = protocol.FlagUserPresent
+		authenticator.appID = rp.AppID
+
+		publicKey, err := authenticator.privateKey.PublicKey.ECDH()
+		assert.NoError(t, err)
+		storedCredential, err := wAuth.NewU2FCredentialEntity("default", user.ID, authenticator.credentialID, publicKey.Bytes(), 0)
+		assert.NoError(t, err)
+
+		_, sessionData, err := rp.WebAuthn.BeginLogin(wAuth.NewUserWebAuthn(user, []*entity.Credential{storedCredential}), webauthn.WithAppIdExtension(rp.AppID))
+		assert.NoError(t, err)
+		_ = c.Sessions.Save(context.Background(), "test-ceremony", sessionData, time.Minute)
+
+		body, _ := json.Marshal(dto.AuthenticatorAssertionResponseRequest{
+			CeremonyID:                "test-ceremony",
+			Id:                        authenticator.id(),
+			Response:                  authenticator.assertion(t, sessionData.Challenge, nil),
+			GetClientExtensionResults: dto.ClientExtensionResults{AppID: &appIDUsed},
+			Type:                      "public-key",
+		})
+
+		mockUC.EXPECT().ConsumeChallenge("default", sessionData.Challenge).Return(user, nil)
+		mockCredUC.EXPECT().GetCredentialByID(authenticator.id()).Return(storedCredential, nil)
+		mockCredUC.EXPECT().GetCredentialsByUserID("1").Return([]*entity.Credential{storedCredential}, nil)
+		if appIDUsed {
+			mockCredUC.EXPECT().UpdateCredential(&entity.Credential{ID: authenticator.id()}, mock.Anything).Return(nil)
+			mockTokenUC.EXPECT().
+				IssueTokens("http://localhost:3000", user, authenticator.id(), false).
+				Return(&token.Pair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 15 * time.Minute}, nil)
+		}
+
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request, _ = http.NewRequest("POST", "/assertion/result", bytes.NewBuffer(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		tenant.Set(ctx, &rp)
+
+		c.FinishAssertionHandler(ctx)
+
+		if appIDUsed {
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		} else {
+			// 未使用 AppID 時 rpIdHash 與 RP ID 不符
+			assert.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+		}
+	}
+}
+
+// Session 與登入 Challenge 同樣依 RP 設定的登入逾時保存，不會在 Challenge 仍有效時先過期
+func TestStartAssertionHandler_SessionTTL(t *testing.T) {
+	sessions := session.NewMockSessionStore(t)
+	c := NewAuthController(nil, nil, nil, nil, sessions)
+
+	rp := *tenant.Default()
+	rp.LoginTimeout = 10 * time.Minute
+
+	sessions.EXPECT().Save(mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*webauthn.SessionData"), 10*time.Minute).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/assertion/options", bytes.NewBufferString(`{}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	tenant.Set(ctx, &rp)
+
+	c.StartAssertionHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
 }
\ No newline at end of file
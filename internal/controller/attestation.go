@@ -3,9 +3,12 @@ package controller
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fido2/internal/dto"
-	"fido2/internal/entity"
+	"fido2/internal/platform/tenant"
+	"fido2/internal/platform/username"
 	wAuth "fido2/internal/platform/webauthn"
+	"fido2/internal/repository"
 	"fido2/pkg/utils"
 	"fido2/pkg/utils/common"
 	"github.com/gin-gonic/gin"
@@ -13,16 +16,17 @@ import (
 	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"net/http"
+	"time"
 )
 
-var attestationSessionData *webauthn.SessionData
-
 // StartAttestationHandler Credential Creation Options
 // WebAuthn 產生註冊資訊的請求
 func (c *AuthController) StartAttestationHandler(ctx *gin.Context) {
 
 	utils.GetLogger().Info("StartAttestationHandler called")
 
+	rp := tenant.FromContext(ctx)
+
 	var request *dto.CredentialCreationOptionsRequest
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -36,24 +40,51 @@ func (c *AuthController) StartAttestationHandler(ctx *gin.Context) {
 		return
 	}
 
-	user := &entity.User{
-		ID:          uuid.New().String(),
-		UserName:    request.Username,
-		DisplayName: request.DisplayName,
+	if !validAttestationPreference(ctx, rp, request.Attestation) {
+		return
 	}
 
-	webauthnUser := wAuth.NewUserWebAuthn(user)
-	//excludeCredentialsOption := webauthn.WithExclusions(webauthnUser.CredentialExcludeList())
-	//authenticatorSelectionOption := webauthn.WithAuthenticatorSelection(request.AuthenticatorSelection)
-	//attestationOption := webauthn.WithConveyancePreference(protocol.ConveyancePreference(request.Attestation))
+	extensions, err := registrationExtensions(request.Extensions, rp.AuthenticatorPolicy.RegistrationExtensions())
+	if err != nil {
+		invalidExtensionResponse(ctx, err)
+		return
+	}
 
-	opts := func(options *protocol.PublicKeyCredentialCreationOptions) {
-		options.CredentialExcludeList = webauthnUser.CredentialExcludeList()
-		options.AuthenticatorSelection = request.AuthenticatorSelection
-		options.Attestation = protocol.ConveyancePreference(request.Attestation)
+	// 同一租戶內的使用者名稱只對應一個帳號，未完成的註冊只在保留期限內佔用使用者名稱
+	user, err := c.UserUC.ReserveUsername(rp.ID, request.Username, request.DisplayName, rp.RegistrationTimeout)
+	if err != nil {
+		switch {
+		case errors.Is(err, username.ErrInvalidUsername):
+			ctx.JSON(
+				http.StatusBadRequest,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "invalid username, error: " + err.Error(),
+					ErrorCode:    common.ErrorCodeInvalidUsername,
+				},
+			)
+		case errors.Is(err, repository.ErrUsernameTaken):
+			ctx.JSON(
+				http.StatusConflict,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "username is already taken, sign in to add a passkey to an existing account",
+					ErrorCode:    common.ErrorCodeUsernameTaken,
+				},
+			)
+		default:
+			ctx.JSON(
+				http.StatusInternalServerError,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to reserve username, error: " + err.Error(),
+				},
+			)
+		}
+		return
 	}
 
-	options, sessionData, err := wAuth.WebAuthn.BeginRegistration(webauthnUser, opts)
+	options, sessionData, err := rp.WebAuthn.BeginRegistration(wAuth.NewUserWebAuthn(user, nil), attestationOptions(rp, request, nil, extensions))
 
 	if err != nil {
 		utils.GetLogger().Error("begin registration failed, error: ", err.Error())
@@ -69,25 +100,205 @@ func (c *AuthController) StartAttestationHandler(ctx *gin.Context) {
 
 	utils.GetLogger().Infof("Credential Creation Options: %+v", options)
 
-	user.Challenge = options.Response.Challenge.String()
-	user.Credential = "`" + "{}" + "`"
+	if err := c.UserUC.SetChallenge(user, options.Response.Challenge.String(), rp.RegistrationTimeout); err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to save user, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	utils.GetLogger().Infof("Saved user: %+v", user)
+
+	c.creationOptionsResponse(ctx, options, sessionData)
+}
+
+// StartAddCredentialHandler Credential Creation Options
+// 已登入的使用者為既有帳號新增 Passkey，沿用原本的 user handle 並排除已註冊的驗證器
+func (c *AuthController) StartAddCredentialHandler(ctx *gin.Context) {
+
+	utils.GetLogger().Info("StartAddCredentialHandler called")
+
+	rp := tenant.FromContext(ctx)
+	claims := accessClaims(ctx)
+
+	var request *dto.CredentialCreationOptionsRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to parse request body, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	if !validAttestationPreference(ctx, rp, request.Attestation) {
+		return
+	}
+
+	extensions, err := registrationExtensions(request.Extensions, rp.AuthenticatorPolicy.RegistrationExtensions())
+	if err != nil {
+		invalidExtensionResponse(ctx, err)
+		return
+	}
+
+	user, err := c.UserUC.GetUserByID(claims.Subject)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	if user == nil || user.TenantID != rp.ID {
+		ctx.JSON(
+			http.StatusNotFound,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "user not found",
+			},
+		)
+		return
+	}
 
-	if err := c.UserUC.CreateUser(user); err != nil {
+	credentials, err := c.CredentialUC.GetCredentialsByUserID(user.ID)
+	if err != nil {
 		ctx.JSON(
 			http.StatusInternalServerError,
 			common.CommonResponse{
 				Status:       "failed",
-				ErrorMessage: "failed to create user, error: " + err.Error(),
+				ErrorMessage: "failed to get user credentials, error: " + err.Error(),
 			},
 		)
 		return
 	}
 
-	sessionData.Challenge = base64.RawStdEncoding.EncodeToString([]byte(sessionData.Challenge))
+	webauthnUser := wAuth.NewUserWebAuthn(user, credentials)
 
-	utils.GetLogger().Infof("Created user: %+v", user)
+	options, sessionData, err := rp.WebAuthn.BeginRegistration(webauthnUser, attestationOptions(rp, request, webauthnUser.CredentialExcludeList(), extensions))
 
-	attestationSessionData = sessionData
+	if err != nil {
+		utils.GetLogger().Error("begin registration failed, error: ", err.Error())
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to create credential creation options, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	// FinishAttestationHandler 透過 Challenge 找回使用者，新的 Credential 會附加在既有帳號下
+	if err := c.UserUC.SetChallenge(user, options.Response.Challenge.String(), rp.RegistrationTimeout); err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to save user, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	c.creationOptionsResponse(ctx, options, sessionData)
+}
+
+// validAttestationPreference 檢查請求的 Attestation 傳遞偏好，enterprise 只開放給已設定企業 Attestation 的租戶
+func validAttestationPreference(ctx *gin.Context, rp *tenant.Tenant, preference string) bool {
+	switch protocol.ConveyancePreference(preference) {
+	case "", protocol.PreferNoAttestation, protocol.PreferIndirectAttestation, protocol.PreferDirectAttestation:
+		return true
+	case protocol.PreferEnterpriseAttestation:
+		if rp.EnterpriseAttestation != nil {
+			return true
+		}
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "enterprise attestation is not enabled for this relying party",
+				ErrorCode:    common.ErrorCodeEnterpriseAttestationNotAllowed,
+			},
+		)
+	default:
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "unknown attestation preference: " + preference,
+				ErrorCode:    common.ErrorCodeInvalidAttestation,
+			},
+		)
+	}
+	return false
+}
+
+// attestationOptions 未指定的選項沿用 RP 設定檔中的預設值
+func attestationOptions(rp *tenant.Tenant, request *dto.CredentialCreationOptionsRequest, exclusions []protocol.CredentialDescriptor, extensions protocol.AuthenticationExtensions) webauthn.RegistrationOption {
+	return func(options *protocol.PublicKeyCredentialCreationOptions) {
+		options.CredentialExcludeList = exclusions
+		options.Extensions = extensions
+		if rp.AppID != "" {
+			// 讓用戶端以 AppID 一併比對 excludeCredentials，避免已有 U2F Credential 的驗證器重複註冊
+			if options.Extensions == nil {
+				options.Extensions = protocol.AuthenticationExtensions{}
+			}
+			options.Extensions[protocol.ExtensionAppIDExclude] = rp.AppID
+		}
+		options.Parameters = rp.CredentialParameters
+		if request.AuthenticatorSelection.AuthenticatorAttachment != "" {
+			options.AuthenticatorSelection.AuthenticatorAttachment = request.AuthenticatorSelection.AuthenticatorAttachment
+		}
+		if request.AuthenticatorSelection.ResidentKey != "" {
+			options.AuthenticatorSelection.ResidentKey = request.AuthenticatorSelection.ResidentKey
+		}
+		if request.AuthenticatorSelection.RequireResidentKey != nil {
+			options.AuthenticatorSelection.RequireResidentKey = request.AuthenticatorSelection.RequireResidentKey
+		}
+		if request.AuthenticatorSelection.UserVerification != "" {
+			options.AuthenticatorSelection.UserVerification = request.AuthenticatorSelection.UserVerification
+		}
+		if request.Attestation != "" {
+			options.Attestation = protocol.ConveyancePreference(request.Attestation)
+		}
+		if _, ok := extensions[wAuth.PaymentExtension]; ok {
+			// Secure Payment Confirmation 只接受 platform 驗證器上需使用者驗證的 discoverable credential
+			options.AuthenticatorSelection.AuthenticatorAttachment = protocol.Platform
+			options.AuthenticatorSelection.ResidentKey = protocol.ResidentKeyRequirementRequired
+			options.AuthenticatorSelection.RequireResidentKey = protocol.ResidentKeyRequired()
+			options.AuthenticatorSelection.UserVerification = protocol.VerificationRequired
+		}
+	}
+}
+
+// creationOptionsResponse 保存註冊儀式的 SessionData 並回傳 Credential Creation Options
+func (c *AuthController) creationOptionsResponse(ctx *gin.Context, options *protocol.CredentialCreation, sessionData *webauthn.SessionData) {
+	// go-webauthn 註冊的 SessionData 不保存 extensions，完成註冊時需知道是否要求了 payment 擴充
+	sessionData.Extensions = options.Response.Extensions
+
+	// Session 與註冊 Challenge 同樣在 RP 設定的註冊逾時後失效
+	ceremonyID := uuid.New().String()
+	if err := c.Sessions.Save(ctx.Request.Context(), ceremonyID, sessionData, tenant.FromContext(ctx).RegistrationTimeout); err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to save session data, error: " + err.Error(),
+			},
+		)
+		return
+	}
 
 	ctx.JSON(
 		http.StatusOK,
@@ -97,6 +308,7 @@ func (c *AuthController) StartAttestationHandler(ctx *gin.Context) {
 				ErrorMessage: "",
 			},
 			PublicKeyCredentialCreationOptions: options.Response,
+			CeremonyID:                         ceremonyID,
 		},
 	)
 }
@@ -106,6 +318,8 @@ func (c *AuthController) StartAttestationHandler(ctx *gin.Context) {
 func (c *AuthController) FinishAttestationHandler(ctx *gin.Context) {
 	utils.GetLogger().Info("Processing attestation response")
 
+	rp := tenant.FromContext(ctx)
+
 	var request *dto.AuthenticatorAttestationResponseRequest
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -119,6 +333,11 @@ func (c *AuthController) FinishAttestationHandler(ctx *gin.Context) {
 		return
 	}
 
+	sessionData, ok := takeSession(ctx, c.Sessions, request.CeremonyID)
+	if !ok {
+		return
+	}
+
 	authenticatorClientDataJSON, err := base64.RawURLEncoding.DecodeString(request.Response.ClientDataJSON)
 	if err != nil {
 		ctx.JSON(
@@ -157,7 +376,7 @@ func (c *AuthController) FinishAttestationHandler(ctx *gin.Context) {
 		return
 	}
 
-	if challenge != attestationSessionData.Challenge {
+	if challenge != sessionData.Challenge {
 		ctx.JSON(
 			http.StatusBadRequest,
 			common.CommonResponse{
@@ -166,44 +385,10 @@ func (c *AuthController) FinishAttestationHandler(ctx *gin.Context) {
 			},
 		)
 		return
-	} else {
-
-		decodedChallenge, err := base64.RawURLEncoding.DecodeString(challenge)
-
-		if err != nil {
-			ctx.JSON(
-				http.StatusBadRequest,
-				common.CommonResponse{
-					Status:       "failed",
-					ErrorMessage: "failed to decode challenge, error: " + err.Error(),
-				},
-			)
-		}
-
-		challenge = string(decodedChallenge)
-
-		// decodedChallenge 在 err != nil 時不會有值
-		//if decodedChallenge, err := base64.RawURLEncoding.DecodeString(challenge); err != nil {
-		//	ctx.JSON(
-		//		http.StatusBadRequest,
-		//		common.CommonResponse{
-		//			Status:       "failed",
-		//			ErrorMessage: "failed to decode challenge, error: " + err.Error(),
-		//		},
-		//	)
-		//	challenge = string(decodedChallenge)
-		//}
 	}
 
-	foundUser, err := c.UserUC.GetUserByChallenge(challenge)
-	if err != nil {
-		ctx.JSON(
-			http.StatusInternalServerError,
-			common.CommonResponse{
-				Status:       "failed",
-				ErrorMessage: "failed to get user by challenge, error: " + err.Error(),
-			},
-		)
+	foundUser, ok := c.consumeChallenge(ctx, rp.ID, challenge)
+	if !ok {
 		return
 	}
 
@@ -227,8 +412,9 @@ func (c *AuthController) FinishAttestationHandler(ctx *gin.Context) {
 				ID:   request.Id,
 				Type: request.Type,
 			},
-			RawID:                  []byte(request.Id),
-			ClientExtensionResults: request.GetClientExtensionResults,
+			RawID:                   []byte(request.Id),
+			ClientExtensionResults:  request.GetClientExtensionResults.ToProtocol(),
+			AuthenticatorAttachment: request.AuthenticatorAttachment,
 		},
 		AttestationResponse: protocol.AuthenticatorAttestationResponse{
 			AttestationObject: protocol.URLEncodedBase64(authenticatorAttestationObject),
@@ -251,10 +437,22 @@ func (c *AuthController) FinishAttestationHandler(ctx *gin.Context) {
 	}
 	utils.GetLogger().Infof("Parsed PublicKeyCredential: %+v", pcc)
 
-	// 將 domain.User 包裝為 WebAuthn User
-	webauthnUser := wAuth.NewUserWebAuthn(foundUser)
+	credentials, err := c.CredentialUC.GetCredentialsByUserID(foundUser.ID)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user credentials, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	// 將 entity.User 包裝為 WebAuthn User
+	webauthnUser := wAuth.NewUserWebAuthn(foundUser, credentials)
 
-	credential, err := wAuth.WebAuthn.CreateCredential(webauthnUser, *attestationSessionData, pcc)
+	credential, err := rp.WebAuthn.CreateCredential(webauthnUser, *sessionData, pcc)
 
 	if err != nil {
 		ctx.JSON(
@@ -269,31 +467,87 @@ func (c *AuthController) FinishAttestationHandler(ctx *gin.Context) {
 
 	utils.GetLogger().Infof("Created credential: %+v", credential)
 
-	credentialJSON, err := json.Marshal(credential)
-	if err != nil {
+	if err = rp.AuthenticatorPolicy.Evaluate(credential, pcc.Response.AttestationObject, wAuth.MetadataEntry(credential.Authenticator.AAGUID)); err != nil {
+		utils.GetLogger().Warnf("Rejected authenticator for user %s: %v", foundUser.ID, err)
+
+		var violation *wAuth.PolicyViolation
+		if !errors.As(err, &violation) {
+			ctx.JSON(
+				http.StatusBadRequest,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to evaluate authenticator policy, error: " + err.Error(),
+				},
+			)
+			return
+		}
+		ctx.JSON(
+			http.StatusForbidden,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "authenticator not allowed, error: " + err.Error(),
+				ErrorCode:    violation.Code,
+			},
+		)
+		return
+	}
+
+	credentialEntity := wAuth.NewCredentialEntity(foundUser.TenantID, foundUser.ID, credential)
+	_, credentialEntity.Payment = sessionData.Extensions[wAuth.PaymentExtension]
+	if err = applyRegistrationExtensionResults(credentialEntity, request.GetClientExtensionResults); err != nil {
 		ctx.JSON(
 			http.StatusInternalServerError,
 			common.CommonResponse{
 				Status:       "failed",
-				ErrorMessage: "failed to marshal credential, error: " + err.Error(),
+				ErrorMessage: "failed to record extension results, error: " + err.Error(),
 			},
 		)
 		return
 	}
 
-	utils.GetLogger().Infof("Credential JSON: %s", string(credentialJSON))
+	// 企業 Attestation 的憑證可識別個別裝置，只有設定的驗證器型號與根憑證才會保存裝置資料
+	if rp.EnterpriseAttestation != nil {
+		device, err := rp.EnterpriseAttestation.Verify(credential, pcc.Response.AttestationObject, time.Now())
+		switch {
+		case err == nil:
+			credentialEntity.EnterpriseAttestation = true
+			credentialEntity.DeviceSerialNumber = device.SerialNumber
+			credentialEntity.AttestationCertificate = device.Certificate
+		case rp.EnterpriseAttestation.Required:
+			utils.GetLogger().Warnf("Rejected non-enterprise attestation for user %s: %v", foundUser.ID, err)
+			ctx.JSON(
+				http.StatusForbidden,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "enterprise attestation required, error: " + err.Error(),
+					ErrorCode:    common.ErrorCodeEnterpriseAttestationRequired,
+				},
+			)
+			return
+		default:
+			utils.GetLogger().Infof("Credential for user %s is not enterprise attested: %v", foundUser.ID, err)
+		}
+	}
 
-	// 更新使用者憑證後再呼叫 UpdateUser
-	if err = c.UserUC.UpdateUser(
-		foundUser, entity.User{
-			Credential: "`" + string(credentialJSON) + "`",
-		},
-	); err != nil {
+	// 每個驗證器各自新增一筆 Credential，不覆蓋使用者既有的 Credential
+	// Credential 與解除使用者名稱保留在同一個交易中寫入
+	if err = c.UserUC.CompleteRegistration(foundUser, credentialEntity); err != nil {
+		if errors.Is(err, repository.ErrReservationLost) {
+			ctx.JSON(
+				http.StatusConflict,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to complete registration, error: " + err.Error(),
+					ErrorCode:    common.ErrorCodeUsernameTaken,
+				},
+			)
+			return
+		}
 		ctx.JSON(
 			http.StatusInternalServerError,
 			common.CommonResponse{
 				Status:       "failed",
-				ErrorMessage: "failed to update user, error: " + err.Error(),
+				ErrorMessage: "failed to complete registration, error: " + err.Error(),
 			},
 		)
 		return
@@ -2,13 +2,21 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fido2/config"
 	"fido2/internal/dto"
 	"fido2/internal/entity"
+	"fido2/internal/platform/session"
+	"fido2/internal/platform/tenant"
+	"fido2/internal/platform/username"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/internal/repository"
 	mocks "fido2/internal/usecase"
 	"fido2/pkg/utils"
+	"fido2/pkg/utils/common"
 	"github.com/gin-gonic/gin"
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
@@ -17,12 +25,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 // 測試成功流程
 func TestStartAttestationHandler_Success(t *testing.T) {
 	mockUC := mocks.NewMockUserUseCase(t)
-	c := NewAuthController(mockUC)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
 
 	// input 輸入
 	reqBody := dto.CredentialCreationOptionsRequest{
@@ -37,9 +48,15 @@ func TestStartAttestationHandler_Success(t *testing.T) {
 	}
 	body, _ := json.Marshal(reqBody)
 
-	// 期望 mock CreateUser 被呼叫，並回傳 nil（成功）
+	user := &entity.User{ID: "1", TenantID: "default", UserHandle: []byte("user-handle"), UserName: "testuser"}
+
+	// 期望 mock ReserveUsername 被呼叫，並回傳保留中的使用者（成功）
+	mockUC.EXPECT().
+		ReserveUsername("default", "testuser", "Test User", tenant.Default().RegistrationTimeout).
+		Return(user, nil)
+
 	mockUC.EXPECT().
-		CreateUser(mock.AnythingOfType("*domain.User")).
+		SetChallenge(user, mock.AnythingOfType("string"), tenant.Default().RegistrationTimeout).
 		Return(nil)
 
 	// Gin context
@@ -56,10 +73,139 @@ func TestStartAttestationHandler_Success(t *testing.T) {
 	// 你可視需求解析 response 內容
 }
 
-// 測試 CreateUser 失敗流程
-func TestStartAttestationHandler_CreateUserFail(t *testing.T) {
+// 租戶要求 credProtect 與 minPinLength 時，Options 一律帶上對應的擴充
+func TestStartAttestationHandler_PolicyExtensions(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	c := NewAuthController(mockUC, nil, nil, nil, session.NewMemoryStore(time.Minute))
+
+	policy, err := wAuth.NewAuthenticatorPolicy(config.AuthenticatorPolicy{CredentialProtection: "userVerificationRequired", MinPinLength: 6})
+	assert.NoError(t, err)
+	rp := *tenant.Default()
+	rp.AuthenticatorPolicy = policy
+
+	body, _ := json.Marshal(dto.CredentialCreationOptionsRequest{
+		Username:    "testuser",
+		DisplayName: "Test User",
+		Extensions:  dto.RegistrationExtensionsInput{CredProps: true},
+	})
+
+	user := &entity.User{ID: "1", TenantID: "default", UserHandle: []byte("user-handle"), UserName: "testuser"}
+
+	mockUC.EXPECT().
+		ReserveUsername("default", "testuser", "Test User", tenant.Default().RegistrationTimeout).
+		Return(user, nil)
+
+	mockUC.EXPECT().
+		SetChallenge(user, mock.AnythingOfType("string"), rp.RegistrationTimeout).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/attestation/options", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	tenant.Set(ctx, &rp)
+
+	c.StartAttestationHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Extensions map[string]any `json:"extensions"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]any{
+		"credProps":                         true,
+		"credentialProtectionPolicy":        "userVerificationRequired",
+		"enforceCredentialProtectionPolicy": true,
+		"minPinLength":                      true,
+	}, response.Extensions)
+}
+
+// 設定 AppID 的租戶要求 appidExclude，避免已有 U2F Credential 的驗證器重複註冊
+func TestStartAttestationHandler_AppIDExclude(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	c := NewAuthController(mockUC, nil, nil, nil, session.NewMemoryStore(time.Minute))
+
+	rp := *tenant.Default()
+	rp.AppID = "https://localhost/u2f/app-id.json"
+
+	body, _ := json.Marshal(dto.CredentialCreationOptionsRequest{Username: "testuser", DisplayName: "Test User"})
+
+	user := &entity.User{ID: "1", TenantID: "default", UserHandle: []byte("user-handle"), UserName: "testuser"}
+
+	mockUC.EXPECT().
+		ReserveUsername("default", "testuser", "Test User", tenant.Default().RegistrationTimeout).
+		Return(user, nil)
+
+	mockUC.EXPECT().
+		SetChallenge(user, mock.AnythingOfType("string"), rp.RegistrationTimeout).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/attestation/options", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	tenant.Set(ctx, &rp)
+
+	c.StartAttestationHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Extensions map[string]any `json:"extensions"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]any{"appidExclude": rp.AppID}, response.Extensions)
+}
+
+// 要求 payment 擴充時一律改為 platform 驗證器、discoverable credential 與使用者驗證，並保存在 SessionData 中
+func TestStartAttestationHandler_Payment(t *testing.T) {
 	mockUC := mocks.NewMockUserUseCase(t)
-	c := NewAuthController(mockUC)
+	c := NewAuthController(mockUC, nil, nil, nil, session.NewMemoryStore(time.Minute))
+
+	body, _ := json.Marshal(dto.CredentialCreationOptionsRequest{
+		Username:               "testuser",
+		DisplayName:            "Test User",
+		AuthenticatorSelection: protocol.AuthenticatorSelection{AuthenticatorAttachment: protocol.CrossPlatform, UserVerification: protocol.VerificationDiscouraged},
+		Extensions:             dto.RegistrationExtensionsInput{Payment: true},
+	})
+
+	user := &entity.User{ID: "1", TenantID: "default", UserHandle: []byte("user-handle"), UserName: "testuser"}
+
+	mockUC.EXPECT().
+		ReserveUsername("default", "testuser", "Test User", tenant.Default().RegistrationTimeout).
+		Return(user, nil)
+
+	mockUC.EXPECT().
+		SetChallenge(user, mock.AnythingOfType("string"), tenant.Default().RegistrationTimeout).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/attestation/options", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.StartAttestationHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.CredentialCreationOptionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, protocol.Platform, response.AuthenticatorSelection.AuthenticatorAttachment)
+	assert.Equal(t, protocol.ResidentKeyRequirementRequired, response.AuthenticatorSelection.ResidentKey)
+	assert.Equal(t, protocol.VerificationRequired, response.AuthenticatorSelection.UserVerification)
+
+	sessionData, err := c.Sessions.Get(context.Background(), response.CeremonyID)
+	assert.NoError(t, err)
+	assert.Contains(t, sessionData.Extensions, "payment")
+}
+
+// 測試 ReserveUsername 失敗流程
+func TestStartAttestationHandler_ReserveUsernameFail(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
 
 	reqBody := dto.CredentialCreationOptionsRequest{
 		Username:    "testuser",
@@ -74,8 +220,8 @@ func TestStartAttestationHandler_CreateUserFail(t *testing.T) {
 	body, _ := json.Marshal(reqBody)
 
 	mockUC.EXPECT().
-		CreateUser(mock.AnythingOfType("*domain.User")).
-		Return(errors.New("db error"))
+		ReserveUsername("default", "testuser", "Test User", tenant.Default().RegistrationTimeout).
+		Return(nil, errors.New("db error"))
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
@@ -87,44 +233,204 @@ func TestStartAttestationHandler_CreateUserFail(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
-func TestFinishAttestationHandler_Success(t *testing.T) {
+// 未設定企業 Attestation 的租戶不可要求 enterprise，未知的偏好也應拒絕
+func TestStartAttestationHandler_InvalidAttestation(t *testing.T) {
+	tests := []struct {
+		attestation string
+		wantCode    string
+	}{
+		{attestation: "enterprise", wantCode: common.ErrorCodeEnterpriseAttestationNotAllowed},
+		{attestation: "always", wantCode: common.ErrorCodeInvalidAttestation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.attestation, func(t *testing.T) {
+			mockUC := mocks.NewMockUserUseCase(t)
+			c := NewAuthController(mockUC, nil, nil, nil, session.NewMemoryStore(time.Minute))
+
+			body, _ := json.Marshal(dto.CredentialCreationOptionsRequest{
+				Username:    "testuser",
+				DisplayName: "Test User",
+				Attestation: tt.attestation,
+			})
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/attestation/options", bytes.NewBuffer(body))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			c.StartAttestationHandler(ctx)
+
+			// 不應保留使用者名稱
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response common.CommonResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantCode, response.ErrorCode)
+		})
+	}
+}
+
+// 使用者名稱已被註冊或保留時回傳 username_taken
+func TestStartAttestationHandler_UsernameTaken(t *testing.T) {
 	mockUC := mocks.NewMockUserUseCase(t)
-	c := NewAuthController(mockUC)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
 
-	// 假設 attestationSessionData 全局變數先設好
-	attestationSessionData = &webauthn.SessionData{
-		Challenge: "test-challenge",
+	body, _ := json.Marshal(dto.CredentialCreationOptionsRequest{Username: "TestUser", DisplayName: "Test User"})
+
+	mockUC.EXPECT().
+		ReserveUsername("default", "TestUser", "Test User", tenant.Default().RegistrationTimeout).
+		Return(nil, repository.ErrUsernameTaken)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/attestation/options", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.StartAttestationHandler(ctx)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response common.CommonResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, common.ErrorCodeUsernameTaken, response.ErrorCode)
+}
+
+func TestStartAttestationHandler_InvalidUsername(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	body, _ := json.Marshal(dto.CredentialCreationOptionsRequest{Username: "   "})
+
+	mockUC.EXPECT().
+		ReserveUsername("default", "   ", "", tenant.Default().RegistrationTimeout).
+		Return(nil, username.ErrInvalidUsername)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/attestation/options", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.StartAttestationHandler(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response common.CommonResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, common.ErrorCodeInvalidUsername, response.ErrorCode)
+}
+
+func TestStartAddCredentialHandler_Success(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	router := gin.New()
+	router.POST("/credentials/attestation/options", NewTokenController(mockTokenUC).Authenticate, c.StartAddCredentialHandler)
+
+	user := &entity.User{ID: "user-1", TenantID: "default", UserHandle: []byte("user-handle"), UserName: "testuser"}
+	existing := &entity.Credential{ID: "Y3JlZC0x", UserID: "user-1"}
+
+	expectAccessToken(mockTokenUC)
+
+	mockUC.EXPECT().
+		GetUserByID("user-1").
+		Return(user, nil)
+
+	mockCredUC.EXPECT().
+		GetCredentialsByUserID("user-1").
+		Return([]*entity.Credential{existing}, nil)
+
+	mockUC.EXPECT().
+		SetChallenge(user, mock.AnythingOfType("string"), tenant.Default().RegistrationTimeout).
+		Return(nil)
+
+	body, _ := json.Marshal(dto.CredentialCreationOptionsRequest{})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest("POST", "/credentials/attestation/options", body))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		CeremonyID string `json:"ceremonyId"`
+		User       struct {
+			ID protocol.URLEncodedBase64 `json:"id"`
+		} `json:"user"`
+		ExcludeCredentials []struct {
+			ID protocol.URLEncodedBase64 `json:"id"`
+		} `json:"excludeCredentials"`
 	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.CeremonyID)
+	assert.Equal(t, protocol.URLEncodedBase64("user-handle"), response.User.ID)
+	assert.Len(t, response.ExcludeCredentials, 1)
+}
 
-	// 模擬輸入
-	clientData := map[string]interface{}{
-		"challenge": "test-challenge",
+func TestStartAddCredentialHandler_Unauthorized(t *testing.T) {
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(nil, nil, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	router := gin.New()
+	router.POST("/credentials/attestation/options", NewTokenController(mockTokenUC).Authenticate, c.StartAddCredentialHandler)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/credentials/attestation/options", bytes.NewBufferString("{}"))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestFinishAttestationHandler_Success(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	user := &entity.User{
+		ID:          "1",
+		UserHandle:  []byte("user-handle"),
+		UserName:    "testuser",
+		DisplayName: "Test User",
 	}
-	clientDataJSON, _ := json.Marshal(clientData)
+	authenticator := newVirtualAuthenticator(t)
 
+	// 以 Ceremony ID 存入 BeginRegistration 產生的 SessionData
+	_, sessionData, err := tenant.Default().WebAuthn.BeginRegistration(wAuth.NewUserWebAuthn(user, nil))
+	assert.NoError(t, err)
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", sessionData, time.Minute)
+
+	// 模擬輸入
 	req := dto.AuthenticatorAttestationResponseRequest{
-		Id: "fakeid",
-		Response: dto.AuthenticatorAttestationResponse{
-			AttestationObject: base64.RawURLEncoding.EncodeToString([]byte("fake")),
-			ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
-		},
-		GetClientExtensionResults: map[string]interface{}{},
+		CeremonyID:                "test-ceremony",
+		Id:                        authenticator.id(),
+		Response:                  authenticator.attestation(t, sessionData.Challenge),
+		GetClientExtensionResults: dto.ClientExtensionResults{},
 		Type:                      "public-key",
 	}
 	body, _ := json.Marshal(req)
 
 	// 模擬找到 user
 	mockUC.EXPECT().
-		GetUserByChallenge("test-challenge").
-		Return(&entity.User{
-			ID:          "1",
-			UserName:    "testuser",
-			DisplayName: "Test User",
-		}, nil)
+		ConsumeChallenge("default", sessionData.Challenge).
+		Return(user, nil)
 
-	// 模擬 UpdateUser 成功
+	mockCredUC.EXPECT().
+		GetCredentialsByUserID("1").
+		Return([]*entity.Credential{}, nil)
+
+	// 模擬 Credential 寫入與解除保留成功
 	mockUC.EXPECT().
-		UpdateUser(mock.AnythingOfType("*domain.User")).
+		CompleteRegistration(user, mock.AnythingOfType("*entity.Credential")).
+		Run(func(_ *entity.User, credential *entity.Credential) {
+			assert.Equal(t, authenticator.id(), credential.ID)
+			assert.Equal(t, "1", credential.UserID)
+			assert.Len(t, credential.PRFSalt, prfSaltLength)
+		}).
 		Return(nil)
 
 	w := httptest.NewRecorder()
@@ -135,35 +441,52 @@ func TestFinishAttestationHandler_Success(t *testing.T) {
 	c.FinishAttestationHandler(ctx)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 同一個 Ceremony 不可重複送出
+	w = httptest.NewRecorder()
+	ctx, _ = gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/attestation/result", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.FinishAttestationHandler(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response common.CommonResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, common.ErrorCodeChallengeExpired, response.ErrorCode)
 }
 
-// 測試 GetUserByChallenge 失敗
-func TestFinishAttestationHandler_GetUserByChallengeFail(t *testing.T) {
+// 測試 Challenge 已過期或已被使用過
+func TestFinishAttestationHandler_ChallengeExpired(t *testing.T) {
 	mockUC := mocks.NewMockUserUseCase(t)
-	c := NewAuthController(mockUC)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
 
-	attestationSessionData = &webauthn.SessionData{
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", &webauthn.SessionData{
 		Challenge: "test-challenge",
-	}
+	}, time.Minute)
 	clientData := map[string]interface{}{
 		"challenge": "test-challenge",
 	}
 	clientDataJSON, _ := json.Marshal(clientData)
 
 	req := dto.AuthenticatorAttestationResponseRequest{
-		Id: "fakeid",
+		CeremonyID: "test-ceremony",
+		Id:         "fakeid",
 		Response: dto.AuthenticatorAttestationResponse{
 			AttestationObject: base64.RawURLEncoding.EncodeToString([]byte("fake")),
 			ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
 		},
-		GetClientExtensionResults: map[string]interface{}{},
+		GetClientExtensionResults: dto.ClientExtensionResults{},
 		Type:                      "public-key",
 	}
 	body, _ := json.Marshal(req)
 
 	mockUC.EXPECT().
-		GetUserByChallenge("test-challenge").
-		Return(nil, errors.New("not found"))
+		ConsumeChallenge("default", "test-challenge").
+		Return(nil, repository.ErrChallengeExpired)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
@@ -172,5 +495,92 @@ func TestFinishAttestationHandler_GetUserByChallengeFail(t *testing.T) {
 
 	c.FinishAttestationHandler(ctx)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response common.CommonResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, common.ErrorCodeChallengeExpired, response.ErrorCode)
+}
+
+// 測試驗證器不符合租戶的驗證器條件
+func TestFinishAttestationHandler_PolicyViolation(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, mockTokenUC, nil, session.NewMemoryStore(time.Minute))
+
+	// 只接受可同步的 Credential (passkey)，虛擬驗證器的 Credential 不是 backup eligible
+	policy, err := wAuth.NewAuthenticatorPolicy(config.AuthenticatorPolicy{BackupEligibility: "required", AttestationTypes: []string{"none"}})
+	assert.NoError(t, err)
+	rp := *tenant.Default()
+	rp.AuthenticatorPolicy = policy
+
+	user := &entity.User{
+		ID:          "1",
+		UserHandle:  []byte("user-handle"),
+		UserName:    "testuser",
+		DisplayName: "Test User",
+	}
+	authenticator := newVirtualAuthenticator(t)
+
+	_, sessionData, err := rp.WebAuthn.BeginRegistration(wAuth.NewUserWebAuthn(user, nil))
+	assert.NoError(t, err)
+	_ = c.Sessions.Save(context.Background(), "test-ceremony", sessionData, time.Minute)
+
+	req := dto.AuthenticatorAttestationResponseRequest{
+		CeremonyID:                "test-ceremony",
+		Id:                        authenticator.id(),
+		Response:                  authenticator.attestation(t, sessionData.Challenge),
+		GetClientExtensionResults: dto.ClientExtensionResults{},
+		Type:                      "public-key",
+	}
+	body, _ := json.Marshal(req)
+
+	mockUC.EXPECT().
+		ConsumeChallenge("default", sessionData.Challenge).
+		Return(user, nil)
+
+	mockCredUC.EXPECT().
+		GetCredentialsByUserID("1").
+		Return([]*entity.Credential{}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/attestation/result", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	tenant.Set(ctx, &rp)
+
+	c.FinishAttestationHandler(ctx)
+
+	// 不應儲存 Credential 也不應完成註冊
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var response common.CommonResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, common.ErrorCodeBackupEligibilityNotAllowed, response.ErrorCode)
+}
+
+// Session 與註冊 Challenge 同樣依 RP 設定的註冊逾時保存
+func TestStartAttestationHandler_SessionTTL(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	sessions := session.NewMockSessionStore(t)
+	c := NewAuthController(mockUC, nil, nil, nil, sessions)
+
+	rp := *tenant.Default()
+	rp.RegistrationTimeout = 10 * time.Minute
+
+	user := &entity.User{ID: "1", TenantID: "default", UserHandle: []byte("user-handle"), UserName: "testuser"}
+	mockUC.EXPECT().ReserveUsername("default", "testuser", "Test User", 10*time.Minute).Return(user, nil)
+	mockUC.EXPECT().SetChallenge(user, mock.AnythingOfType("string"), 10*time.Minute).Return(nil)
+	sessions.EXPECT().Save(mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*webauthn.SessionData"), 10*time.Minute).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/attestation/options", bytes.NewBufferString(`{"username":"testuser","displayName":"Test User"}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	tenant.Set(ctx, &rp)
+
+	c.StartAttestationHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
 }
\ No newline at end of file
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fido2/internal/dto"
+	"fido2/internal/entity"
+	"fido2/internal/platform/tenant"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// virtualAuthenticator 測試用的軟體驗證器，產生可通過 go-webauthn 驗證的 attestation / assertion
+type virtualAuthenticator struct {
+	credentialID []byte
+	privateKey   *ecdsa.PrivateKey
+	aaguid       []byte
+	signCount    uint32
+	flags        protocol.AuthenticatorFlags
+
+	// appID 設定時以 AppID 計算 rpIdHash，模擬以 U2F 註冊的金鑰
+	appID string
+}
+
+func newVirtualAuthenticator(t *testing.T) *virtualAuthenticator {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("產生金鑰失敗: %v", err)
+	}
+
+	credentialID := make([]byte, 16)
+	_, _ = rand.Read(credentialID)
+
+	return &virtualAuthenticator{
+		credentialID: credentialID,
+		privateKey:   privateKey,
+		aaguid:       make([]byte, 16),
+		flags:        protocol.FlagUserPresent | protocol.FlagUserVerified,
+	}
+}
+
+// id 回傳 base64url 編碼的 Credential ID
+func (a *virtualAuthenticator) id() string {
+	return base64.RawURLEncoding.EncodeToString(a.credentialID)
+}
+
+// publicKey 回傳 COSE 格式的公鑰
+func (a *virtualAuthenticator) publicKey(t *testing.T) []byte {
+	t.Helper()
+
+	key := webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.privateKey.X.FillBytes(make([]byte, 32)),
+		YCoord: a.privateKey.Y.FillBytes(make([]byte, 32)),
+	}
+
+	encoded, err := webauthncbor.Marshal(key)
+	if err != nil {
+		t.Fatalf("COSE 編碼失敗: %v", err)
+	}
+	return encoded
+}
+
+// authData 組出 authenticator data，attested 為 true 時附上 attested credential data
+func (a *virtualAuthenticator) authData(t *testing.T, attested bool) []byte {
+	t.Helper()
+
+	rpIDHash := sha256.Sum256([]byte(tenant.Default().WebAuthn.Config.RPID))
+	if a.appID != "" {
+		rpIDHash = sha256.Sum256([]byte(a.appID))
+	}
+	flags := a.flags
+	if attested {
+		flags |= protocol.FlagAttestedCredentialData
+	}
+
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, byte(flags))
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+
+	if attested {
+		data = append(data, a.aaguid...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.publicKey(t)...)
+	}
+
+	return data
+}
+
+// clientData 組出 clientDataJSON
+func (a *virtualAuthenticator) clientData(ceremonyType protocol.CeremonyType, challenge string) []byte {
+	clientData, _ := json.Marshal(protocol.CollectedClientData{
+		Type:      ceremonyType,
+		Challenge: challenge,
+		Origin:    tenant.Default().WebAuthn.Config.RPOrigins[0],
+	})
+	return clientData
+}
+
+// attestation 產生 "none" 格式的 attestation 回應
+func (a *virtualAuthenticator) attestation(t *testing.T, challenge string) dto.AuthenticatorAttestationResponse {
+	t.Helper()
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(t, true),
+	})
+	if err != nil {
+		t.Fatalf("attestationObject 編碼失敗: %v", err)
+	}
+
+	return dto.AuthenticatorAttestationResponse{
+		AttestationObject: base64.RawURLEncoding.EncodeToString(attestationObject),
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(a.clientData(protocol.CreateCeremony, challenge)),
+	}
+}
+
+// assertion 產生簽章後的 assertion 回應，每次呼叫簽章計數器加一
+func (a *virtualAuthenticator) assertion(t *testing.T, challenge string, userHandle []byte) dto.AuthenticatorAssertionResponse {
+	t.Helper()
+
+	return a.assertionWithClientData(t, a.clientData(protocol.AssertCeremony, challenge), userHandle)
+}
+
+// assertionWithClientData 以指定的 clientDataJSON 產生簽章後的 assertion 回應 (例如 payment.get)
+func (a *virtualAuthenticator) assertionWithClientData(t *testing.T, clientData []byte, userHandle []byte) dto.AuthenticatorAssertionResponse {
+	t.Helper()
+
+	a.signCount++
+	authData := a.authData(t, false)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.privateKey, digest[:])
+	if err != nil {
+		t.Fatalf("簽章失敗: %v", err)
+	}
+
+	return dto.AuthenticatorAssertionResponse{
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientData),
+		Signature:         base64.RawURLEncoding.EncodeToString(signature),
+		UserHandle:        base64.RawURLEncoding.EncodeToString(userHandle),
+	}
+}
+
+// storedCredential 回傳此驗證器註冊後會儲存的 Credential
+func (a *virtualAuthenticator) storedCredential(t *testing.T, userID string) *entity.Credential {
+	t.Helper()
+
+	return &entity.Credential{
+		ID:              a.id(),
+		UserID:          userID,
+		PublicKey:       a.publicKey(t),
+		AAGUID:          a.aaguid,
+		SignCount:       a.signCount,
+		UserPresent:     true,
+		UserVerified:    true,
+		AttestationType: "none",
+	}
+}
\ No newline at end of file
@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"errors"
+	"fido2/internal/dto"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/internal/repository"
+	"fido2/internal/usecase"
+	"fido2/pkg/utils"
+	"fido2/pkg/utils/common"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+type CredentialController struct {
+	UserUC       usecase.UserUseCase
+	CredentialUC usecase.CredentialUseCase
+	TokenUC      usecase.TokenUseCase
+}
+
+func NewCredentialController(u usecase.UserUseCase, cr usecase.CredentialUseCase, t usecase.TokenUseCase) *CredentialController {
+	return &CredentialController{UserUC: u, CredentialUC: cr, TokenUC: t}
+}
+
+// ListCredentialsHandler 列出目前登入使用者的所有 Passkey
+func (c *CredentialController) ListCredentialsHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("ListCredentialsHandler called")
+
+	claims := accessClaims(ctx)
+
+	credentials, err := c.CredentialUC.GetCredentialsByUserID(claims.Subject)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user credentials, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	infos := make([]dto.CredentialInfo, 0, len(credentials))
+	for _, credential := range credentials {
+		infos = append(infos, dto.CredentialInfo{
+			ID:                 credential.ID,
+			Nickname:           credential.Nickname,
+			AAGUID:             wAuth.FormatAAGUID(credential.AAGUID),
+			AuthenticatorName:  wAuth.AuthenticatorName(credential.AAGUID),
+			Attachment:         credential.Attachment,
+			Transports:         credential.Transports,
+			BackupEligible:     credential.BackupEligible,
+			BackupState:        credential.BackupState,
+			Discoverable:       credential.Discoverable,
+			PRFEnabled:         credential.PRFEnabled,
+			LargeBlobSupported: credential.LargeBlobSupported,
+			Payment:            credential.Payment,
+			CreatedAt:          credential.CreatedAt,
+			LastUsedAt:         credential.LastUsedAt,
+			Current:            credential.ID == claims.CredentialID,
+		})
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		dto.CredentialListResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			Credentials: infos,
+		},
+	)
+}
+
+// RenameCredentialHandler 修改目前登入使用者的 Passkey 名稱
+func (c *CredentialController) RenameCredentialHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("RenameCredentialHandler called")
+
+	claims := accessClaims(ctx)
+
+	var request *dto.RenameCredentialRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to parse request body, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	if err := c.CredentialUC.RenameCredential(claims.Subject, ctx.Param("id"), strings.TrimSpace(request.Nickname)); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrCredentialNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(
+			status,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to rename credential, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		common.CommonResponse{
+			Status:       "ok",
+			ErrorMessage: "",
+		},
+	)
+}
+
+// DeleteCredentialHandler 刪除目前登入使用者的 Passkey，並撤銷以該 Passkey 登入的 Refresh Token
+// 沒有備援方式時不可刪除最後一個 Passkey，避免使用者無法再登入
+func (c *CredentialController) DeleteCredentialHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("DeleteCredentialHandler called")
+
+	claims := accessClaims(ctx)
+	credentialID := ctx.Param("id")
+
+	user, err := c.UserUC.GetUserByID(claims.Subject)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user, error: " + err.Error(),
+			},
+		)
+		return
+	}
+	if user == nil {
+		ctx.JSON(
+			http.StatusUnauthorized,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user, error: " + errUserNotFound.Error(),
+			},
+		)
+		return
+	}
+
+	if err := c.CredentialUC.DeleteUserCredential(user, credentialID); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, repository.ErrCredentialNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, repository.ErrLastCredential):
+			status = http.StatusConflict
+		}
+		ctx.JSON(
+			status,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to delete credential, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	if err := c.TokenUC.RevokeCredentialTokens(credentialID); err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to revoke credential tokens, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	utils.GetLogger().Infof("User %s deleted credential ID: %s", user.ID, credentialID)
+
+	ctx.JSON(
+		http.StatusOK,
+		common.CommonResponse{
+			Status:       "ok",
+			ErrorMessage: "",
+		},
+	)
+}
\ No newline at end of file
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fido2/internal/dto"
+	"fido2/internal/entity"
+	"fido2/internal/platform/token"
+	"fido2/internal/repository"
+	mocks "fido2/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newCredentialRouter 建立掛載 Access Token 驗證的 Passkey 管理路由
+func newCredentialRouter(c *CredentialController, tokenUC *mocks.MockTokenUseCase) *gin.Engine {
+	router := gin.New()
+	cred := router.Group("/credentials", NewTokenController(tokenUC).Authenticate)
+	cred.GET("", c.ListCredentialsHandler)
+	cred.PATCH("/:id", c.RenameCredentialHandler)
+	cred.DELETE("/:id", c.DeleteCredentialHandler)
+	return router
+}
+
+func authorizedRequest(method, path string, body []byte) *http.Request {
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer access")
+	return req
+}
+
+// testAccessClaims 以 Y3JlZC0x 登入的 user-1
+func testAccessClaims() *token.AccessClaims {
+	return &token.AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1"},
+		CredentialID:     "Y3JlZC0x",
+		TenantID:         "default",
+	}
+}
+
+func expectAccessToken(tokenUC *mocks.MockTokenUseCase) {
+	tokenUC.EXPECT().VerifyAccessToken("default", "access").Return(testAccessClaims(), nil)
+}
+
+func TestCredentialsHandler_Unauthorized(t *testing.T) {
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	router := newCredentialRouter(NewCredentialController(nil, nil, mockTokenUC), mockTokenUC)
+
+	// 缺少 Access Token
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/credentials", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Access Token 無效
+	mockTokenUC.EXPECT().VerifyAccessToken("default", "access").Return(nil, token.ErrInvalidAccessToken)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest("GET", "/credentials", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestListCredentialsHandler(t *testing.T) {
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	router := newCredentialRouter(NewCredentialController(nil, mockCredUC, mockTokenUC), mockTokenUC)
+
+	expectAccessToken(mockTokenUC)
+
+	iCloud := uuid.MustParse("fbfc3007-154e-4ecc-8c0b-6e020557d7bd")
+	lastUsed := time.Now()
+	mockCredUC.EXPECT().
+		GetCredentialsByUserID("user-1").
+		Return([]*entity.Credential{
+			{ID: "Y3JlZC0x", AAGUID: iCloud[:], Transports: []string{"internal", "hybrid"}, BackupEligible: true, BackupState: true, LastUsedAt: &lastUsed},
+			{ID: "Y3JlZC0y", Nickname: "Office key", AAGUID: make([]byte, 16), Transports: []string{"usb"}},
+		}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest("GET", "/credentials", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.CredentialListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Credentials, 2)
+	assert.Equal(t, "iCloud Keychain", response.Credentials[0].AuthenticatorName)
+	assert.Equal(t, iCloud.String(), response.Credentials[0].AAGUID)
+	assert.True(t, response.Credentials[0].BackupState)
+	assert.True(t, response.Credentials[0].Current)
+	assert.NotNil(t, response.Credentials[0].LastUsedAt)
+	assert.Equal(t, "Office key", response.Credentials[1].Nickname)
+	assert.Empty(t, response.Credentials[1].AAGUID)
+	assert.False(t, response.Credentials[1].Current)
+}
+
+func TestRenameCredentialHandler(t *testing.T) {
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	router := newCredentialRouter(NewCredentialController(nil, mockCredUC, mockTokenUC), mockTokenUC)
+	expectAccessToken(mockTokenUC)
+
+	mockCredUC.EXPECT().RenameCredential("user-1", "Y3JlZC0x", "My phone").Return(nil)
+	mockCredUC.EXPECT().RenameCredential("user-1", "b3RoZXI", "My phone").Return(repository.ErrCredentialNotFound)
+
+	body, _ := json.Marshal(dto.RenameCredentialRequest{Nickname: "  My phone "})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest("PATCH", "/credentials/Y3JlZC0x", body))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 其他使用者的 Credential
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest("PATCH", "/credentials/b3RoZXI", body))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeleteCredentialHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"成功並撤銷 Token", nil, http.StatusOK},
+		{"最後一個 Credential", repository.ErrLastCredential, http.StatusConflict},
+		{"其他使用者的 Credential", repository.ErrCredentialNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := mocks.NewMockUserUseCase(t)
+			mockCredUC := mocks.NewMockCredentialUseCase(t)
+			mockTokenUC := mocks.NewMockTokenUseCase(t)
+			router := newCredentialRouter(NewCredentialController(mockUC, mockCredUC, mockTokenUC), mockTokenUC)
+			expectAccessToken(mockTokenUC)
+
+			user := &entity.User{ID: "user-1", TenantID: "default"}
+			mockUC.EXPECT().GetUserByID("user-1").Return(user, nil)
+			mockCredUC.EXPECT().DeleteUserCredential(user, "Y3JlZC0y").Return(tt.err)
+			if tt.err == nil {
+				mockTokenUC.EXPECT().RevokeCredentialTokens("Y3JlZC0y").Return(nil)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authorizedRequest("DELETE", "/credentials/Y3JlZC0y", nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
\ No newline at end of file
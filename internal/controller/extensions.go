@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"crypto/rand"
+	"errors"
+	"fido2/internal/dto"
+	"fido2/internal/entity"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/pkg/utils/common"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"maps"
+	"net/http"
+)
+
+// prfSaltLength 每個 Credential 的 PRF salt 長度 (bytes)
+const prfSaltLength = 32
+
+var (
+	// errExtensionRequiresUser PRF 與 largeBlob 寫入需要 allowCredentials，無使用者名稱的登入不支援
+	errExtensionRequiresUser = errors.New("prf and largeBlob write require a username")
+
+	// errInvalidLargeBlobSupport 註冊時 largeBlob 只能指定 support
+	errInvalidLargeBlobSupport = errors.New("largeBlob at registration only accepts support required or preferred")
+
+	// errInvalidLargeBlobOperation 登入時 largeBlob 只能讀取或寫入其中一種
+	errInvalidLargeBlobOperation = errors.New("largeBlob at authentication accepts either read or write")
+)
+
+// registrationExtensions 將註冊請求的擴充轉為 Credential Creation Options 中的 extensions
+// required 為租戶驗證器條件要求的擴充 (credProtect / minPinLength)，一律附加且不可由請求覆寫
+func registrationExtensions(input dto.RegistrationExtensionsInput, required protocol.AuthenticationExtensions) (protocol.AuthenticationExtensions, error) {
+	extensions := protocol.AuthenticationExtensions{}
+	if input.CredProps {
+		extensions["credProps"] = true
+	}
+	if input.PRF {
+		extensions["prf"] = map[string]any{}
+	}
+	if input.Payment {
+		extensions[wAuth.PaymentExtension] = map[string]any{"isPayment": true}
+	}
+	if input.LargeBlob != nil {
+		if input.LargeBlob.Read || len(input.LargeBlob.Write) > 0 {
+			return nil, errInvalidLargeBlobSupport
+		}
+		switch input.LargeBlob.Support {
+		case "required", "preferred":
+			extensions["largeBlob"] = map[string]any{"support": input.LargeBlob.Support}
+		default:
+			return nil, errInvalidLargeBlobSupport
+		}
+	}
+	maps.Copy(extensions, required)
+	if len(extensions) == 0 {
+		return nil, nil
+	}
+	return extensions, nil
+}
+
+// assertionExtensions 將登入請求的擴充轉為 Credential Request Options 中的 extensions
+// credentials 為 allowCredentials 中的 Credential，無使用者名稱的登入為 nil
+func assertionExtensions(input dto.AuthenticationExtensionsInput, credentials []*entity.Credential) (protocol.AuthenticationExtensions, error) {
+	extensions := protocol.AuthenticationExtensions{}
+	if input.PRF {
+		if credentials == nil {
+			return nil, errExtensionRequiresUser
+		}
+		evalByCredential := make(map[string]any, len(credentials))
+		for _, credential := range credentials {
+			if len(credential.PRFSalt) > 0 {
+				evalByCredential[credential.ID] = map[string]any{"first": protocol.URLEncodedBase64(credential.PRFSalt)}
+			}
+		}
+		extensions["prf"] = map[string]any{"evalByCredential": evalByCredential}
+	}
+	if input.LargeBlob != nil {
+		switch {
+		case input.LargeBlob.Support != "" || input.LargeBlob.Read == (len(input.LargeBlob.Write) > 0):
+			return nil, errInvalidLargeBlobOperation
+		case input.LargeBlob.Read:
+			extensions["largeBlob"] = map[string]any{"read": true}
+		case credentials == nil:
+			return nil, errExtensionRequiresUser
+		default:
+			extensions["largeBlob"] = map[string]any{"write": input.LargeBlob.Write}
+		}
+	}
+	if len(extensions) == 0 {
+		return nil, nil
+	}
+	return extensions, nil
+}
+
+// invalidExtensionResponse 回傳擴充不合法的錯誤
+func invalidExtensionResponse(ctx *gin.Context, err error) {
+	ctx.JSON(
+		http.StatusBadRequest,
+		common.CommonResponse{
+			Status:       "failed",
+			ErrorMessage: "invalid extensions, error: " + err.Error(),
+			ErrorCode:    common.ErrorCodeInvalidExtension,
+		},
+	)
+}
+
+// applyRegistrationExtensionResults 將註冊時的擴充結果記錄在 Credential 上，並產生此 Credential 專屬的 PRF salt
+// 部分驗證器只在登入時才回報 PRF，因此每個 Credential 都會產生 salt
+func applyRegistrationExtensionResults(credential *entity.Credential, results dto.ClientExtensionResults) error {
+	if results.CredProps != nil {
+		credential.Discoverable = results.CredProps.ResidentKey
+	}
+	if results.PRF != nil && results.PRF.Enabled != nil {
+		credential.PRFEnabled = *results.PRF.Enabled
+	}
+	if results.LargeBlob != nil && results.LargeBlob.Supported != nil {
+		credential.LargeBlobSupported = *results.LargeBlob.Supported
+	}
+
+	credential.PRFSalt = make([]byte, prfSaltLength)
+	_, err := rand.Read(credential.PRFSalt)
+	return err
+}
+
+// assertionExtensionUpdate 依登入時的擴充結果產生要寫回 Credential 的欄位
+func assertionExtensionUpdate(results dto.ClientExtensionResults) map[string]interface{} {
+	update := map[string]interface{}{}
+	if results.PRF != nil && results.PRF.Results != nil {
+		update["prf_enabled"] = true
+	}
+	if results.LargeBlob != nil && results.LargeBlob.Written != nil && *results.LargeBlob.Written {
+		update["large_blob_supported"] = true
+	}
+	return update
+}
\ No newline at end of file
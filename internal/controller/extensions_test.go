@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"encoding/json"
+	"fido2/internal/dto"
+	"fido2/internal/entity"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRegistrationExtensions(t *testing.T) {
+	extensions, err := registrationExtensions(dto.RegistrationExtensionsInput{
+		CredProps: true,
+		PRF:       true,
+		LargeBlob: &dto.LargeBlobInput{Support: "preferred"},
+		Payment:   true,
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, true, extensions["credProps"])
+	assert.Equal(t, map[string]any{}, extensions["prf"])
+	assert.Equal(t, map[string]any{"support": "preferred"}, extensions["largeBlob"])
+	assert.Equal(t, map[string]any{"isPayment": true}, extensions["payment"])
+
+	// 未要求任何擴充時不輸出 extensions
+	extensions, err = registrationExtensions(dto.RegistrationExtensionsInput{}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, extensions)
+
+	// 註冊時不可讀寫 largeBlob
+	_, err = registrationExtensions(dto.RegistrationExtensionsInput{LargeBlob: &dto.LargeBlobInput{Read: true}}, nil)
+	assert.ErrorIs(t, err, errInvalidLargeBlobSupport)
+
+	_, err = registrationExtensions(dto.RegistrationExtensionsInput{LargeBlob: &dto.LargeBlobInput{Support: "always"}}, nil)
+	assert.ErrorIs(t, err, errInvalidLargeBlobSupport)
+
+	// 租戶要求的擴充一律附加
+	extensions, err = registrationExtensions(dto.RegistrationExtensionsInput{}, protocol.AuthenticationExtensions{"minPinLength": true})
+	assert.NoError(t, err)
+	assert.Equal(t, true, extensions["minPinLength"])
+}
+
+func TestAssertionExtensions(t *testing.T) {
+	credentials := []*entity.Credential{
+		{ID: "Y3JlZC0x", PRFSalt: []byte("salt-1")},
+		{ID: "bGVnYWN5"},
+	}
+
+	extensions, err := assertionExtensions(dto.AuthenticationExtensionsInput{
+		PRF:       true,
+		LargeBlob: &dto.LargeBlobInput{Write: []byte("notes")},
+	}, credentials)
+	assert.NoError(t, err)
+
+	// 只有產生過 salt 的 Credential 才會出現在 evalByCredential
+	body, _ := json.Marshal(extensions)
+	assert.JSONEq(t, `{
+		"prf": {"evalByCredential": {"Y3JlZC0x": {"first": "c2FsdC0x"}}},
+		"largeBlob": {"write": "bm90ZXM"}
+	}`, string(body))
+
+	// 無使用者名稱時只能讀取 largeBlob
+	extensions, err = assertionExtensions(dto.AuthenticationExtensionsInput{LargeBlob: &dto.LargeBlobInput{Read: true}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"read": true}, extensions["largeBlob"])
+
+	_, err = assertionExtensions(dto.AuthenticationExtensionsInput{PRF: true}, nil)
+	assert.ErrorIs(t, err, errExtensionRequiresUser)
+
+	_, err = assertionExtensions(dto.AuthenticationExtensionsInput{LargeBlob: &dto.LargeBlobInput{Write: []byte("notes")}}, nil)
+	assert.ErrorIs(t, err, errExtensionRequiresUser)
+
+	// 讀取與寫入只能擇一
+	_, err = assertionExtensions(dto.AuthenticationExtensionsInput{LargeBlob: &dto.LargeBlobInput{Read: true, Write: []byte("notes")}}, credentials)
+	assert.ErrorIs(t, err, errInvalidLargeBlobOperation)
+}
+
+func TestApplyRegistrationExtensionResults(t *testing.T) {
+	var results dto.ClientExtensionResults
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"credProps": {"rk": true},
+		"prf": {"enabled": true},
+		"largeBlob": {"supported": false},
+		"unknown": {"value": 1}
+	}`), &results))
+
+	credential := &entity.Credential{}
+	assert.NoError(t, applyRegistrationExtensionResults(credential, results))
+
+	assert.NotNil(t, credential.Discoverable)
+	assert.True(t, *credential.Discoverable)
+	assert.True(t, credential.PRFEnabled)
+	assert.False(t, credential.LargeBlobSupported)
+	assert.Len(t, credential.PRFSalt, prfSaltLength)
+
+	// 未回報 credProps 時維持未知
+	credential = &entity.Credential{}
+	assert.NoError(t, applyRegistrationExtensionResults(credential, dto.ClientExtensionResults{}))
+	assert.Nil(t, credential.Discoverable)
+}
+
+func TestAssertionExtensionUpdate(t *testing.T) {
+	var results dto.ClientExtensionResults
+	assert.NoError(t, json.Unmarshal([]byte(`{"prf": {"results": {"first": "c2VjcmV0"}}, "largeBlob": {"written": true}}`), &results))
+
+	assert.Equal(t, map[string]interface{}{"prf_enabled": true, "large_blob_supported": true}, assertionExtensionUpdate(results))
+	assert.Empty(t, assertionExtensionUpdate(dto.ClientExtensionResults{}))
+
+	// PRF 輸出為用戶端秘密，不應轉交給 go-webauthn 或寫入紀錄
+	body, _ := json.Marshal(results.ToProtocol())
+	assert.NotContains(t, string(body), "c2VjcmV0")
+	assert.IsType(t, protocol.AuthenticationExtensionsClientOutputs{}, results.ToProtocol())
+}
\ No newline at end of file
@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"fido2/internal/platform/tenant"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// 初始化 WebAuthn RP 伺服器供 handler 使用
+	_ = os.Setenv("RP_ID", "localhost")
+	_ = os.Setenv("RP_ORIGINS", "http://localhost:3000")
+	tenant.Init()
+	os.Exit(m.Run())
+}
\ No newline at end of file
@@ -0,0 +1,245 @@
+package controller
+
+import (
+	_ "embed"
+	"errors"
+	"fido2/internal/dto"
+	"fido2/internal/entity"
+	"fido2/internal/platform/oidc"
+	"fido2/internal/platform/tenant"
+	"fido2/internal/platform/token"
+	"fido2/internal/usecase"
+	"fido2/pkg/utils"
+	"fido2/pkg/utils/common"
+	"github.com/gin-gonic/gin"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//go:embed templates/authorize.html
+var authorizePage string
+
+// authorizeTemplate /authorize 頁面，以既有的註冊與登入 API 完成 Passkey 驗證
+var authorizeTemplate = template.Must(template.New("authorize").Parse(authorizePage))
+
+type OIDCController struct {
+	OIDCUC usecase.OIDCUseCase
+}
+
+func NewOIDCController(o usecase.OIDCUseCase) *OIDCController {
+	return &OIDCController{OIDCUC: o}
+}
+
+// DiscoveryHandler OpenID Provider 設定 (/.well-known/openid-configuration)
+func (c *OIDCController) DiscoveryHandler(ctx *gin.Context) {
+	issuer := tenant.FromContext(ctx).Issuer
+
+	ctx.JSON(
+		http.StatusOK,
+		dto.OpenIDConfiguration{
+			Issuer:                            issuer,
+			AuthorizationEndpoint:             issuer + "/authorize",
+			TokenEndpoint:                     issuer + "/token",
+			UserInfoEndpoint:                  issuer + "/userinfo",
+			JWKSURI:                           issuer + "/.well-known/jwks.json",
+			ResponseTypesSupported:            []string{oidc.ResponseTypeCode},
+			GrantTypesSupported:               []string{oidc.GrantTypeAuthorizationCode},
+			SubjectTypesSupported:             []string{"public"},
+			IDTokenSigningAlgValuesSupported:  []string{token.AlgES256, token.AlgEdDSA},
+			ScopesSupported:                   []string{oidc.ScopeOpenID, oidc.ScopeProfile},
+			TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+			CodeChallengeMethodsSupported:     []string{oidc.CodeChallengeMethodS256},
+			ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "amr", "acr", "preferred_username", "name"},
+		},
+	)
+}
+
+// AuthorizeHandler OIDC 授權端點
+// 驗證參數後回傳登入頁面，頁面透過 /attestation 與 /assertion 完成 Passkey 驗證後導回 Client
+func (c *OIDCController) AuthorizeHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("AuthorizeHandler called")
+
+	rp := tenant.FromContext(ctx)
+
+	var request dto.AuthorizeRequest
+
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to parse authorization request, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	authRequest := &entity.AuthorizationRequest{
+		TenantID:            rp.ID,
+		ClientID:            request.ClientID,
+		RedirectURI:         request.RedirectURI,
+		Scope:               request.Scope,
+		State:               request.State,
+		Nonce:               request.Nonce,
+		CodeChallenge:       request.CodeChallenge,
+		CodeChallengeMethod: request.CodeChallengeMethod,
+	}
+
+	client, err := c.OIDCUC.StartAuthorization(authRequest, request.ResponseType)
+
+	var oauthErr *oidc.Error
+	switch {
+	case errors.Is(err, oidc.ErrUnknownClient), errors.Is(err, oidc.ErrInvalidRedirectURI):
+		// client_id 或 redirect_uri 無效時不可導回，避免成為 open redirect
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "invalid authorization request, error: " + err.Error(),
+			},
+		)
+		return
+	case errors.As(err, &oauthErr):
+		location, err := oidc.BuildRedirectURI(request.RedirectURI, url.Values{
+			"error":             {oauthErr.Code},
+			"error_description": {oauthErr.Description},
+			"state":             {request.State},
+		})
+		if err != nil {
+			ctx.JSON(
+				http.StatusBadRequest,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "invalid redirect_uri, error: " + err.Error(),
+				},
+			)
+			return
+		}
+		ctx.Redirect(http.StatusFound, location)
+		return
+	case err != nil:
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to start authorization, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("X-Frame-Options", "DENY")
+	ctx.Header("Content-Security-Policy", "frame-ancestors 'none'")
+	ctx.Header("Content-Type", "text/html; charset=utf-8")
+	ctx.Status(http.StatusOK)
+
+	if err := authorizeTemplate.Execute(ctx.Writer, map[string]string{
+		"RequestID":  authRequest.ID,
+		"ClientName": client.Name,
+		"RPName":     rp.WebAuthn.Config.RPDisplayName,
+	}); err != nil {
+		utils.GetLogger().Errorf("failed to render authorize page: %v", err)
+	}
+}
+
+// TokenHandler OIDC Token 端點，以 Authorization Code 與 PKCE code_verifier 換發 Token
+func (c *OIDCController) TokenHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("TokenHandler called")
+
+	rp := tenant.FromContext(ctx)
+
+	var request dto.OIDCTokenRequest
+
+	if err := ctx.ShouldBind(&request); err != nil {
+		oauthError(ctx, oidc.NewError(oidc.ErrorInvalidRequest, err.Error()))
+		return
+	}
+
+	if request.GrantType != oidc.GrantTypeAuthorizationCode {
+		oauthError(ctx, oidc.NewError(oidc.ErrorUnsupportedGrantType, "only grant_type=authorization_code is supported"))
+		return
+	}
+
+	// client_secret_basic 優先，其次為 client_secret_post 或 Public Client 的 client_id
+	clientID, clientSecret := request.ClientID, request.ClientSecret
+	if username, password, ok := ctx.Request.BasicAuth(); ok {
+		clientID, _ = url.QueryUnescape(username)
+		clientSecret, _ = url.QueryUnescape(password)
+	}
+
+	tokens, err := c.OIDCUC.ExchangeCode(rp.Issuer, rp.ID, clientID, clientSecret, request.Code, request.RedirectURI, request.CodeVerifier)
+	if err != nil {
+		oauthError(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+	ctx.JSON(
+		http.StatusOK,
+		dto.OIDCTokenResponse{
+			AccessToken:  tokens.AccessToken,
+			TokenType:    "Bearer",
+			ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+			RefreshToken: tokens.RefreshToken,
+			IDToken:      tokens.IDToken,
+			Scope:        tokens.Scope,
+		},
+	)
+}
+
+// UserInfoHandler OIDC UserInfo 端點，以 Bearer Access Token 取得使用者資料
+func (c *OIDCController) UserInfoHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("UserInfoHandler called")
+
+	rp := tenant.FromContext(ctx)
+
+	accessToken, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if !ok || accessToken == "" {
+		ctx.Header("WWW-Authenticate", `Bearer`)
+		ctx.JSON(
+			http.StatusUnauthorized,
+			dto.OAuthErrorResponse{Error: oidc.ErrorInvalidToken, ErrorDescription: "bearer access token is required"},
+		)
+		return
+	}
+
+	userInfo, err := c.OIDCUC.GetUserInfo(rp.ID, accessToken)
+	if err != nil {
+		oauthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, userInfo)
+}
+
+// oauthError 以 RFC 6749 的格式回傳錯誤
+func oauthError(ctx *gin.Context, err error) {
+	var oauthErr *oidc.Error
+	if !errors.As(err, &oauthErr) {
+		utils.GetLogger().Errorf("OIDC request failed: %v", err)
+		ctx.JSON(
+			http.StatusInternalServerError,
+			dto.OAuthErrorResponse{Error: "server_error"},
+		)
+		return
+	}
+
+	status := http.StatusBadRequest
+	switch oauthErr.Code {
+	case oidc.ErrorInvalidClient:
+		status = http.StatusUnauthorized
+		ctx.Header("WWW-Authenticate", `Basic realm="token"`)
+	case oidc.ErrorInvalidToken:
+		status = http.StatusUnauthorized
+		ctx.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+	}
+
+	ctx.JSON(
+		status,
+		dto.OAuthErrorResponse{Error: oauthErr.Code, ErrorDescription: oauthErr.Description},
+	)
+}
\ No newline at end of file
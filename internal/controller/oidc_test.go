@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fido2/internal/dto"
+	"fido2/internal/entity"
+	"fido2/internal/platform/oidc"
+	"fido2/internal/platform/token"
+	mocks "fido2/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscoveryHandler(t *testing.T) {
+	c := NewOIDCController(mocks.NewMockOIDCUseCase(t))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/.well-known/openid-configuration", nil)
+
+	c.DiscoveryHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var config dto.OpenIDConfiguration
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &config))
+	assert.Equal(t, "http://localhost:3000", config.Issuer)
+	assert.Equal(t, "http://localhost:3000/token", config.TokenEndpoint)
+	assert.Equal(t, "http://localhost:3000/.well-known/jwks.json", config.JWKSURI)
+	assert.Equal(t, []string{"S256"}, config.CodeChallengeMethodsSupported)
+}
+
+func authorizeQuery() url.Values {
+	return url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"client-1"},
+		"redirect_uri":          {"https://app.example.com/callback"},
+		"scope":                 {"openid profile"},
+		"state":                 {"xyz"},
+		"code_challenge":        {"challenge"},
+		"code_challenge_method": {"S256"},
+	}
+}
+
+func TestAuthorizeHandler_Success(t *testing.T) {
+	mockOIDCUC := mocks.NewMockOIDCUseCase(t)
+	c := NewOIDCController(mockOIDCUC)
+
+	mockOIDCUC.EXPECT().
+		StartAuthorization(mock.Anything, "code").
+		Run(func(request *entity.AuthorizationRequest, _ string) {
+			assert.Equal(t, "default", request.TenantID)
+			assert.Equal(t, "client-1", request.ClientID)
+			assert.Equal(t, "xyz", request.State)
+			request.ID = "request-1"
+		}).
+		Return(&entity.OIDCClient{ID: "client-1", Name: "<Example App>"}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/authorize?"+authorizeQuery().Encode(), nil)
+
+	c.AuthorizeHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Contains(t, w.Body.String(), `"request-1"`)
+	// Client 名稱需跳脫
+	assert.Contains(t, w.Body.String(), "&lt;Example App&gt;")
+}
+
+func TestAuthorizeHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{"未知的 Client 不導回", oidc.ErrUnknownClient, http.StatusBadRequest, ""},
+		{"未註冊的 redirect_uri 不導回", oidc.ErrInvalidRedirectURI, http.StatusBadRequest, ""},
+		{"其他參數錯誤導回 Client", oidc.NewError(oidc.ErrorInvalidScope, "scope must include openid"), http.StatusFound, oidc.ErrorInvalidScope},
+		{"伺服器錯誤", errors.New("db down"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOIDCUC := mocks.NewMockOIDCUseCase(t)
+			c := NewOIDCController(mockOIDCUC)
+
+			mockOIDCUC.EXPECT().StartAuthorization(mock.Anything, "code").Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("GET", "/authorize?"+authorizeQuery().Encode(), nil)
+
+			c.AuthorizeHandler(ctx)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				location, _ := url.Parse(w.Header().Get("Location"))
+				assert.Equal(t, "app.example.com", location.Host)
+				assert.Equal(t, tt.wantError, location.Query().Get("error"))
+				assert.Equal(t, "xyz", location.Query().Get("state"))
+			}
+		})
+	}
+}
+
+func TestTokenHandler_Success(t *testing.T) {
+	mockOIDCUC := mocks.NewMockOIDCUseCase(t)
+	c := NewOIDCController(mockOIDCUC)
+
+	mockOIDCUC.EXPECT().
+		ExchangeCode("http://localhost:3000", "default", "client-1", "secret", "code", "https://app.example.com/callback", "verifier").
+		Return(&oidc.Tokens{
+			Pair:    &token.Pair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: time.Minute},
+			IDToken: "id-token",
+			Scope:   "openid",
+		}, nil)
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {"code"},
+		"redirect_uri":  {"https://app.example.com/callback"},
+		"code_verifier": {"verifier"},
+	}
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/token", strings.NewReader(form.Encode()))
+	ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx.Request.SetBasicAuth("client-1", "secret")
+
+	c.TokenHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var response dto.OIDCTokenResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "id-token", response.IDToken)
+	assert.Equal(t, "access", response.AccessToken)
+	assert.Equal(t, 60, response.ExpiresIn)
+}
+
+func TestTokenHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		grantType  string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{"不支援的 grant_type", "password", nil, http.StatusBadRequest, oidc.ErrorUnsupportedGrantType},
+		{"無效的 Code", "authorization_code", oidc.NewError(oidc.ErrorInvalidGrant, "authorization code is invalid"), http.StatusBadRequest, oidc.ErrorInvalidGrant},
+		{"Client 驗證失敗", "authorization_code", oidc.NewError(oidc.ErrorInvalidClient, "client authentication failed"), http.StatusUnauthorized, oidc.ErrorInvalidClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOIDCUC := mocks.NewMockOIDCUseCase(t)
+			c := NewOIDCController(mockOIDCUC)
+
+			if tt.err != nil {
+				mockOIDCUC.EXPECT().
+					ExchangeCode(mock.Anything, "default", "client-1", "", "code", mock.Anything, mock.Anything).
+					Return(nil, tt.err)
+			}
+
+			form := url.Values{"grant_type": {tt.grantType}, "code": {"code"}, "client_id": {"client-1"}}
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/token", strings.NewReader(form.Encode()))
+			ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			c.TokenHandler(ctx)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response dto.OAuthErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantError, response.Error)
+		})
+	}
+}
+
+func TestUserInfoHandler(t *testing.T) {
+	mockOIDCUC := mocks.NewMockOIDCUseCase(t)
+	c := NewOIDCController(mockOIDCUC)
+
+	mockOIDCUC.EXPECT().
+		GetUserInfo("default", "access").
+		Return(&oidc.UserInfo{Subject: "user-1", PreferredUsername: "alice"}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/userinfo", nil)
+	ctx.Request.Header.Set("Authorization", "Bearer access")
+
+	c.UserInfoHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var userInfo oidc.UserInfo
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &userInfo))
+	assert.Equal(t, "user-1", userInfo.Subject)
+}
+
+func TestUserInfoHandler_MissingToken(t *testing.T) {
+	c := NewOIDCController(mocks.NewMockOIDCUseCase(t))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/userinfo", nil)
+
+	c.UserInfoHandler(ctx)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+}
\ No newline at end of file
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"fido2/internal/dto"
+	"fido2/internal/entity"
+	"fido2/internal/platform/tenant"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/pkg/utils"
+	"fido2/pkg/utils/common"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+// StartPaymentAssertionHandler Secure Payment Confirmation Credential Get Options
+// 產生帶有交易資料 (收款方、金額、付款工具) 的登入選項，只允許以 payment 擴充註冊的 Credential
+// 驗證器回應同樣送到 /assertion/result，伺服器會比對 payment.get 的 clientDataJSON 與此處發出的交易
+func (c *AuthController) StartPaymentAssertionHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("StartPaymentAssertionHandler called")
+
+	rp := tenant.FromContext(ctx)
+
+	var request *dto.PaymentAssertionOptionsRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to parse request body, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	transaction := &wAuth.PaymentTransaction{
+		RPID:        rp.WebAuthn.Config.RPID,
+		TopOrigin:   request.TopOrigin,
+		PayeeName:   request.PayeeName,
+		PayeeOrigin: request.PayeeOrigin,
+		Total:       request.Total,
+		Instrument:  request.Instrument,
+	}
+	if err := transaction.Validate(); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: err.Error(),
+				ErrorCode:    common.ErrorCodeInvalidPaymentTransaction,
+			},
+		)
+		return
+	}
+
+	foundUser, err := c.UserUC.GetUserByUsername(rp.ID, request.Username)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user by name, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	if foundUser == nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "user not found",
+			},
+		)
+		return
+	}
+
+	credentials, err := c.CredentialUC.GetCredentialsByUserID(foundUser.ID)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user credentials, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	var paymentCredentials []*entity.Credential
+	for _, credential := range credentials {
+		if credential.Payment {
+			paymentCredentials = append(paymentCredentials, credential)
+		}
+	}
+	if len(paymentCredentials) == 0 {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "user has no credential registered for secure payment confirmation",
+				ErrorCode:    common.ErrorCodeNoPaymentCredential,
+			},
+		)
+		return
+	}
+
+	// 交易資料放在 payment 擴充中，隨 SessionData 保存供 /assertion/result 比對
+	options, sessionData, err := rp.WebAuthn.BeginLogin(
+		wAuth.NewUserWebAuthn(foundUser, paymentCredentials),
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+		webauthn.WithAssertionExtensions(protocol.AuthenticationExtensions{wAuth.PaymentExtension: transaction.Extension()}),
+	)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to begin login, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ceremonyID := uuid.New().String()
+	if err := c.Sessions.Save(ctx.Request.Context(), ceremonyID, sessionData, rp.LoginTimeout); err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to save session data, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	if err = c.UserUC.SetChallenge(foundUser, options.Response.Challenge.String(), rp.LoginTimeout); err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to update user, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		dto.CredentialGetOptionsResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			PublicKeyCredentialRequestOptions: options.Response,
+			CeremonyID:                        ceremonyID,
+		},
+	)
+}
+
+// verifyPaymentClientData 驗證 SPC 登入的 clientDataJSON 並調整解析結果，讓 go-webauthn 可繼續驗證簽章與驗證器資料
+// clientDataJSON 的 type 為 payment.get 且 origin 可能是收款方，兩者已在此比對過，簽章仍涵蓋原始的 clientDataJSON
+func verifyPaymentClientData(ctx *gin.Context, rp *tenant.Tenant, transaction *wAuth.PaymentTransaction, clientDataJSON []byte, pca *protocol.ParsedCredentialAssertionData) bool {
+	if err := transaction.VerifyClientData(clientDataJSON, rp.WebAuthn.Config.RPOrigins); err != nil {
+		utils.GetLogger().Warnf("Rejected payment assertion: %v", err)
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "payment confirmation rejected, error: " + err.Error(),
+				ErrorCode:    common.ErrorCodePaymentMismatch,
+			},
+		)
+		return false
+	}
+
+	pca.Response.CollectedClientData.Type = protocol.AssertCeremony
+	pca.Response.CollectedClientData.Origin = rp.WebAuthn.Config.RPOrigins[0]
+	pca.Response.CollectedClientData.TopOrigin = ""
+	pca.Response.CollectedClientData.CrossOrigin = false
+	return true
+}
+
+// paymentResultResponse 回傳 SPC 驗證成功的結果，付款授權由呼叫端依此結果進行
+func paymentResultResponse(ctx *gin.Context, user *entity.User, credentialID string, credential *webauthn.Credential, transaction *wAuth.PaymentTransaction) {
+	ctx.JSON(
+		http.StatusOK,
+		dto.PaymentAssertionResultResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			CredentialID: credentialID,
+			UserID:       user.ID,
+			UserVerified: credential.Flags.UserVerified,
+			Transaction:  *transaction,
+		},
+	)
+}
\ No newline at end of file
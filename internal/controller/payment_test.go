@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fido2/internal/dto"
+	"fido2/internal/entity"
+	"fido2/internal/platform/session"
+	wAuth "fido2/internal/platform/webauthn"
+	mocks "fido2/internal/usecase"
+	"fido2/pkg/utils/common"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testPaymentRequest 測試用的 SPC 交易
+func testPaymentRequest() dto.PaymentAssertionOptionsRequest {
+	return dto.PaymentAssertionOptionsRequest{
+		Username:    "testuser",
+		PayeeName:   "Example Store",
+		PayeeOrigin: "https://store.example",
+		Total:       wAuth.PaymentCurrencyAmount{Currency: "TWD", Value: "1280.00"},
+		Instrument:  wAuth.PaymentCredentialInstrument{DisplayName: "Visa ****1234", Icon: "https://localhost/card.png"},
+	}
+}
+
+// startPaymentAssertion 呼叫 StartPaymentAssertionHandler 並回傳登入選項
+func startPaymentAssertion(t *testing.T, c *AuthController, request dto.PaymentAssertionOptionsRequest) (*httptest.ResponseRecorder, dto.CredentialGetOptionsResponse) {
+	t.Helper()
+
+	body, _ := json.Marshal(request)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/payment/assertion/options", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.StartPaymentAssertionHandler(ctx)
+
+	var response dto.CredentialGetOptionsResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+// 只有以 payment 擴充註冊的 Credential 會出現在 allowCredentials，交易資料放在 payment 擴充中
+func TestStartPaymentAssertionHandler_Success(t *testing.T) {
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	c := NewAuthController(mockUC, mockCredUC, nil, nil, session.NewMemoryStore(time.Minute))
+
+	user := &entity.User{ID: "1", UserHandle: []byte("user-handle"), UserName: "testuser"}
+
+	mockUC.EXPECT().GetUserByUsername("default", "testuser").Return(user, nil)
+	mockCredUC.EXPECT().
+		GetCredentialsByUserID("1").
+		Return([]*entity.Credential{{ID: "Y3JlZC0x", UserID: "1", Payment: true}, {ID: "Y3JlZC0y", UserID: "1"}}, nil)
+	mockUC.EXPECT().SetChallenge(user, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	w, response := startPaymentAssertion(t, c, testPaymentRequest())
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, response.CeremonyID)
+	assert.Equal(t, protocol.VerificationRequired, response.UserVerification)
+	assert.Len(t, response.AllowedCredentials, 1)
+	assert.Equal(t, protocol.URLEncodedBase64("cred-1"), response.AllowedCredentials[0].CredentialID)
+
+	payment, _ := json.Marshal(response.Extensions["payment"])
+	assert.JSONEq(t, `{
+		"isPayment": true,
+		"rpId": "localhost",
+		"payeeName": "Example Store",
+		"payeeOrigin": "https://store.example",
+		"total": {"currency": "TWD", "value": "1280.00"},
+		"instrument": {"displayName": "Visa ****1234", "icon": "https://localhost/card.png"}
+	}`, string(payment))
+}
+
+func TestStartPaymentAssertionHandler_Rejected(t *testing.T) {
+	// 交易資料格式錯誤時不查詢使用者
+	c := NewAuthController(mocks.NewMockUserUseCase(t), nil, nil, nil, session.NewMemoryStore(time.Minute))
+	request := testPaymentRequest()
+	request.Total.Value = "a lot"
+
+	w, response := startPaymentAssertion(t, c, request)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, common.ErrorCodeInvalidPaymentTransaction, response.ErrorCode)
+
+	// 沒有 SPC 用的 Credential
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	c = NewAuthController(mockUC, mockCredUC, nil, nil, session.NewMemoryStore(time.Minute))
+
+	mockUC.EXPECT().GetUserByUsername("default", "testuser").Return(&entity.User{ID: "1"}, nil)
+	mockCredUC.EXPECT().GetCredentialsByUserID("1").Return([]*entity.Credential{{ID: "Y3JlZC0y", UserID: "1"}}, nil)
+
+	w, response = startPaymentAssertion(t, c, testPaymentRequest())
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, common.ErrorCodeNoPaymentCredential, response.ErrorCode)
+}
+
+// 驗證器回傳的 payment.get clientDataJSON 需與伺服器發出的交易相符，成功時不簽發登入 Token
+func TestFinishAssertionHandler_Payment(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected int
+	}{
+		{name: "交易相符", value: "1280.00", expected: http.StatusOK},
+		{name: "金額被竄改", value: "1.00", expected: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUC := mocks.NewMockUserUseCase(t)
+			mockCredUC := mocks.NewMockCredentialUseCase(t)
+			c := NewAuthController(mockUC, mockCredUC, mocks.NewMockTokenUseCase(t), nil, session.NewMemoryStore(time.Minute))
+
+			user := &entity.User{ID: "1", UserHandle: []byte("user-handle"), UserName: "testuser"}
+			authenticator := newVirtualAuthenticator(t)
+			storedCredential := authenticator.storedCredential(t, user.ID)
+			storedCredential.Payment = true
+
+			mockUC.EXPECT().GetUserByUsername("default", "testuser").Return(user, nil)
+			mockCredUC.EXPECT().GetCredentialsByUserID("1").Return([]*entity.Credential{storedCredential}, nil)
+			mockUC.EXPECT().SetChallenge(user, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+			_, options := startPaymentAssertion(t, c, testPaymentRequest())
+
+			// 瀏覽器在收款方頁面顯示交易並產生 payment.get 的 clientDataJSON
+			request := testPaymentRequest()
+			clientData, _ := json.Marshal(map[string]any{
+				"type":      "payment.get",
+				"challenge": options.Challenge.String(),
+				"origin":    "https://store.example",
+				"payment": wAuth.PaymentTransaction{
+					RPID:        "localhost",
+					TopOrigin:   "https://store.example",
+					PayeeName:   request.PayeeName,
+					PayeeOrigin: request.PayeeOrigin,
+					Total:       wAuth.PaymentCurrencyAmount{Currency: "TWD", Value: tc.value},
+					Instrument:  request.Instrument,
+				},
+			})
+
+			body, _ := json.Marshal(dto.AuthenticatorAssertionResponseRequest{
+				CeremonyID: options.CeremonyID,
+				Id:         authenticator.id(),
+				Response:   authenticator.assertionWithClientData(t, clientData, nil),
+				Type:       "public-key",
+			})
+
+			mockUC.EXPECT().ConsumeChallenge("default", options.Challenge.String()).Return(user, nil)
+			mockCredUC.EXPECT().GetCredentialByID(authenticator.id()).Return(storedCredential, nil)
+			if tc.expected == http.StatusOK {
+				mockCredUC.EXPECT().UpdateCredential(&entity.Credential{ID: authenticator.id()}, mock.Anything).Return(nil)
+			}
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/assertion/result", bytes.NewBuffer(body))
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			c.FinishAssertionHandler(ctx)
+
+			assert.Equal(t, tc.expected, w.Code, w.Body.String())
+			if tc.expected != http.StatusOK {
+				var response common.CommonResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, common.ErrorCodePaymentMismatch, response.ErrorCode)
+				return
+			}
+
+			var response dto.PaymentAssertionResultResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "1", response.UserID)
+			assert.True(t, response.UserVerified)
+			assert.Equal(t, "1280.00", response.Transaction.Total.Value)
+		})
+	}
+}
\ No newline at end of file
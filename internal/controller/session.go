@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"errors"
+	"fido2/internal/entity"
+	"fido2/internal/platform/session"
+	"fido2/internal/repository"
+	"fido2/pkg/utils/common"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"net/http"
+)
+
+// takeSession 依 Ceremony ID 以單一原子操作取出並刪除 SessionData，確保每個 Session 只能使用一次
+// 同時送出的請求只有一個能取得 Session，其餘與找不到 Session 相同，會直接寫入回應並回傳 false
+func takeSession(ctx *gin.Context, sessions session.SessionStore, ceremonyID string) (*webauthn.SessionData, bool) {
+	if ceremonyID == "" {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "ceremonyId is missing",
+			},
+		)
+		return nil, false
+	}
+
+	sessionData, err := sessions.Take(ctx.Request.Context(), ceremonyID)
+	if err != nil {
+		if errors.Is(err, session.ErrSessionNotFound) {
+			ctx.JSON(
+				http.StatusBadRequest,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "session not found or expired",
+					ErrorCode:    common.ErrorCodeChallengeExpired,
+				},
+			)
+			return nil, false
+		}
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to take session data, error: " + err.Error(),
+			},
+		)
+		return nil, false
+	}
+
+	return sessionData, true
+}
+
+// consumeChallenge 消耗 Options 階段保存在使用者上的 Challenge 並回傳該使用者，確保 Challenge 只能使用一次
+// 失敗時會直接寫入回應並回傳 false
+func (c *AuthController) consumeChallenge(ctx *gin.Context, tenantID, challenge string) (*entity.User, bool) {
+	user, err := c.UserUC.ConsumeChallenge(tenantID, challenge)
+	if err != nil {
+		if errors.Is(err, repository.ErrChallengeExpired) {
+			ctx.JSON(
+				http.StatusBadRequest,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: err.Error(),
+					ErrorCode:    common.ErrorCodeChallengeExpired,
+				},
+			)
+			return nil, false
+		}
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to consume challenge, error: " + err.Error(),
+			},
+		)
+		return nil, false
+	}
+
+	return user, true
+}
\ No newline at end of file
@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"errors"
+	"fido2/internal/dto"
+	"fido2/internal/platform/tenant"
+	"fido2/internal/platform/token"
+	"fido2/internal/usecase"
+	"fido2/pkg/utils"
+	"fido2/pkg/utils/common"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// accessClaimsKey 驗證後的 Access Token 內容存放在 gin.Context 中的 key
+const accessClaimsKey = "accessClaims"
+
+type TokenController struct {
+	TokenUC usecase.TokenUseCase
+}
+
+func NewTokenController(t usecase.TokenUseCase) *TokenController {
+	return &TokenController{TokenUC: t}
+}
+
+// Authenticate 驗證 Authorization header 中的 Bearer Access Token，通過後才執行後續的 Handler
+func (c *TokenController) Authenticate(ctx *gin.Context) {
+	accessToken, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if !ok || accessToken == "" {
+		ctx.Header("WWW-Authenticate", "Bearer")
+		ctx.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "bearer access token is required",
+			},
+		)
+		return
+	}
+
+	claims, err := c.TokenUC.VerifyAccessToken(tenant.FromContext(ctx).ID, accessToken)
+	if err != nil {
+		ctx.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		ctx.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to verify access token, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.Set(accessClaimsKey, claims)
+	ctx.Next()
+}
+
+// accessClaims 取得 Authenticate 驗證後的 Access Token 內容
+func accessClaims(ctx *gin.Context) *token.AccessClaims {
+	return ctx.MustGet(accessClaimsKey).(*token.AccessClaims)
+}
+
+// RefreshTokenHandler 以 Refresh Token 換發新的 Access Token 與 Refresh Token
+func (c *TokenController) RefreshTokenHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("RefreshTokenHandler called")
+
+	var request *dto.RefreshTokenRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to parse request body, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	rp := tenant.FromContext(ctx)
+	pair, err := c.TokenUC.RefreshTokens(rp.Issuer, rp.ID, request.RefreshToken)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, token.ErrInvalidRefreshToken) || errors.Is(err, token.ErrRefreshTokenReused) {
+			status = http.StatusUnauthorized
+		}
+		ctx.JSON(
+			status,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to refresh token, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newTokenResponse(pair))
+}
+
+// LogoutHandler 撤銷 Refresh Token 所屬登入 Session 的所有 Token
+func (c *TokenController) LogoutHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("LogoutHandler called")
+
+	var request *dto.RefreshTokenRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to parse request body, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	if err := c.TokenUC.RevokeRefreshToken(tenant.FromContext(ctx).ID, request.RefreshToken); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, token.ErrInvalidRefreshToken) {
+			status = http.StatusUnauthorized
+		}
+		ctx.JSON(
+			status,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to logout, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		common.CommonResponse{
+			Status:       "ok",
+			ErrorMessage: "",
+		},
+	)
+}
+
+// JWKSHandler 公開驗證 Token 簽章用的公鑰 (/.well-known/jwks.json)
+func (c *TokenController) JWKSHandler(ctx *gin.Context) {
+	jwks, err := c.TokenUC.GetJWKS()
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get jwks, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, jwks)
+}
+
+// newTokenResponse 將簽發的 Token 轉為回應格式
+func newTokenResponse(pair *token.Pair) dto.TokenResponse {
+	return dto.TokenResponse{
+		CommonResponse: common.CommonResponse{
+			Status:       "ok",
+			ErrorMessage: "",
+		},
+		AccessToken:  pair.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(pair.ExpiresIn.Seconds()),
+		RefreshToken: pair.RefreshToken,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fido2/internal/dto"
+	"fido2/internal/platform/token"
+	mocks "fido2/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenHandler_Success(t *testing.T) {
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewTokenController(mockTokenUC)
+
+	body, _ := json.Marshal(dto.RefreshTokenRequest{RefreshToken: "refresh"})
+
+	mockTokenUC.EXPECT().
+		RefreshTokens("http://localhost:3000", "default", "refresh").
+		Return(&token.Pair{AccessToken: "new-access", RefreshToken: "new-refresh", ExpiresIn: time.Minute}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/token/refresh", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.RefreshTokenHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.TokenResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "new-access", response.AccessToken)
+	assert.Equal(t, "new-refresh", response.RefreshToken)
+	assert.Equal(t, 60, response.ExpiresIn)
+}
+
+func TestRefreshTokenHandler_Reused(t *testing.T) {
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewTokenController(mockTokenUC)
+
+	body, _ := json.Marshal(dto.RefreshTokenRequest{RefreshToken: "refresh"})
+
+	mockTokenUC.EXPECT().
+		RefreshTokens("http://localhost:3000", "default", "refresh").
+		Return(nil, token.ErrRefreshTokenReused)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/token/refresh", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.RefreshTokenHandler(ctx)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRefreshTokenHandler_MissingToken(t *testing.T) {
+	c := NewTokenController(mocks.NewMockTokenUseCase(t))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/token/refresh", bytes.NewBufferString(`{}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.RefreshTokenHandler(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLogoutHandler(t *testing.T) {
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewTokenController(mockTokenUC)
+
+	body, _ := json.Marshal(dto.RefreshTokenRequest{RefreshToken: "refresh"})
+
+	mockTokenUC.EXPECT().
+		RevokeRefreshToken("default", "refresh").
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/logout", bytes.NewBuffer(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.LogoutHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+func TestJWKSHandler(t *testing.T) {
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	c := NewTokenController(mockTokenUC)
+
+	mockTokenUC.EXPECT().
+		GetJWKS().
+		Return(&token.JWKS{Keys: []token.JWK{{Kty: "EC", Crv: "P-256", X: "x", Y: "y", Kid: "kid-1", Alg: "ES256", Use: "sig"}}}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/.well-known/jwks.json", nil)
+
+	c.JWKSHandler(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Cache-Control"))
+
+	var jwks token.JWKS
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &jwks))
+	assert.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "kid-1", jwks.Keys[0].Kid)
+}
\ No newline at end of file
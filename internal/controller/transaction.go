@@ -0,0 +1,555 @@
+package controller
+
+import (
+	"encoding/base64"
+	"errors"
+	"fido2/internal/dto"
+	"fido2/internal/entity"
+	"fido2/internal/platform/session"
+	"fido2/internal/platform/tenant"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/internal/repository"
+	"fido2/internal/usecase"
+	"fido2/pkg/utils"
+	"fido2/pkg/utils/common"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+var errTransactionTampered = errors.New("transaction details do not match the stored hash")
+
+type TransactionController struct {
+	UserUC        usecase.UserUseCase
+	CredentialUC  usecase.CredentialUseCase
+	TransactionUC usecase.TransactionUseCase
+	Sessions      session.SessionStore
+	ClonePolicy   wAuth.ClonePolicy
+}
+
+func NewTransactionController(u usecase.UserUseCase, cr usecase.CredentialUseCase, tx usecase.TransactionUseCase, s session.SessionStore) *TransactionController {
+	return &TransactionController{UserUC: u, CredentialUC: cr, TransactionUC: tx, Sessions: s, ClonePolicy: wAuth.GetClonePolicy()}
+}
+
+// CreateTransactionHandler 建立需要目前登入使用者以 Passkey 確認的交易
+func (c *TransactionController) CreateTransactionHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("CreateTransactionHandler called")
+
+	rp := tenant.FromContext(ctx)
+	claims := accessClaims(ctx)
+
+	var request *dto.CreateTransactionRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to parse request body, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	transaction, err := c.TransactionUC.CreateTransaction(rp.ID, claims.Subject, request.Payee, request.Amount, request.Currency)
+	if err != nil {
+		if errors.Is(err, wAuth.ErrInvalidTransaction) {
+			ctx.JSON(
+				http.StatusBadRequest,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: err.Error(),
+					ErrorCode:    common.ErrorCodeInvalidTransaction,
+				},
+			)
+			return
+		}
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to create transaction, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusCreated,
+		dto.TransactionResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			Transaction: transaction,
+		},
+	)
+}
+
+// ListTransactionsHandler 列出目前登入使用者的交易，可用 status 查詢參數篩選狀態
+func (c *TransactionController) ListTransactionsHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("ListTransactionsHandler called")
+
+	rp := tenant.FromContext(ctx)
+	claims := accessClaims(ctx)
+
+	status := entity.TransactionStatus(ctx.Query("status"))
+	switch status {
+	case "", entity.TransactionPending, entity.TransactionConfirmed, entity.TransactionExpired, entity.TransactionRejected:
+	default:
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "status must be one of pending, confirmed, expired or rejected",
+			},
+		)
+		return
+	}
+
+	transactions, err := c.TransactionUC.GetTransactions(rp.ID, claims.Subject, status)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get transactions, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		dto.TransactionListResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			Transactions: transactions,
+		},
+	)
+}
+
+// GetTransactionHandler 查詢交易狀態，已確認的交易會附上簽章紀錄
+func (c *TransactionController) GetTransactionHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("GetTransactionHandler called")
+
+	transaction, ok := c.getTransaction(ctx)
+	if !ok {
+		return
+	}
+
+	var record *entity.TransactionSignature
+	if transaction.Status == entity.TransactionConfirmed {
+		var err error
+		record, err = c.TransactionUC.GetTransactionSignature(transaction.ID)
+		if err != nil {
+			ctx.JSON(
+				http.StatusInternalServerError,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "failed to get transaction signature, error: " + err.Error(),
+				},
+			)
+			return
+		}
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		dto.TransactionResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			Transaction: transaction,
+			Signature:   record,
+		},
+	)
+}
+
+// RejectTransactionHandler 使用者拒絕尚未確認的交易
+func (c *TransactionController) RejectTransactionHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("RejectTransactionHandler called")
+
+	rp := tenant.FromContext(ctx)
+	claims := accessClaims(ctx)
+
+	if err := c.TransactionUC.RejectTransaction(rp.ID, claims.Subject, ctx.Param("id")); err != nil {
+		transactionErrorResponse(ctx, "failed to reject transaction", err)
+		return
+	}
+
+	utils.GetLogger().Infof("User %s rejected transaction %s", claims.Subject, ctx.Param("id"))
+
+	ctx.JSON(
+		http.StatusOK,
+		common.CommonResponse{
+			Status:       "ok",
+			ErrorMessage: "",
+		},
+	)
+}
+
+// StartTransactionAssertionHandler Transaction Confirmation Credential Get Options
+// 以交易內容的雜湊值作為 Challenge 產生登入選項，簽章因此與交易的收款方與金額綁定 (PSD2 動態連結)
+func (c *TransactionController) StartTransactionAssertionHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("StartTransactionAssertionHandler called")
+
+	rp := tenant.FromContext(ctx)
+
+	transaction, ok := c.pendingTransaction(ctx)
+	if !ok {
+		return
+	}
+
+	foundUser, err := c.UserUC.GetUserByID(transaction.UserID)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	if foundUser == nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "user not found",
+			},
+		)
+		return
+	}
+
+	credentials, err := c.CredentialUC.GetCredentialsByUserID(foundUser.ID)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user credentials, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	// Strong Customer Authentication 需要使用者驗證
+	options, sessionData, err := rp.WebAuthn.BeginLogin(
+		wAuth.NewUserWebAuthn(foundUser, credentials),
+		webauthn.WithChallenge(wAuth.TransactionHash(transaction)),
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to begin login, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ceremonyID := uuid.New().String()
+	if err := c.Sessions.Save(ctx.Request.Context(), ceremonyID, sessionData, rp.LoginTimeout); err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to save session data, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		dto.CredentialGetOptionsResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			PublicKeyCredentialRequestOptions: options.Response,
+			CeremonyID:                        ceremonyID,
+		},
+	)
+}
+
+// FinishTransactionAssertionHandler Transaction Confirmation Authenticator Assertion Response
+// 驗證驗證器對交易雜湊值的簽章，成功後將交易標記為已確認並保存不可否認性紀錄
+func (c *TransactionController) FinishTransactionAssertionHandler(ctx *gin.Context) {
+	utils.GetLogger().Info("FinishTransactionAssertionHandler called")
+
+	rp := tenant.FromContext(ctx)
+
+	var request *dto.AuthenticatorAssertionResponseRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to parse request body, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	transaction, ok := c.pendingTransaction(ctx)
+	if !ok {
+		return
+	}
+
+	sessionData, ok := takeSession(ctx, c.Sessions, request.CeremonyID)
+	if !ok {
+		return
+	}
+
+	// Session 必須是為這筆交易產生的，Challenge 即交易雜湊值
+	if sessionData.Challenge != transaction.Hash {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "session does not belong to this transaction",
+			},
+		)
+		return
+	}
+
+	pca, err := parseAssertionRequest(request)
+	if err != nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to parse assertion response, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	foundUser, err := c.UserUC.GetUserByID(transaction.UserID)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	if foundUser == nil {
+		ctx.JSON(
+			http.StatusBadRequest,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "user not found",
+			},
+		)
+		return
+	}
+
+	credentials, err := c.CredentialUC.GetCredentialsByUserID(foundUser.ID)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to get user credentials, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	webauthnUser := wAuth.NewUserWebAuthn(foundUser, credentials)
+	if len(pca.Response.UserHandle) > 0 {
+		if !webauthnUser.MatchUserHandle(pca.Response.UserHandle) {
+			ctx.JSON(
+				http.StatusBadRequest,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "user handle does not belong to this user",
+				},
+			)
+			return
+		}
+		// 以舊版別名註冊的 Passkey 回傳舊版 user handle，Session 已透過交易綁定此使用者
+		sessionData.UserID = webauthnUser.WebAuthnID()
+	}
+
+	credential, err := rp.WebAuthn.ValidateLogin(webauthnUser, *sessionData, pca)
+	if err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to validate login, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	credentialID := base64.RawURLEncoding.EncodeToString(credential.ID)
+	if credential.Authenticator.CloneWarning && !handleCloneWarning(ctx, c.ClonePolicy, c.UserUC, c.CredentialUC, foundUser, credentialID) {
+		return
+	}
+
+	if err := c.CredentialUC.UpdateCredential(&entity.Credential{ID: credentialID}, wAuth.CredentialUsageUpdate(credential, time.Now())); err != nil {
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: "failed to update credential, error: " + err.Error(),
+			},
+		)
+		return
+	}
+
+	record := &entity.TransactionSignature{
+		CredentialID:      credentialID,
+		ClientDataJSON:    pca.Raw.AssertionResponse.ClientDataJSON,
+		AuthenticatorData: pca.Raw.AssertionResponse.AuthenticatorData,
+		Signature:         pca.Raw.AssertionResponse.Signature,
+		UserVerified:      credential.Flags.UserVerified,
+		SignCount:         credential.Authenticator.SignCount,
+	}
+	if err := c.TransactionUC.ConfirmTransaction(transaction, record); err != nil {
+		transactionErrorResponse(ctx, "failed to confirm transaction", err)
+		return
+	}
+
+	utils.GetLogger().Infof("User %s confirmed transaction %s with credential ID: %s", foundUser.ID, transaction.ID, credentialID)
+
+	ctx.JSON(
+		http.StatusOK,
+		dto.TransactionResponse{
+			CommonResponse: common.CommonResponse{
+				Status:       "ok",
+				ErrorMessage: "",
+			},
+			Transaction: transaction,
+			Signature:   record,
+		},
+	)
+}
+
+// getTransaction 取得目前登入使用者的交易，失敗時會直接寫入回應並回傳 false
+func (c *TransactionController) getTransaction(ctx *gin.Context) (*entity.Transaction, bool) {
+	transaction, err := c.TransactionUC.GetTransaction(tenant.FromContext(ctx).ID, accessClaims(ctx).Subject, ctx.Param("id"))
+	if err != nil {
+		transactionErrorResponse(ctx, "failed to get transaction", err)
+		return nil, false
+	}
+	return transaction, true
+}
+
+// pendingTransaction 取得等待確認的交易並確認交易內容未在建立後被修改
+// 失敗時會直接寫入回應並回傳 false
+func (c *TransactionController) pendingTransaction(ctx *gin.Context) (*entity.Transaction, bool) {
+	transaction, ok := c.getTransaction(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	if transaction.Status != entity.TransactionPending {
+		transactionErrorResponse(ctx, "transaction is "+string(transaction.Status), repository.ErrTransactionNotPending)
+		return nil, false
+	}
+
+	if !wAuth.VerifyTransactionHash(transaction) {
+		utils.GetLogger().Errorf("Stored hash of transaction %s does not match its details", transaction.ID)
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: errTransactionTampered.Error(),
+			},
+		)
+		return nil, false
+	}
+
+	return transaction, true
+}
+
+// transactionErrorResponse 依交易錯誤類型回傳對應的狀態碼與錯誤代碼
+func transactionErrorResponse(ctx *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTransactionNotFound):
+		ctx.JSON(
+			http.StatusNotFound,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: message + ", error: " + err.Error(),
+				ErrorCode:    common.ErrorCodeTransactionNotFound,
+			},
+		)
+	case errors.Is(err, repository.ErrTransactionNotPending):
+		ctx.JSON(
+			http.StatusConflict,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: message + ", error: " + err.Error(),
+				ErrorCode:    common.ErrorCodeTransactionNotPending,
+			},
+		)
+	default:
+		ctx.JSON(
+			http.StatusInternalServerError,
+			common.CommonResponse{
+				Status:       "failed",
+				ErrorMessage: message + ", error: " + err.Error(),
+			},
+		)
+	}
+}
+
+// parseAssertionRequest 解碼驗證器回應並轉換為 go-webauthn 的解析結果
+func parseAssertionRequest(request *dto.AuthenticatorAssertionResponseRequest) (*protocol.ParsedCredentialAssertionData, error) {
+	credentialRawID, err := utils.DecodeCredentialRawID(request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields [4][]byte
+	for i, value := range []string{
+		request.Response.ClientDataJSON,
+		request.Response.AuthenticatorData,
+		request.Response.Signature,
+		request.Response.UserHandle,
+	} {
+		if fields[i], err = base64.RawURLEncoding.DecodeString(value); err != nil {
+			return nil, err
+		}
+	}
+
+	car := protocol.CredentialAssertionResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{
+				ID:   request.Id,
+				Type: request.Type,
+			},
+			RawID: protocol.URLEncodedBase64(credentialRawID),
+		},
+		AssertionResponse: protocol.AuthenticatorAssertionResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{
+				ClientDataJSON: fields[0],
+			},
+			AuthenticatorData: fields[1],
+			Signature:         fields[2],
+			UserHandle:        fields[3],
+		},
+	}
+	return car.Parse()
+}
\ No newline at end of file
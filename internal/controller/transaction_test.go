@@ -0,0 +1,250 @@
+package controller
+
+import (
+	"encoding/json"
+	"fido2/internal/dto"
+	"fido2/internal/entity"
+	"fido2/internal/platform/session"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/internal/repository"
+	mocks "fido2/internal/usecase"
+	"fido2/pkg/utils/common"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTransactionRouter 建立掛載 Access Token 驗證的交易確認路由
+func newTransactionRouter(c *TransactionController, tokenUC *mocks.MockTokenUseCase) *gin.Engine {
+	router := gin.New()
+	txn := router.Group("/transactions", NewTokenController(tokenUC).Authenticate)
+	txn.POST("", c.CreateTransactionHandler)
+	txn.GET("", c.ListTransactionsHandler)
+	txn.GET("/:id", c.GetTransactionHandler)
+	txn.POST("/:id/reject", c.RejectTransactionHandler)
+	txn.POST("/:id/assertion/options", c.StartTransactionAssertionHandler)
+	txn.POST("/:id/assertion/result", c.FinishTransactionAssertionHandler)
+	return router
+}
+
+// testTransaction 建立屬於 user-1 的待確認交易
+func testTransaction(t *testing.T) *entity.Transaction {
+	t.Helper()
+
+	transaction, err := wAuth.NewTransaction("default", "user-1", "Example Store", "1280.00", "TWD", time.Now())
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+	return transaction
+}
+
+func TestTransactionHandlers(t *testing.T) {
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	mockTxUC := mocks.NewMockTransactionUseCase(t)
+	router := newTransactionRouter(NewTransactionController(nil, nil, mockTxUC, nil), mockTokenUC)
+	expectAccessToken(mockTokenUC)
+
+	transaction := testTransaction(t)
+
+	t.Run("建立交易", func(t *testing.T) {
+		mockTxUC.EXPECT().CreateTransaction("default", "user-1", "Example Store", "1280.00", "TWD").Return(transaction, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedRequest("POST", "/transactions", []byte(`{"payee":"Example Store","amount":"1280.00","currency":"TWD"}`)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response dto.TransactionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, entity.TransactionPending, response.Transaction.Status)
+		assert.Equal(t, transaction.Hash, response.Transaction.Hash)
+	})
+
+	t.Run("交易格式錯誤", func(t *testing.T) {
+		mockTxUC.EXPECT().
+			CreateTransaction("default", "user-1", "Example Store", "-1", "TWD").
+			Return(nil, fmt.Errorf("%w: amount must be a positive decimal value", wAuth.ErrInvalidTransaction)).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedRequest("POST", "/transactions", []byte(`{"payee":"Example Store","amount":"-1","currency":"TWD"}`)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), common.ErrorCodeInvalidTransaction)
+	})
+
+	t.Run("依狀態查詢", func(t *testing.T) {
+		mockTxUC.EXPECT().GetTransactions("default", "user-1", entity.TransactionExpired).Return([]*entity.Transaction{}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedRequest("GET", "/transactions?status=expired", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status":"ok","errorMessage":"","transactions":[]}`, w.Body.String())
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedRequest("GET", "/transactions?status=unknown", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("已確認的交易附上簽章紀錄", func(t *testing.T) {
+		confirmed := *transaction
+		confirmed.Status = entity.TransactionConfirmed
+		record := &entity.TransactionSignature{TransactionID: transaction.ID, TransactionHash: transaction.Hash, Signature: []byte("signature")}
+
+		mockTxUC.EXPECT().GetTransaction("default", "user-1", transaction.ID).Return(&confirmed, nil).Once()
+		mockTxUC.EXPECT().GetTransactionSignature(transaction.ID).Return(record, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedRequest("GET", "/transactions/"+transaction.ID, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.TransactionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, entity.TransactionConfirmed, response.Transaction.Status)
+		assert.Equal(t, transaction.Hash, response.Signature.TransactionHash)
+	})
+
+	t.Run("不屬於使用者的交易", func(t *testing.T) {
+		mockTxUC.EXPECT().GetTransaction("default", "user-1", "other").Return(nil, repository.ErrTransactionNotFound).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedRequest("GET", "/transactions/other", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), common.ErrorCodeTransactionNotFound)
+	})
+
+	t.Run("拒絕已確認的交易", func(t *testing.T) {
+		mockTxUC.EXPECT().RejectTransaction("default", "user-1", transaction.ID).Return(repository.ErrTransactionNotPending).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedRequest("POST", "/transactions/"+transaction.ID+"/reject", nil))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), common.ErrorCodeTransactionNotPending)
+	})
+}
+
+// 登入選項的 Challenge 即交易雜湊值，驗證器簽署後交易標記為已確認並保存簽章
+func TestTransactionAssertion_Confirm(t *testing.T) {
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	mockUC := mocks.NewMockUserUseCase(t)
+	mockCredUC := mocks.NewMockCredentialUseCase(t)
+	mockTxUC := mocks.NewMockTransactionUseCase(t)
+	router := newTransactionRouter(NewTransactionController(mockUC, mockCredUC, mockTxUC, session.NewMemoryStore(time.Minute)), mockTokenUC)
+	expectAccessToken(mockTokenUC)
+
+	user := &entity.User{ID: "user-1", UserHandle: []byte("user-handle"), UserName: "testuser"}
+	authenticator := newVirtualAuthenticator(t)
+	storedCredential := authenticator.storedCredential(t, user.ID)
+	transaction := testTransaction(t)
+
+	mockTxUC.EXPECT().GetTransaction("default", "user-1", transaction.ID).RunAndReturn(func(string, string, string) (*entity.Transaction, error) {
+		stored := *transaction
+		return &stored, nil
+	})
+	mockUC.EXPECT().GetUserByID("user-1").Return(user, nil)
+	mockCredUC.EXPECT().GetCredentialsByUserID("user-1").Return([]*entity.Credential{storedCredential}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest("POST", "/transactions/"+transaction.ID+"/assertion/options", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var options dto.CredentialGetOptionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &options))
+	assert.Equal(t, transaction.Hash, options.Challenge.String())
+	assert.Equal(t, protocol.VerificationRequired, options.UserVerification)
+
+	assertion := authenticator.assertion(t, options.Challenge.String(), user.UserHandle)
+	body, _ := json.Marshal(dto.AuthenticatorAssertionResponseRequest{
+		CeremonyID: options.CeremonyID,
+		Id:         authenticator.id(),
+		Response:   assertion,
+		Type:       "public-key",
+	})
+
+	mockCredUC.EXPECT().UpdateCredential(&entity.Credential{ID: authenticator.id()}, mock.Anything).Return(nil)
+	mockTxUC.EXPECT().
+		ConfirmTransaction(mock.MatchedBy(func(tx *entity.Transaction) bool { return tx.ID == transaction.ID }), mock.MatchedBy(func(record *entity.TransactionSignature) bool {
+			return record.CredentialID == authenticator.id() && record.UserVerified && len(record.Signature) > 0 &&
+				len(record.AuthenticatorData) > 0 && len(record.ClientDataJSON) > 0
+		})).
+		RunAndReturn(func(tx *entity.Transaction, record *entity.TransactionSignature) error {
+			tx.Status = entity.TransactionConfirmed
+			record.TransactionHash = tx.Hash
+			return nil
+		})
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest("POST", "/transactions/"+transaction.ID+"/assertion/result", body))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response dto.TransactionResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, entity.TransactionConfirmed, response.Transaction.Status)
+	assert.Equal(t, transaction.Hash, response.Signature.TransactionHash)
+
+	// Session 只能使用一次
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest("POST", "/transactions/"+transaction.ID+"/assertion/result", body))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTransactionAssertion_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(transaction *entity.Transaction)
+		expected int
+	}{
+		{
+			name:     "交易已過期",
+			modify:   func(tx *entity.Transaction) { tx.Status = entity.TransactionExpired },
+			expected: http.StatusConflict,
+		},
+		{
+			// 交易內容在建立後被修改，與保存的雜湊值不符
+			name:     "交易內容被竄改",
+			modify:   func(tx *entity.Transaction) { tx.Amount = "1.00" },
+			expected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTokenUC := mocks.NewMockTokenUseCase(t)
+			mockTxUC := mocks.NewMockTransactionUseCase(t)
+			router := newTransactionRouter(NewTransactionController(nil, nil, mockTxUC, session.NewMemoryStore(time.Minute)), mockTokenUC)
+			expectAccessToken(mockTokenUC)
+
+			transaction := testTransaction(t)
+			tc.modify(transaction)
+			mockTxUC.EXPECT().GetTransaction("default", "user-1", transaction.ID).Return(transaction, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authorizedRequest("POST", "/transactions/"+transaction.ID+"/assertion/options", nil))
+			assert.Equal(t, tc.expected, w.Code, w.Body.String())
+		})
+	}
+
+	// 為另一筆交易產生的 Session 不可用來確認此交易
+	mockTokenUC := mocks.NewMockTokenUseCase(t)
+	mockTxUC := mocks.NewMockTransactionUseCase(t)
+	sessions := session.NewMemoryStore(time.Minute)
+	router := newTransactionRouter(NewTransactionController(nil, nil, mockTxUC, sessions), mockTokenUC)
+	expectAccessToken(mockTokenUC)
+
+	transaction, other := testTransaction(t), testTransaction(t)
+	mockTxUC.EXPECT().GetTransaction("default", "user-1", transaction.ID).Return(transaction, nil)
+	assert.NoError(t, sessions.Save(t.Context(), "ceremony-1", &webauthn.SessionData{Challenge: other.Hash}, time.Minute))
+
+	body, _ := json.Marshal(dto.AuthenticatorAssertionResponseRequest{CeremonyID: "ceremony-1"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest("POST", "/transactions/"+transaction.ID+"/assertion/result", body))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "session does not belong to this transaction")
+}
\ No newline at end of file
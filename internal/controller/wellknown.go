@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"fido2/internal/platform/tenant"
 	"fido2/pkg/utils/common"
 	"fmt"
 	"net/http"
@@ -14,7 +15,7 @@ import (
 func AppleWellKnownHandler(ctx *gin.Context) {
 	fmt.Println("call /.well-known/apple-app-site-association")
 
-	appleAppSiteAssociationData, err := os.ReadFile("apple-app-site-association")
+	appleAppSiteAssociationData, err := os.ReadFile(tenant.FromContext(ctx).AppleAppSiteAssociationFile)
 	if err != nil {
 		ctx.JSON(
 			http.StatusInternalServerError,
@@ -47,7 +48,7 @@ func AppleWellKnownHandler(ctx *gin.Context) {
 func AndroidWellKnownHandler(ctx *gin.Context) {
 	fmt.Println("call /.well-known/assetlinks.json")
 
-	assetlinksData, err := os.ReadFile("assetlinks.json")
+	assetlinksData, err := os.ReadFile(tenant.FromContext(ctx).AssetLinksFile)
 	if err != nil {
 		ctx.JSON(
 			http.StatusInternalServerError,
@@ -74,4 +75,4 @@ func AndroidWellKnownHandler(ctx *gin.Context) {
 		http.StatusOK,
 		assetlinks,
 	)
-}
+}
\ No newline at end of file
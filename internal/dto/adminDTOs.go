@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"fido2/internal/entity"
+	"fido2/pkg/utils/common"
+
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+type FlaggedCredentialsResponse struct {
+	common.CommonResponse
+	Credentials []*entity.Credential `json:"credentials"`
+	Users       []*entity.User       `json:"users"`
+}
+
+type CredentialDeviceResponse struct {
+	common.CommonResponse
+	CredentialID           string `json:"credentialId"`
+	UserID                 string `json:"userId"`
+	AAGUID                 string `json:"aaguid,omitzero"`
+	AuthenticatorName      string `json:"authenticatorName,omitzero"`
+	AttestationType        string `json:"attestationType,omitzero"`
+	EnterpriseAttestation  bool   `json:"enterpriseAttestation"`
+	DeviceSerialNumber     string `json:"deviceSerialNumber,omitzero"`
+	AttestationCertificate string `json:"attestationCertificate,omitzero"`
+}
+
+// ImportU2FCredentialRequest 匯入舊版 U2F 金鑰，使用者不存在時會建立新帳號
+type ImportU2FCredentialRequest struct {
+	Username    string                    `json:"username" binding:"required"`
+	DisplayName string                    `json:"displayName"`
+	KeyHandle   protocol.URLEncodedBase64 `json:"keyHandle" binding:"required"`
+	PublicKey   protocol.URLEncodedBase64 `json:"publicKey" binding:"required"`
+	SignCount   uint32                    `json:"signCount"`
+}
+
+type ImportU2FCredentialResponse struct {
+	common.CommonResponse
+	CredentialID string `json:"credentialId"`
+	UserID       string `json:"userId"`
+}
\ No newline at end of file
@@ -1,24 +1,31 @@
 package dto
 
 import (
+	wAuth "fido2/internal/platform/webauthn"
 	"fido2/pkg/utils/common"
 	"github.com/go-webauthn/webauthn/protocol"
 )
 
 type CredentialGetOptionsRequest struct {
-	Username         string `json:"username,omitzero"`
-	UserVerification string `json:"userVerification,omitzero"`
+	Username         string                        `json:"username,omitzero"`
+	UserVerification string                        `json:"userVerification,omitzero"`
+	Mediation        string                        `json:"mediation,omitzero"`
+	Extensions       AuthenticationExtensionsInput `json:"extensions,omitzero"`
 }
 
 type CredentialGetOptionsResponse struct {
 	common.CommonResponse
 	protocol.PublicKeyCredentialRequestOptions
+	Mediation  protocol.CredentialMediationRequirement `json:"mediation,omitzero"`
+	CeremonyID string                                  `json:"ceremonyId,omitzero"`
 }
 
 type AuthenticatorAssertionResponseRequest struct {
+	CeremonyID                string                         `json:"ceremonyId,omitzero"`
+	AuthorizationRequestID    string                         `json:"authorizationRequestId,omitzero"`
 	Id                        string                         `json:"id,omitzero"`
 	Response                  AuthenticatorAssertionResponse `json:"response,omitzero"`
-	GetClientExtensionResults map[string]interface{}         `json:"getClientExtensionResults,omitzero"`
+	GetClientExtensionResults ClientExtensionResults         `json:"getClientExtensionResults,omitzero"`
 	Type                      string                         `json:"type,omitzero"`
 }
 
@@ -27,4 +34,23 @@ type AuthenticatorAssertionResponse struct {
 	ClientDataJSON    string `json:"clientDataJSON,omitzero"`
 	Signature         string `json:"signature,omitzero"`
 	UserHandle        string `json:"userHandle,omitzero"`
+}
+
+// PaymentAssertionOptionsRequest Secure Payment Confirmation 的登入選項，交易資料會顯示給使用者確認
+type PaymentAssertionOptionsRequest struct {
+	Username    string                            `json:"username" binding:"required"`
+	PayeeName   string                            `json:"payeeName,omitzero"`
+	PayeeOrigin string                            `json:"payeeOrigin,omitzero"`
+	TopOrigin   string                            `json:"topOrigin,omitzero"`
+	Total       wAuth.PaymentCurrencyAmount       `json:"total"`
+	Instrument  wAuth.PaymentCredentialInstrument `json:"instrument"`
+}
+
+// PaymentAssertionResultResponse Secure Payment Confirmation 驗證成功的結果，不簽發登入 Token
+type PaymentAssertionResultResponse struct {
+	common.CommonResponse
+	CredentialID string                   `json:"credentialId"`
+	UserID       string                   `json:"userId"`
+	UserVerified bool                     `json:"userVerified"`
+	Transaction  wAuth.PaymentTransaction `json:"transaction"`
 }
\ No newline at end of file
@@ -10,18 +10,22 @@ type CredentialCreationOptionsRequest struct {
 	DisplayName            string                          `json:"displayName,omitzero"`
 	AuthenticatorSelection protocol.AuthenticatorSelection `json:"authenticatorSelection,omitzero"`
 	Attestation            string                          `json:"attestation,omitzero"`
+	Extensions             RegistrationExtensionsInput     `json:"extensions,omitzero"`
 }
 
 type CredentialCreationOptionsResponse struct {
 	common.CommonResponse
 	protocol.PublicKeyCredentialCreationOptions
+	CeremonyID string `json:"ceremonyId,omitzero"`
 }
 
 type AuthenticatorAttestationResponseRequest struct {
+	CeremonyID                string                           `json:"ceremonyId,omitzero"`
 	Id                        string                           `json:"id,omitzero"`
 	Response                  AuthenticatorAttestationResponse `json:"response,omitzero"`
-	GetClientExtensionResults map[string]interface{}           `json:"getClientExtensionResults,omitzero"`
+	GetClientExtensionResults ClientExtensionResults           `json:"getClientExtensionResults,omitzero"`
 	Type                      string                           `json:"type,omitzero"`
+	AuthenticatorAttachment   string                           `json:"authenticatorAttachment,omitzero"`
 }
 
 type AuthenticatorAttestationResponse struct {
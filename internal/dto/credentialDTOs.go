@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"fido2/pkg/utils/common"
+	"time"
+)
+
+type CredentialInfo struct {
+	ID                 string     `json:"id"`
+	Nickname           string     `json:"nickname,omitzero"`
+	AAGUID             string     `json:"aaguid,omitzero"`
+	AuthenticatorName  string     `json:"authenticatorName,omitzero"`
+	Attachment         string     `json:"attachment,omitzero"`
+	Transports         []string   `json:"transports,omitzero"`
+	BackupEligible     bool       `json:"backupEligible"`
+	BackupState        bool       `json:"backupState"`
+	Discoverable       *bool      `json:"discoverable,omitzero"`
+	PRFEnabled         bool       `json:"prfEnabled"`
+	LargeBlobSupported bool       `json:"largeBlobSupported"`
+	Payment            bool       `json:"payment"`
+	CreatedAt          time.Time  `json:"createdAt,omitzero"`
+	LastUsedAt         *time.Time `json:"lastUsedAt,omitzero"`
+	Current            bool       `json:"current,omitzero"`
+}
+
+type CredentialListResponse struct {
+	common.CommonResponse
+	Credentials []CredentialInfo `json:"credentials"`
+}
+
+type RenameCredentialRequest struct {
+	Nickname string `json:"nickname" binding:"max=64"`
+}
\ No newline at end of file
@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+// RegistrationExtensionsInput 註冊時要求的 WebAuthn 擴充
+type RegistrationExtensionsInput struct {
+	// CredProps 要求回報 Credential 是否為 discoverable credential
+	CredProps bool `json:"credProps,omitzero"`
+
+	// PRF 確認驗證器是否支援 PRF，登入時再以各 Credential 的 salt 取得 PRF 輸出
+	PRF bool `json:"prf,omitzero"`
+
+	// LargeBlob 註冊時只可指定 support (required / preferred)
+	LargeBlob *LargeBlobInput `json:"largeBlob,omitzero"`
+
+	// Payment 註冊為 Secure Payment Confirmation 可用的 Credential，會要求 platform 驗證器、discoverable credential 與使用者驗證
+	Payment bool `json:"payment,omitzero"`
+}
+
+// AuthenticationExtensionsInput 登入時要求的 WebAuthn 擴充，條件式 (autofill) 登入不支援
+type AuthenticationExtensionsInput struct {
+	// PRF 以使用者各 Credential 註冊時產生的 salt 取得 PRF 輸出，需指定使用者名稱
+	PRF bool `json:"prf,omitzero"`
+
+	// LargeBlob 登入時可讀取 (read) 或寫入 (write) 驗證器上的資料，寫入需指定使用者名稱
+	LargeBlob *LargeBlobInput `json:"largeBlob,omitzero"`
+}
+
+// LargeBlobInput largeBlob 擴充的輸入
+type LargeBlobInput struct {
+	Support string                    `json:"support,omitzero"`
+	Read    bool                      `json:"read,omitzero"`
+	Write   protocol.URLEncodedBase64 `json:"write,omitzero"`
+}
+
+// ClientExtensionResults 用戶端回傳的擴充結果 (getClientExtensionResults)
+// 未知的擴充會被忽略，PRF 輸出與 largeBlob 內容為用戶端的秘密資料，伺服器只記錄是否有回傳
+type ClientExtensionResults struct {
+	CredProps *CredPropsOutput `json:"credProps,omitzero"`
+	PRF       *PRFOutput       `json:"prf,omitzero"`
+	LargeBlob *LargeBlobOutput `json:"largeBlob,omitzero"`
+
+	// AppID 登入時用戶端是否以 AppID 取代 RP ID 計算 rpIdHash (舊版 U2F Credential)
+	AppID *bool `json:"appid,omitzero"`
+
+	// AppIDExclude 註冊時用戶端是否以 AppID 比對 excludeCredentials
+	AppIDExclude *bool `json:"appidExclude,omitzero"`
+}
+
+// CredPropsOutput credProps 擴充的結果
+type CredPropsOutput struct {
+	ResidentKey *bool `json:"rk,omitzero"`
+}
+
+// PRFOutput prf 擴充的結果
+type PRFOutput struct {
+	Enabled *bool       `json:"enabled,omitzero"`
+	Results *PRFResults `json:"results,omitzero"`
+}
+
+// PRFResults PRF 輸出，不解析也不保存內容
+type PRFResults struct{}
+
+// LargeBlobOutput largeBlob 擴充的結果
+type LargeBlobOutput struct {
+	Supported *bool `json:"supported,omitzero"`
+	Written   *bool `json:"written,omitzero"`
+}
+
+// ToProtocol 轉換為 go-webauthn 使用的擴充結果
+func (r ClientExtensionResults) ToProtocol() protocol.AuthenticationExtensionsClientOutputs {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil
+	}
+	var outputs protocol.AuthenticationExtensionsClientOutputs
+	if err := json.Unmarshal(data, &outputs); err != nil {
+		return nil
+	}
+	return outputs
+}
\ No newline at end of file
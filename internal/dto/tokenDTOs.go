@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"fido2/pkg/utils/common"
+)
+
+type TokenResponse struct {
+	common.CommonResponse
+	AccessToken  string `json:"accessToken,omitzero"`
+	TokenType    string `json:"tokenType,omitzero"`
+	ExpiresIn    int    `json:"expiresIn,omitzero"`
+	RefreshToken string `json:"refreshToken,omitzero"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
\ No newline at end of file
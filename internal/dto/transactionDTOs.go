@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"fido2/internal/entity"
+	"fido2/pkg/utils/common"
+)
+
+// CreateTransactionRequest 建立需要以 Passkey 確認的交易
+type CreateTransactionRequest struct {
+	Payee    string `json:"payee" binding:"required"`
+	Amount   string `json:"amount" binding:"required"`
+	Currency string `json:"currency" binding:"required"`
+}
+
+// TransactionResponse 交易內容與狀態，已確認的交易附上不可否認性紀錄
+type TransactionResponse struct {
+	common.CommonResponse
+	Transaction *entity.Transaction          `json:"transaction"`
+	Signature   *entity.TransactionSignature `json:"signature,omitzero"`
+}
+
+type TransactionListResponse struct {
+	common.CommonResponse
+	Transactions []*entity.Transaction `json:"transactions"`
+}
\ No newline at end of file
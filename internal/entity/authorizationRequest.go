@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+// AuthorizationRequest OIDC 授權請求，Passkey 驗證完成後綁定使用者並產生 Authorization Code
+type AuthorizationRequest struct {
+	// ID 授權請求 ID，由 /authorize 頁面帶入 WebAuthn 登入流程
+	ID string `json:"id,omitzero" gorm:"primaryKey"`
+
+	// TenantID 授權請求所屬的租戶
+	TenantID string `json:"tenantId,omitzero" gorm:"index"`
+
+	// ClientID 發起授權的 client_id
+	ClientID string `json:"clientId,omitzero" gorm:"index"`
+
+	// RedirectURI 完成後導回的 redirect_uri
+	RedirectURI string `json:"redirectUri,omitzero"`
+
+	// Scope 核准的 scope
+	Scope string `json:"scope,omitzero"`
+
+	// State Client 帶入的 state，導回時原樣傳回
+	State string `json:"state,omitzero"`
+
+	// Nonce Client 帶入的 nonce，放入 ID Token
+	Nonce string `json:"nonce,omitzero"`
+
+	// CodeChallenge PKCE code_challenge
+	CodeChallenge string `json:"codeChallenge,omitzero"`
+
+	// CodeChallengeMethod PKCE code_challenge_method
+	CodeChallengeMethod string `json:"codeChallengeMethod,omitzero"`
+
+	// UserID 完成驗證的使用者 ID
+	UserID string `json:"userId,omitzero"`
+
+	// CredentialID 驗證所使用的 Credential ID
+	CredentialID string `json:"credentialId,omitzero"`
+
+	// AMR 依驗證器旗標決定的驗證方式
+	AMR []string `json:"amr,omitzero" gorm:"serializer:json"`
+
+	// ACR 驗證強度等級
+	ACR string `json:"acr,omitzero"`
+
+	// AuthTime 實際完成 WebAuthn 驗證的時間
+	AuthTime *time.Time `json:"authTime,omitzero"`
+
+	// CodeHash Authorization Code 的 SHA-256 雜湊值，驗證完成前為空
+	CodeHash string `json:"-" gorm:"index"`
+
+	// ExpiresAt 授權請求或 Authorization Code 的過期時間
+	ExpiresAt time.Time `json:"expiresAt,omitzero" gorm:"index"`
+
+	// RedeemedAt Authorization Code 兌換時間，未兌換時為 nil
+	RedeemedAt *time.Time `json:"redeemedAt,omitzero"`
+
+	// RefreshTokenFamilyID 兌換 Authorization Code 時開始的 Refresh Token 家族，Code 被重複使用時整批撤銷
+	RefreshTokenFamilyID string `json:"-" gorm:"index"`
+
+	// CreatedAt 建立時間
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+}
+
+// TableName 設定資料庫表名
+func (*AuthorizationRequest) TableName() string {
+	return "authorization_request"
+}
\ No newline at end of file
@@ -0,0 +1,85 @@
+package entity
+
+import "time"
+
+type Credential struct {
+	// ID Credential ID (base64url 編碼)
+	ID string `json:"id,omitzero" gorm:"primaryKey"`
+
+	// TenantID Credential 所屬的租戶
+	TenantID string `json:"tenantId,omitzero" gorm:"index;not null;default:default"`
+
+	// UserID 擁有此 Credential 的使用者 ID
+	UserID string `json:"userId,omitzero" gorm:"index"`
+
+	// PublicKey Credential 的公鑰 (COSE 格式，匯入的 U2F 金鑰為未壓縮格式的 P-256 公鑰)
+	PublicKey []byte `json:"publicKey,omitzero"`
+
+	// AAGUID 驗證器型號的識別碼
+	AAGUID []byte `json:"aaguid,omitzero"`
+
+	// SignCount 驗證器回傳的簽章計數器
+	SignCount uint32 `json:"signCount,omitzero"`
+
+	// CloneWarning 簽章計數器異常時標記可能遭複製
+	CloneWarning bool `json:"cloneWarning,omitzero"`
+
+	// Attachment 驗證器連接方式 (platform / cross-platform)
+	Attachment string `json:"attachment,omitzero"`
+
+	// Transports 驗證器支援的傳輸方式
+	Transports []string `json:"transports,omitzero" gorm:"serializer:json"`
+
+	// UserPresent 註冊時的 UP 旗標
+	UserPresent bool `json:"userPresent,omitzero"`
+
+	// UserVerified 註冊時的 UV 旗標
+	UserVerified bool `json:"userVerified,omitzero"`
+
+	// BackupEligible 是否可備份 / 同步 (BE 旗標)
+	BackupEligible bool `json:"backupEligible,omitzero"`
+
+	// BackupState 是否已備份 / 同步 (BS 旗標)
+	BackupState bool `json:"backupState,omitzero"`
+
+	// AttestationType 註冊時使用的 Attestation 格式
+	AttestationType string `json:"attestationType,omitzero"`
+
+	// Discoverable credProps 擴充回報的是否為 discoverable credential，nil 表示未回報
+	Discoverable *bool `json:"discoverable,omitzero"`
+
+	// PRFEnabled 驗證器是否支援 PRF 擴充
+	PRFEnabled bool `json:"prfEnabled,omitzero"`
+
+	// PRFSalt 此 Credential 專屬的 PRF salt，登入時透過 evalByCredential 傳給驗證器
+	PRFSalt []byte `json:"-"`
+
+	// LargeBlobSupported 驗證器是否支援 largeBlob 擴充
+	LargeBlobSupported bool `json:"largeBlobSupported,omitzero"`
+
+	// Payment 是否以 payment 擴充註冊，只有此類 Credential 可用於 Secure Payment Confirmation
+	Payment bool `json:"payment,omitzero"`
+
+	// EnterpriseAttestation 註冊時是否通過企業 Attestation 驗證
+	EnterpriseAttestation bool `json:"enterpriseAttestation,omitzero"`
+
+	// DeviceSerialNumber 企業 Attestation 憑證中可識別個別裝置的序號
+	DeviceSerialNumber string `json:"deviceSerialNumber,omitzero" gorm:"index"`
+
+	// AttestationCertificate 企業 Attestation 的裝置憑證 (DER)，只透過管理者 API 提供
+	AttestationCertificate []byte `json:"-"`
+
+	// Nickname 使用者自訂的名稱
+	Nickname string `json:"nickname,omitzero"`
+
+	// CreatedAt 建立時間
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+
+	// LastUsedAt 最後一次登入使用的時間
+	LastUsedAt *time.Time `json:"lastUsedAt,omitzero"`
+}
+
+// TableName 設定資料庫表名
+func (*Credential) TableName() string {
+	return "credential"
+}
\ No newline at end of file
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// OIDCClient 透過本伺服器以 Passkey 登入的 OIDC Client (Relying Party 應用程式)
+type OIDCClient struct {
+	// ID client_id
+	ID string `json:"clientId,omitzero" gorm:"primaryKey"`
+
+	// TenantID Client 所屬的租戶
+	TenantID string `json:"tenantId,omitzero" gorm:"index;not null;default:default"`
+
+	// Name Client 名稱，顯示於登入頁面
+	Name string `json:"name,omitzero"`
+
+	// SecretHash Client Secret 的 SHA-256 雜湊值，Public Client 為空
+	SecretHash string `json:"-"`
+
+	// Public 是否為無法保存 Secret 的 Public Client (SPA / 原生 App)，只能依靠 PKCE
+	Public bool `json:"public,omitzero"`
+
+	// RedirectURIs 已註冊的 redirect_uri，需完全相符
+	RedirectURIs []string `json:"redirectUris,omitzero" gorm:"serializer:json"`
+
+	// CreatedAt 建立時間
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+}
+
+// TableName 設定資料庫表名
+func (*OIDCClient) TableName() string {
+	return "oidc_client"
+}
\ No newline at end of file
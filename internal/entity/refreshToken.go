@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+// RefreshToken 伺服器端保存的 Refresh Token，只保存雜湊值
+type RefreshToken struct {
+	// ID Refresh Token 記錄 ID
+	ID string `json:"id,omitzero" gorm:"primaryKey"`
+
+	// TokenHash Refresh Token 的 SHA-256 雜湊值
+	TokenHash string `json:"-" gorm:"uniqueIndex"`
+
+	// FamilyID 同一次登入輪替出的 Refresh Token 共用的 ID，用於偵測重複使用並整批撤銷
+	FamilyID string `json:"familyId,omitzero" gorm:"index"`
+
+	// TenantID 使用者所屬的租戶
+	TenantID string `json:"tenantId,omitzero" gorm:"index"`
+
+	// UserID 使用者 ID
+	UserID string `json:"userId,omitzero" gorm:"index"`
+
+	// CredentialID 登入所使用的 Credential ID
+	CredentialID string `json:"credentialId,omitzero"`
+
+	// ACR 登入時的驗證強度等級，輪替時沿用
+	ACR string `json:"acr,omitzero"`
+
+	// AuthTime 實際完成 WebAuthn 驗證的時間，輪替時沿用
+	AuthTime time.Time `json:"authTime,omitzero"`
+
+	// Scope OIDC Client 取得的 scope，輪替時沿用；直接以 WebAuthn 登入取得時為空
+	Scope string `json:"scope,omitzero"`
+
+	// ExpiresAt Refresh Token 過期時間
+	ExpiresAt time.Time `json:"expiresAt,omitzero" gorm:"index"`
+
+	// RevokedAt 撤銷時間，未撤銷時為 nil
+	RevokedAt *time.Time `json:"revokedAt,omitzero"`
+
+	// ReplacedBy 輪替後取代此 Token 的記錄 ID
+	ReplacedBy string `json:"replacedBy,omitzero"`
+
+	// CreatedAt 建立時間
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+}
+
+// TableName 設定資料庫表名
+func (*RefreshToken) TableName() string {
+	return "refresh_token"
+}
\ No newline at end of file
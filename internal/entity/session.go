@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// WebAuthnSession 以 Ceremony ID 為鍵保存的 WebAuthn SessionData
+type WebAuthnSession struct {
+	// ID 伺服器發出的 Ceremony ID
+	ID string `gorm:"primaryKey"`
+
+	// Data 序列化後的 webauthn.SessionData
+	Data string `gorm:"type:text"`
+
+	// ExpiresAt Session 過期時間
+	ExpiresAt time.Time `gorm:"index"`
+}
+
+// TableName 設定資料庫表名
+func (*WebAuthnSession) TableName() string {
+	return "webauthn_session"
+}
\ No newline at end of file
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// SigningKey 簽署 JWT 用的非對稱金鑰，私鑰以加密後的形式保存
+type SigningKey struct {
+	// ID 金鑰 ID，即 JWT header 中的 kid
+	ID string `json:"kid,omitzero" gorm:"primaryKey"`
+
+	// Algorithm 簽章演算法 (ES256 / EdDSA)
+	Algorithm string `json:"alg,omitzero"`
+
+	// PrivateKey 以 AES-GCM 加密的 PKCS#8 私鑰 (nonce || ciphertext)
+	PrivateKey []byte `json:"-"`
+
+	// PublicKey PKIX 格式的公鑰
+	PublicKey []byte `json:"-"`
+
+	// CreatedAt 建立時間，最新且未退役的金鑰用於簽署
+	CreatedAt time.Time `json:"createdAt,omitzero" gorm:"index"`
+
+	// RetiredAt 停止用於簽署的時間，未退役時為 nil
+	RetiredAt *time.Time `json:"retiredAt,omitzero"`
+
+	// ExpiresAt 停止於 JWKS 公開的時間，需晚於此金鑰簽出的 Token 到期時間
+	ExpiresAt *time.Time `json:"expiresAt,omitzero" gorm:"index"`
+}
+
+// TableName 設定資料庫表名
+func (*SigningKey) TableName() string {
+	return "signing_key"
+}
\ No newline at end of file
@@ -0,0 +1,112 @@
+package entity
+
+import "time"
+
+// TransactionStatus 交易確認的狀態
+type TransactionStatus string
+
+const (
+	// TransactionPending 等待使用者以 Passkey 確認
+	TransactionPending TransactionStatus = "pending"
+
+	// TransactionConfirmed 使用者已簽署交易
+	TransactionConfirmed TransactionStatus = "confirmed"
+
+	// TransactionExpired 超過確認期限仍未簽署
+	TransactionExpired TransactionStatus = "expired"
+
+	// TransactionRejected 使用者拒絕交易
+	TransactionRejected TransactionStatus = "rejected"
+)
+
+// Transaction 需要使用者以 Passkey 確認的交易 (PSD2 動態連結)，登入選項的 Challenge 由交易內容的雜湊值產生
+type Transaction struct {
+	// ID 交易 ID
+	ID string `json:"id,omitzero" gorm:"primaryKey"`
+
+	// TenantID 交易所屬的租戶
+	TenantID string `json:"tenantId,omitzero" gorm:"index"`
+
+	// UserID 需確認交易的使用者 ID
+	UserID string `json:"userId,omitzero" gorm:"index"`
+
+	// Payee 收款方
+	Payee string `json:"payee,omitzero"`
+
+	// Amount 金額，十進位字串
+	Amount string `json:"amount,omitzero"`
+
+	// Currency ISO 4217 幣別代碼
+	Currency string `json:"currency,omitzero"`
+
+	// Nonce 伺服器產生的隨機值，確保相同內容的交易有不同的 Challenge
+	Nonce string `json:"nonce,omitzero"`
+
+	// Hash 交易內容的 SHA-256 雜湊值 (base64url)，即登入選項的 Challenge
+	Hash string `json:"hash,omitzero" gorm:"uniqueIndex"`
+
+	// Status 交易狀態
+	Status TransactionStatus `json:"status,omitzero" gorm:"index"`
+
+	// ExpiresAt 確認期限
+	ExpiresAt time.Time `json:"expiresAt,omitzero" gorm:"index"`
+
+	// ConfirmedAt 使用者簽署交易的時間，未確認時為 nil
+	ConfirmedAt *time.Time `json:"confirmedAt,omitzero"`
+
+	// CreatedAt 建立時間
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+
+	// UpdatedAt 更新時間
+	UpdatedAt time.Time `json:"updatedAt,omitzero"`
+}
+
+// TableName 設定資料庫表名
+func (*Transaction) TableName() string {
+	return "transaction"
+}
+
+// TransactionSignature 交易確認的不可否認性紀錄，保存驗證器對交易雜湊值的簽章
+// 以 Credential 公鑰對 authenticatorData || SHA-256(clientDataJSON) 驗證 Signature 即可重現簽署結果
+type TransactionSignature struct {
+	// ID 紀錄 ID
+	ID string `json:"id,omitzero" gorm:"primaryKey"`
+
+	// TransactionID 確認的交易 ID，每筆交易只會有一筆紀錄
+	TransactionID string `json:"transactionId,omitzero" gorm:"uniqueIndex"`
+
+	// TenantID 交易所屬的租戶
+	TenantID string `json:"tenantId,omitzero" gorm:"index"`
+
+	// UserID 簽署交易的使用者 ID
+	UserID string `json:"userId,omitzero" gorm:"index"`
+
+	// CredentialID 簽署所使用的 Credential ID
+	CredentialID string `json:"credentialId,omitzero"`
+
+	// TransactionHash 簽署時的交易雜湊值
+	TransactionHash string `json:"transactionHash,omitzero"`
+
+	// ClientDataJSON 驗證器回傳的 clientDataJSON 原文，其中的 challenge 即交易雜湊值
+	ClientDataJSON []byte `json:"clientDataJSON,omitzero"`
+
+	// AuthenticatorData 驗證器回傳的 authenticatorData
+	AuthenticatorData []byte `json:"authenticatorData,omitzero"`
+
+	// Signature 驗證器的簽章
+	Signature []byte `json:"signature,omitzero"`
+
+	// UserVerified 簽署時是否完成使用者驗證
+	UserVerified bool `json:"userVerified"`
+
+	// SignCount 簽署時的簽章計數器
+	SignCount uint32 `json:"signCount"`
+
+	// CreatedAt 簽署時間
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+}
+
+// TableName 設定資料庫表名
+func (*TransactionSignature) TableName() string {
+	return "transaction_signature"
+}
\ No newline at end of file
@@ -1,20 +1,43 @@
 package entity
 
+import "time"
+
 type User struct {
 	// ID 使用者 ID
 	ID string `json:"userId,omitzero" gorm:"primaryKey"`
 
-	// UserName 使用者名稱
-	UserName string `json:"name,omitzero"`
+	// TenantID 使用者所屬的租戶，不同租戶的使用者彼此隔離
+	TenantID string `json:"tenantId,omitzero" gorm:"index;not null;default:default"`
+
+	// UserHandle 隨機產生的 WebAuthn user handle，作為驗證器上的 user.id
+	UserHandle []byte `json:"userHandle,omitzero" gorm:"uniqueIndex"`
+
+	// LegacyUserHandle 舊版以使用者名稱作為 user handle 的別名，讓既有 Passkey 仍可登入
+	LegacyUserHandle []byte `json:"legacyUserHandle,omitzero" gorm:"index"`
+
+	// UserName 正規化後的使用者名稱，同一租戶內唯一 (idx_user_tenant_username)
+	UserName string `json:"name,omitzero" gorm:"index"`
 
 	// DisplayName 使用者的顯示名稱
 	DisplayName string `json:"displayName,omitzero"`
 
+	// RecoveryEmail 已驗證的備援 Email，設定後才允許刪除最後一個 Credential
+	RecoveryEmail string `json:"recoveryEmail,omitzero"`
+
+	// Flagged 帳號是否因 Credential 疑似被複製而被標記，需人工審查
+	Flagged bool `json:"flagged,omitzero" gorm:"index"`
+
+	// ReservedUntil 尚未完成註冊時使用者名稱的保留期限，完成註冊後為 nil；過期後可由其他人重新註冊
+	ReservedUntil *time.Time `json:"reservedUntil,omitzero" gorm:"index"`
+
 	// Challenge 當次進行 WebAuthn 註冊 / 驗證流程時的使用者 Challenge
-	Challenge string `json:"challenge,omitzero"`
+	Challenge string `json:"challenge,omitzero" gorm:"index"`
+
+	// ChallengeIssuedAt Challenge 的簽發時間
+	ChallengeIssuedAt *time.Time `json:"challengeIssuedAt,omitzero"`
 
-	// Credential 使用者的 WebAuthn Credential
-	Credential string `json:"credential,oomitzero"`
+	// ChallengeExpiresAt Challenge 的有效期限，依 RP 設定的註冊 / 登入逾時時間計算，過期後由背景清理程序清除
+	ChallengeExpiresAt *time.Time `json:"challengeExpiresAt,omitzero" gorm:"index"`
 }
 
 // TableName 設定資料庫表名
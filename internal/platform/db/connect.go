@@ -89,7 +89,7 @@ func Connect() {
 
 		var gormDB *gorm.DB
 		for i := 0; i < 5; i++ {
-			gormDB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+			gormDB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 			if err == nil {
 				break
 			}
@@ -99,11 +99,29 @@ func Connect() {
 			panic(fmt.Sprintf("failed to connect database after retries: %v", err))
 		}
 
-		// 3. 只 AutoMigrate User 這一張表
-		if err := gormDB.AutoMigrate(&entity.User{}); err != nil {
+		// 3. AutoMigrate User、Credential、WebAuthn Session、Token、簽章金鑰、OIDC 與交易確認資料表
+		if err := gormDB.AutoMigrate(
+			&entity.User{}, &entity.Credential{}, &entity.WebAuthnSession{}, &entity.RefreshToken{}, &entity.SigningKey{},
+			&entity.OIDCClient{}, &entity.AuthorizationRequest{}, &entity.Transaction{}, &entity.TransactionSignature{},
+		); err != nil {
 			utils.GetLogger().Fatalf("failed to auto migrate: %v", err)
 		}
-		utils.GetLogger().Info("User table migrated successfully")
+		utils.GetLogger().Info("User, credential, session, token, signing key, OIDC and transaction tables migrated successfully")
+
+		// 4. 搬移舊版存於 user.credential 欄位的憑證
+		if err := migrateLegacyCredentials(gormDB); err != nil {
+			utils.GetLogger().Fatalf("failed to migrate legacy credentials: %v", err)
+		}
+
+		// 5. 為既有使用者產生隨機 user handle
+		if err := migrateUserHandles(gormDB); err != nil {
+			utils.GetLogger().Fatalf("failed to migrate user handles: %v", err)
+		}
+
+		// 6. 正規化既有使用者名稱並建立租戶內唯一的使用者名稱索引
+		if err := migrateUsernames(gormDB); err != nil {
+			utils.GetLogger().Fatalf("failed to migrate usernames: %v", err)
+		}
 
 		instance = &dbContext{db: gormDB}
 	})
@@ -9,7 +9,7 @@ import (
 )
 
 func TestConnect_Postgres(t *testing.T) {
-	if err := utils.LoadEnv; err != nil {
+	if err := utils.LoadEnv(); err != nil {
 		t.Fatalf("讀取 .env 失敗: %v", err)
 	}
 
@@ -25,7 +25,7 @@ func TestConnect_Postgres(t *testing.T) {
 		t.Fatal("資料庫連線為 nil")
 	}
 
-	if err := conn.AutoMigrate(&entity.User{}); err != nil {
+	if err := conn.AutoMigrate(&entity.User{}, &entity.Credential{}); err != nil {
 		t.Fatalf("AutoMigrate 失敗: %v", err)
 	}
 }
\ No newline at end of file
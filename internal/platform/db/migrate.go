@@ -0,0 +1,219 @@
+package db
+
+import (
+	"encoding/json"
+	"fido2/config"
+	"fido2/internal/entity"
+	"fido2/internal/platform/username"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/pkg/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// legacyCredentialColumn 舊版以 JSON 字串保存憑證的 user 欄位
+	legacyCredentialColumn = "credential"
+
+	// usernameIndex 租戶內唯一的使用者名稱索引
+	usernameIndex = "idx_user_tenant_username"
+)
+
+// migrateLegacyCredentials 將舊版 user.credential 字串欄位的憑證搬移至 credential 資料表
+// 搬移完成後會移除該欄位，因此只會執行一次
+func migrateLegacyCredentials(gormDB *gorm.DB) error {
+	if !gormDB.Migrator().HasColumn(&entity.User{}, legacyCredentialColumn) {
+		return nil
+	}
+
+	var rows []struct {
+		ID         string
+		Credential string
+	}
+	if err := gormDB.Table("user").
+		Select("id, " + legacyCredentialColumn).
+		Where(legacyCredentialColumn + " IS NOT NULL AND " + legacyCredentialColumn + " <> ''").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	migrated := 0
+	err := gormDB.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			// 跳過沒有有效憑證的使用者
+			if row.Credential == "`{}`" {
+				continue
+			}
+
+			for _, credential := range parseLegacyCredential(row.Credential) {
+				if len(credential.ID) == 0 {
+					continue
+				}
+				record := wAuth.NewCredentialEntity(config.DefaultTenantID, row.ID, &credential)
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record).Error; err != nil {
+					return err
+				}
+				migrated++
+			}
+		}
+
+		return tx.Migrator().DropColumn(&entity.User{}, legacyCredentialColumn)
+	})
+	if err != nil {
+		return err
+	}
+
+	utils.GetLogger().Infof("Migrated %d legacy credentials from %d users", migrated, len(rows))
+	return nil
+}
+
+// migrateUserHandles 為尚未擁有隨機 user handle 的使用者產生 user handle
+// 舊版以使用者名稱作為 user handle，保留為別名讓既有 Passkey 仍可登入
+func migrateUserHandles(gormDB *gorm.DB) error {
+	var users []*entity.User
+	if err := gormDB.Where("user_handle IS NULL").Find(&users).Error; err != nil {
+		return err
+	}
+
+	err := gormDB.Transaction(func(tx *gorm.DB) error {
+		for _, user := range users {
+			handle, err := wAuth.NewUserHandle()
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(user).Updates(entity.User{
+				UserHandle:       handle,
+				LegacyUserHandle: []byte(user.UserName),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(users) > 0 {
+		utils.GetLogger().Infof("Generated user handles for %d users", len(users))
+	}
+	return nil
+}
+
+// migrateUsernames 正規化既有的使用者名稱並建立租戶內唯一的使用者名稱索引
+// 舊版重複呼叫註冊 API 會留下同名且沒有 Credential 的使用者，正規化後重複者直接刪除；
+// 沒有 Credential 的使用者改為已過期的保留，可被重新註冊。索引建立後就不會再執行
+func migrateUsernames(gormDB *gorm.DB) error {
+	if gormDB.Migrator().HasIndex(&entity.User{}, usernameIndex) {
+		return nil
+	}
+
+	var users []*entity.User
+	if err := gormDB.Order("id").Find(&users).Error; err != nil {
+		return err
+	}
+
+	var registeredIDs []string
+	if err := gormDB.Model(&entity.Credential{}).Distinct("user_id").Pluck("user_id", &registeredIDs).Error; err != nil {
+		return err
+	}
+	registered := make(map[string]bool, len(registeredIDs))
+	for _, id := range registeredIDs {
+		registered[id] = true
+	}
+
+	// 已有 Credential 的使用者優先保留原本的使用者名稱
+	sort.SliceStable(users, func(i, j int) bool {
+		return registered[users[i].ID] && !registered[users[j].ID]
+	})
+
+	removed, renamed := 0, 0
+	now := time.Now()
+	err := gormDB.Transaction(func(tx *gorm.DB) error {
+		seen := make(map[string]bool, len(users))
+		for _, user := range users {
+			normalized, err := username.Normalize(user.UserName)
+			if err != nil {
+				normalized = user.UserName
+			}
+
+			key := user.TenantID + "\x00" + normalized
+			if seen[key] {
+				if !registered[user.ID] {
+					if err := tx.Delete(&entity.User{}, "id = ?", user.ID).Error; err != nil {
+						return err
+					}
+					removed++
+					continue
+				}
+
+				// 不同帳號都已註冊 Passkey 時保留兩者，後者改名後由管理者處理
+				utils.GetLogger().Warnf("Username %q of user %s conflicts after normalization, renaming", user.UserName, user.ID)
+				normalized = normalized + "#" + user.ID
+				key = user.TenantID + "\x00" + normalized
+				renamed++
+			}
+			seen[key] = true
+
+			updates := map[string]interface{}{"user_name": normalized}
+			if !registered[user.ID] && user.ReservedUntil == nil {
+				updates["reserved_until"] = now
+			}
+			if err := tx.Model(&entity.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Exec(`CREATE UNIQUE INDEX ` + usernameIndex + ` ON "user" (tenant_id, user_name)`).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	utils.GetLogger().Infof("Normalized %d usernames, removed %d duplicate registrations and renamed %d conflicting users", len(users), removed, renamed)
+	return nil
+}
+
+// parseLegacyCredential 解析舊版單一使用者的憑證字串
+func parseLegacyCredential(credentialStr string) []webauthn.Credential {
+	logger := utils.GetLogger()
+
+	// 處理可能帶有反引號的憑證字串
+	unquoted := credentialStr
+	if strings.HasPrefix(credentialStr, "`") {
+		s, err := strconv.Unquote(credentialStr)
+		if err != nil {
+			logger.Debugf("Unquote failed for credential, using original: %v", err)
+			// 移除開頭和結尾的反引號
+			if len(credentialStr) > 2 {
+				unquoted = credentialStr[1 : len(credentialStr)-1]
+			}
+		} else {
+			unquoted = s
+		}
+	}
+
+	// 嘗試多種格式解析憑證
+
+	// 嘗試解析為憑證陣列
+	var credentials []webauthn.Credential
+	if err := json.Unmarshal([]byte(unquoted), &credentials); err == nil {
+		return credentials
+	}
+
+	// 嘗試解析為單一憑證
+	var credential webauthn.Credential
+	if err := json.Unmarshal([]byte(unquoted), &credential); err == nil {
+		return []webauthn.Credential{credential}
+	}
+
+	// 解析失敗時記錄警告
+	logger.Warnf("Failed to parse legacy credential string: %s", credentialStr)
+	return []webauthn.Credential{}
+}
\ No newline at end of file
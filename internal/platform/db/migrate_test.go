@@ -0,0 +1,38 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestParseLegacyCredential(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantCount int
+	}{
+		{
+			name:      "反引號包住的單一憑證",
+			input:     "`" + `{"id":"Y3JlZA==","publicKey":"cGs=","attestationType":"none"}` + "`",
+			wantCount: 1,
+		},
+		{
+			name:      "憑證陣列",
+			input:     `[{"id":"Y3JlZA=="},{"id":"Y3JlZDI="}]`,
+			wantCount: 2,
+		},
+		{
+			name:      "無效字串",
+			input:     "not-json",
+			wantCount: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseLegacyCredential(tc.input)
+			if len(got) != tc.wantCount {
+				t.Errorf("憑證數量錯誤：got=%d want=%d", len(got), tc.wantCount)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,216 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fido2/internal/platform/token"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// AuthorizationRequestTTL /authorize 頁面完成 Passkey 驗證的時限
+	AuthorizationRequestTTL = 10 * time.Minute
+
+	// AuthorizationCodeTTL Authorization Code 的有效時間
+	AuthorizationCodeTTL = time.Minute
+
+	// ScopeOpenID OIDC 必要的 scope
+	ScopeOpenID = "openid"
+
+	// ScopeProfile 回傳使用者名稱等基本資料的 scope
+	ScopeProfile = "profile"
+
+	// ResponseTypeCode 只支援 Authorization Code Flow
+	ResponseTypeCode = "code"
+
+	// GrantTypeAuthorizationCode /token 支援的 grant_type
+	GrantTypeAuthorizationCode = "authorization_code"
+
+	// CodeChallengeMethodS256 只支援 S256 的 PKCE
+	CodeChallengeMethodS256 = "S256"
+
+	// randomLength Authorization Code 與 Client Secret 的隨機位元組長度
+	randomLength = 32
+)
+
+// OAuth 2.0 / OIDC 錯誤代碼 (RFC 6749 5.2、OIDC Core 3.1.2.6)
+const (
+	ErrorInvalidRequest          = "invalid_request"
+	ErrorInvalidClient           = "invalid_client"
+	ErrorInvalidGrant            = "invalid_grant"
+	ErrorInvalidScope            = "invalid_scope"
+	ErrorInvalidToken            = "invalid_token"
+	ErrorUnsupportedResponseType = "unsupported_response_type"
+	ErrorUnsupportedGrantType    = "unsupported_grant_type"
+)
+
+var (
+	// ErrUnknownClient client_id 不存在或不屬於目前的租戶，不可導回 redirect_uri
+	ErrUnknownClient = errors.New("unknown client_id")
+
+	// ErrInvalidRedirectURI redirect_uri 未註冊，不可導回
+	ErrInvalidRedirectURI = errors.New("redirect_uri is not registered for this client")
+
+	// ErrAuthorizationRequestNotFound 授權請求不存在、已過期或已完成
+	ErrAuthorizationRequestNotFound = errors.New("authorization request not found or expired")
+)
+
+// Error 回傳給 Client 的 OAuth 錯誤
+type Error struct {
+	Code        string
+	Description string
+}
+
+// NewError 建立 OAuth 錯誤
+func NewError(code, description string) *Error {
+	return &Error{Code: code, Description: description}
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// IDClaims ID Token 內容
+type IDClaims struct {
+	jwt.RegisteredClaims
+
+	// Nonce Client 在授權請求中帶入的 nonce
+	Nonce string `json:"nonce,omitempty"`
+
+	// AuthTime 實際完成 WebAuthn 驗證的時間 (Unix 秒)
+	AuthTime int64 `json:"auth_time"`
+
+	// AMR 使用的驗證方式 (RFC 8176)
+	AMR []string `json:"amr"`
+
+	// ACR 驗證強度等級
+	ACR string `json:"acr"`
+
+	// PreferredUsername 使用者名稱，scope 含 profile 時提供
+	PreferredUsername string `json:"preferred_username,omitempty"`
+
+	// Name 顯示名稱，scope 含 profile 時提供
+	Name string `json:"name,omitempty"`
+}
+
+// UserInfo /userinfo 回傳的使用者資料
+type UserInfo struct {
+	Subject           string `json:"sub"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Name              string `json:"name,omitempty"`
+}
+
+// Tokens /token 簽發的 Token
+type Tokens struct {
+	*token.Pair
+
+	// IDToken 簽署後的 ID Token
+	IDToken string
+
+	// Scope 核准的 scope
+	Scope string
+}
+
+// AMR 依驗證器旗標產生 RFC 8176 的驗證方式
+// 裝置綁定的金鑰為 hwk，可同步的 Passkey 為 swk，UP 為 user，UV 則另外加上 mfa
+func AMR(flags webauthn.CredentialFlags) []string {
+	amr := []string{"hwk"}
+	if flags.BackupEligible {
+		amr[0] = "swk"
+	}
+	if flags.UserPresent {
+		amr = append(amr, "user")
+	}
+	if flags.UserVerified {
+		amr = append(amr, "mfa")
+	}
+	return amr
+}
+
+// HasScope 判斷以空白分隔的 scope 是否包含指定值
+func HasScope(scope, want string) bool {
+	for _, s := range strings.Fields(scope) {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyCodeChallenge 以 S256 驗證 PKCE code_verifier (RFC 7636 4.6)
+func VerifyCodeChallenge(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	for _, r := range verifier {
+		if !isUnreserved(r) {
+			return false
+		}
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// isUnreserved RFC 3986 unreserved 字元
+func isUnreserved(r rune) bool {
+	return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' ||
+		r == '-' || r == '.' || r == '_' || r == '~'
+}
+
+// BuildRedirectURI 將參數加到 redirect_uri 的 query 中，保留原有的 query
+func BuildRedirectURI(redirectURI string, params url.Values) (string, error) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	for key, values := range params {
+		for _, value := range values {
+			if value != "" {
+				query.Add(key, value)
+			}
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// NewAuthorizationCode 產生隨機的 Authorization Code
+func NewAuthorizationCode() (string, error) {
+	return randomString()
+}
+
+// NewClientSecret 產生隨機的 Client Secret
+func NewClientSecret() (string, error) {
+	return randomString()
+}
+
+// Hash 計算 Authorization Code 或 Client Secret 的雜湊值，資料庫只保存雜湊值
+func Hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyClientSecret 以固定時間比對 Client Secret
+func VerifyClientSecret(secret, secretHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(secret)), []byte(secretHash)) == 1
+}
+
+// randomString 產生 base64url 編碼的隨機字串
+func randomString() (string, error) {
+	b := make([]byte, randomLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
\ No newline at end of file
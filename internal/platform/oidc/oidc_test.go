@@ -0,0 +1,77 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+func TestAMR(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags webauthn.CredentialFlags
+		want  []string
+	}{
+		{"硬體金鑰且 UV", webauthn.CredentialFlags{UserPresent: true, UserVerified: true}, []string{"hwk", "user", "mfa"}},
+		{"硬體金鑰僅 UP", webauthn.CredentialFlags{UserPresent: true}, []string{"hwk", "user"}},
+		{"同步的 Passkey", webauthn.CredentialFlags{UserPresent: true, UserVerified: true, BackupEligible: true}, []string{"swk", "user", "mfa"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AMR(tt.flags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AMR 錯誤，got=%v want=%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	verifier := strings.Repeat("a", 43)
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	if !VerifyCodeChallenge(verifier, challenge) {
+		t.Errorf("正確的 code_verifier 應驗證成功")
+	}
+	if VerifyCodeChallenge(strings.Repeat("b", 43), challenge) {
+		t.Errorf("錯誤的 code_verifier 應驗證失敗")
+	}
+	if VerifyCodeChallenge("short", challenge) {
+		t.Errorf("過短的 code_verifier 應驗證失敗")
+	}
+	if VerifyCodeChallenge(strings.Repeat("a", 42)+"!", challenge) {
+		t.Errorf("含非法字元的 code_verifier 應驗證失敗")
+	}
+}
+
+func TestBuildRedirectURI(t *testing.T) {
+	got, err := BuildRedirectURI("https://app.example.com/cb?foo=bar", url.Values{"code": {"abc"}, "state": {""}})
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+	u, _ := url.Parse(got)
+	if u.Query().Get("foo") != "bar" || u.Query().Get("code") != "abc" || u.Query().Has("state") {
+		t.Errorf("redirect_uri 錯誤，got=%s", got)
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	if !HasScope("openid profile", ScopeProfile) || HasScope("openid", ScopeProfile) {
+		t.Errorf("HasScope 判斷錯誤")
+	}
+}
+
+func TestVerifyClientSecret(t *testing.T) {
+	secret, err := NewClientSecret()
+	if err != nil {
+		t.Fatalf("產生 Client Secret 失敗: %v", err)
+	}
+	if !VerifyClientSecret(secret, Hash(secret)) || VerifyClientSecret("wrong", Hash(secret)) {
+		t.Errorf("Client Secret 比對錯誤")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,139 @@
+package reaper
+
+import (
+	"context"
+	"expvar"
+	"fido2/config"
+	"fido2/internal/repository"
+	"fido2/pkg/utils"
+	"time"
+)
+
+const (
+	// DefaultInterval 預設清理間隔
+	DefaultInterval = time.Minute
+
+	// DefaultRegistrationTTL 預設在開始註冊多久後仍未完成即視為放棄
+	DefaultRegistrationTTL = 15 * time.Minute
+)
+
+// metrics 透過 expvar 公開的清理統計，由 /admin/metrics 讀取
+var metrics = expvar.NewMap("reaper")
+
+// Config 背景清理程序設定
+type Config struct {
+	// Interval 清理間隔
+	Interval time.Duration
+
+	// RegistrationTTL 開始註冊後超過此時間仍沒有 Credential 的使用者會被刪除，不可短於使用者名稱保留時間
+	RegistrationTTL time.Duration
+
+	// ReservationTTL 使用者名稱保留時間，即所有租戶中最長的註冊逾時，未設定時只刪除保留已過期的使用者
+	ReservationTTL time.Duration
+}
+
+// Result 單次清理的結果
+type Result struct {
+	// UsersDeleted 刪除的未完成註冊使用者數
+	UsersDeleted int64
+
+	// ChallengesCleared 清除的過期 Challenge 數
+	ChallengesCleared int64
+}
+
+// Reaper 定期刪除未完成註冊的使用者並清除過期的 Challenge
+type Reaper struct {
+	users  repository.UserRepository
+	config Config
+}
+
+// New 建立 Reaper，未設定或無效的設定值使用預設值
+func New(users repository.UserRepository, config Config) *Reaper {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.RegistrationTTL <= 0 {
+		config.RegistrationTTL = DefaultRegistrationTTL
+	}
+	if config.ReservationTTL <= 0 {
+		config.ReservationTTL = config.RegistrationTTL
+	}
+	if config.RegistrationTTL < config.ReservationTTL {
+		config.RegistrationTTL = config.ReservationTTL
+	}
+	return &Reaper{users: users, config: config}
+}
+
+// ConfigFromEnv 由 REAPER_INTERVAL 與 REAPER_REGISTRATION_TTL 環境變數讀取設定
+func ConfigFromEnv() Config {
+	return Config{
+		Interval:        durationFromEnv("REAPER_INTERVAL", DefaultInterval),
+		RegistrationTTL: durationFromEnv("REAPER_REGISTRATION_TTL", DefaultRegistrationTTL),
+	}
+}
+
+// Run 每隔 Interval 執行一次清理，直到 ctx 結束；進行中的清理會完成後才返回
+func (r *Reaper) Run(ctx context.Context) {
+	utils.GetLogger().Infof("Reaper started, interval %s, registration TTL %s", r.config.Interval, r.config.RegistrationTTL)
+
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			utils.GetLogger().Info("Reaper stopped")
+			return
+		case <-ticker.C:
+			_, _ = r.Reap(time.Now())
+		}
+	}
+}
+
+// Reap 執行一次清理並記錄結果
+func (r *Reaper) Reap(now time.Time) (Result, error) {
+	var result Result
+	metrics.Add("runs", 1)
+
+	// 保留期限 = 開始註冊時間 + ReservationTTL，換算為開始註冊已超過 RegistrationTTL 的使用者
+	// 以最長的保留時間換算，註冊逾時較短的租戶只會晚一點被清除，不會刪除仍在保留期限內的使用者
+	reservedBefore := now.Add(r.config.ReservationTTL - r.config.RegistrationTTL)
+	deleted, err := r.users.DeleteAbandonedRegistrations(reservedBefore)
+	if err != nil {
+		metrics.Add("errors", 1)
+		utils.GetLogger().Errorf("Reaper failed to delete abandoned registrations: %v", err)
+		return result, err
+	}
+	result.UsersDeleted = deleted
+	metrics.Add("users_deleted", deleted)
+
+	cleared, err := r.users.ClearExpiredChallenges(now)
+	if err != nil {
+		metrics.Add("errors", 1)
+		utils.GetLogger().Errorf("Reaper failed to clear expired challenges: %v", err)
+		return result, err
+	}
+	result.ChallengesCleared = cleared
+	metrics.Add("challenges_cleared", cleared)
+
+	if result.UsersDeleted > 0 || result.ChallengesCleared > 0 {
+		utils.GetLogger().Infof("Reaper deleted %d abandoned registrations and cleared %d expired challenges", result.UsersDeleted, result.ChallengesCleared)
+	} else {
+		utils.GetLogger().Debug("Reaper found nothing to clean up")
+	}
+	return result, nil
+}
+
+// durationFromEnv 讀取時間長度的環境變數，未設定或無效時使用預設值
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := config.GetEnv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		utils.GetLogger().Warnf("Invalid %s %q, falling back to %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
\ No newline at end of file
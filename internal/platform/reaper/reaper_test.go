@@ -0,0 +1,82 @@
+package reaper
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fido2/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func metricValue(key string) int64 {
+	if v, ok := metrics.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestNew_Defaults(t *testing.T) {
+	r := New(nil, Config{})
+	assert.Equal(t, DefaultInterval, r.config.Interval)
+	assert.Equal(t, DefaultRegistrationTTL, r.config.RegistrationTTL)
+
+	// 未設定保留時間時只刪除保留已過期的使用者
+	assert.Equal(t, DefaultRegistrationTTL, r.config.ReservationTTL)
+
+	// 不可在保留期限內刪除仍在註冊中的使用者
+	r = New(nil, Config{RegistrationTTL: time.Minute, ReservationTTL: 10 * time.Minute})
+	assert.Equal(t, 10*time.Minute, r.config.RegistrationTTL)
+}
+
+func TestReap(t *testing.T) {
+	repo := repository.NewMockUserRepository(t)
+	r := New(repo, Config{RegistrationTTL: 15 * time.Minute, ReservationTTL: 10 * time.Minute})
+
+	// 保留期限早於 now - 5m 即開始註冊已超過 15m
+	now := time.Now()
+	repo.EXPECT().DeleteAbandonedRegistrations(now.Add(-5*time.Minute)).Return(3, nil)
+	repo.EXPECT().ClearExpiredChallenges(now).Return(2, nil)
+
+	deletedBefore, clearedBefore := metricValue("users_deleted"), metricValue("challenges_cleared")
+
+	result, err := r.Reap(now)
+	assert.NoError(t, err)
+	assert.Equal(t, Result{UsersDeleted: 3, ChallengesCleared: 2}, result)
+	assert.Equal(t, deletedBefore+3, metricValue("users_deleted"))
+	assert.Equal(t, clearedBefore+2, metricValue("challenges_cleared"))
+}
+
+func TestReap_Error(t *testing.T) {
+	repo := repository.NewMockUserRepository(t)
+	r := New(repo, Config{})
+
+	errorsBefore := metricValue("errors")
+	repo.EXPECT().DeleteAbandonedRegistrations(mock.AnythingOfType("time.Time")).Return(0, errors.New("db error"))
+
+	_, err := r.Reap(time.Now())
+	assert.Error(t, err)
+	assert.Equal(t, errorsBefore+1, metricValue("errors"))
+}
+
+func TestRun_StopsOnCancel(t *testing.T) {
+	repo := repository.NewMockUserRepository(t)
+	r := New(repo, Config{Interval: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("reaper did not stop after context was cancelled")
+	}
+}
\ No newline at end of file
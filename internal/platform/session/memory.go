@@ -0,0 +1,98 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+type memoryEntry struct {
+	data      webauthn.SessionData
+	expiresAt time.Time
+}
+
+// memoryStore 單機使用的 SessionStore，定期清除過期的 Session
+type memoryStore struct {
+	sync.Mutex
+	sessions map[string]memoryEntry
+}
+
+// NewMemoryStore 建立記憶體 SessionStore，並每隔 cleanupInterval 清除過期資料
+func NewMemoryStore(cleanupInterval time.Duration) SessionStore {
+	s := &memoryStore{
+		sessions: make(map[string]memoryEntry),
+	}
+
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.evictExpired()
+		}
+	}()
+
+	return s
+}
+
+func (s *memoryStore) Save(_ context.Context, id string, data *webauthn.SessionData, ttl time.Duration) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.sessions[id] = memoryEntry{
+		data:      *data,
+		expiresAt: time.Now().Add(ttl),
+	}
+	return nil
+}
+
+func (s *memoryStore) Get(_ context.Context, id string) (*webauthn.SessionData, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entry, ok := s.sessions[id]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(s.sessions, id)
+		return nil, ErrSessionNotFound
+	}
+
+	data := entry.data
+	return &data, nil
+}
+
+// Take 在同一個鎖內取出並刪除 Session，同時送出的請求只有一個能取得
+func (s *memoryStore) Take(_ context.Context, id string) (*webauthn.SessionData, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entry, ok := s.sessions[id]
+	delete(s.sessions, id)
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, ErrSessionNotFound
+	}
+
+	data := entry.data
+	return &data, nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.sessions, id)
+	return nil
+}
+
+// evictExpired 移除所有已過期的 Session
+func (s *memoryStore) evictExpired() {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	for id, entry := range s.sessions {
+		if now.After(entry.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(time.Minute)
+
+	// 存入後可依 Ceremony ID 取出
+	if err := store.Save(ctx, "ceremony-1", &webauthn.SessionData{Challenge: "c1"}, time.Minute); err != nil {
+		t.Fatalf("Save 失敗: %v", err)
+	}
+	if err := store.Save(ctx, "ceremony-2", &webauthn.SessionData{Challenge: "c2"}, time.Minute); err != nil {
+		t.Fatalf("Save 失敗: %v", err)
+	}
+
+	got, err := store.Get(ctx, "ceremony-1")
+	if err != nil {
+		t.Fatalf("Get 失敗: %v", err)
+	}
+	if got.Challenge != "c1" {
+		t.Errorf("Challenge 錯誤：got=%s want=c1", got.Challenge)
+	}
+
+	// 同時進行的 Ceremony 不會互相覆蓋
+	got, err = store.Get(ctx, "ceremony-2")
+	if err != nil || got.Challenge != "c2" {
+		t.Errorf("ceremony-2 被覆蓋：got=%+v err=%v", got, err)
+	}
+
+	// 刪除後不可再取得
+	if err := store.Delete(ctx, "ceremony-1"); err != nil {
+		t.Fatalf("Delete 失敗: %v", err)
+	}
+	if _, err := store.Get(ctx, "ceremony-1"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("預期 ErrSessionNotFound，got=%v", err)
+	}
+
+	// 過期後不可再取得
+	if err := store.Save(ctx, "ceremony-3", &webauthn.SessionData{Challenge: "c3"}, time.Millisecond); err != nil {
+		t.Fatalf("Save 失敗: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := store.Get(ctx, "ceremony-3"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("預期過期 Session 回傳 ErrSessionNotFound，got=%v", err)
+	}
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(time.Minute)
+
+	if err := store.Save(ctx, "ceremony-1", &webauthn.SessionData{Challenge: "c1"}, time.Minute); err != nil {
+		t.Fatalf("Save 失敗: %v", err)
+	}
+
+	// 同時以相同的 Ceremony ID 取出，只有一個請求能取得 Session
+	const workers = 50
+	var (
+		wg    sync.WaitGroup
+		taken atomic.Int32
+		start = make(chan struct{})
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			data, err := store.Take(ctx, "ceremony-1")
+			switch {
+			case err == nil && data.Challenge == "c1":
+				taken.Add(1)
+			case !errors.Is(err, ErrSessionNotFound):
+				t.Errorf("預期 ErrSessionNotFound，got=%v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if got := taken.Load(); got != 1 {
+		t.Errorf("Session 應只被取出一次，got=%d", got)
+	}
+	if _, err := store.Get(ctx, "ceremony-1"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("取出後 Session 應已刪除，got=%v", err)
+	}
+
+	// 過期的 Session 不可取出
+	if err := store.Save(ctx, "ceremony-2", &webauthn.SessionData{Challenge: "c2"}, time.Millisecond); err != nil {
+		t.Fatalf("Save 失敗: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := store.Take(ctx, "ceremony-2"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("預期過期 Session 回傳 ErrSessionNotFound，got=%v", err)
+	}
+}
\ No newline at end of file
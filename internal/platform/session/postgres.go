@@ -0,0 +1,81 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fido2/internal/entity"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// postgresStore 以 webauthn_session 資料表保存 Session，適用於沒有 Redis 的多實例部署
+type postgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore 建立 Postgres SessionStore
+func NewPostgresStore(db *gorm.DB) SessionStore {
+	return &postgresStore{db: db}
+}
+
+func (s *postgresStore) Save(ctx context.Context, id string, data *webauthn.SessionData, ttl time.Duration) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	row := &entity.WebAuthnSession{
+		ID:        id,
+		Data:      string(payload),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(row).Error
+}
+
+func (s *postgresStore) Get(ctx context.Context, id string) (*webauthn.SessionData, error) {
+	var row entity.WebAuthnSession
+	err := s.db.WithContext(ctx).
+		Where("id = ? AND expires_at > ?", id, time.Now()).
+		First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	var data webauthn.SessionData
+	if err := json.Unmarshal([]byte(row.Data), &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Take 以 DELETE ... RETURNING 取出並刪除 Session，同時送出的請求只有一個能取得
+func (s *postgresStore) Take(ctx context.Context, id string) (*webauthn.SessionData, error) {
+	var rows []entity.WebAuthnSession
+	err := s.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Delete(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 || !rows[0].ExpiresAt.After(time.Now()) {
+		return nil, ErrSessionNotFound
+	}
+
+	var data webauthn.SessionData
+	if err := json.Unmarshal([]byte(rows[0].Data), &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (s *postgresStore) Delete(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Delete(&entity.WebAuthnSession{}, "id = ?", id).Error
+}
\ No newline at end of file
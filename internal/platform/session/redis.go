@@ -0,0 +1,68 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "webauthn_session:"
+
+// redisStore 多實例部署使用的 SessionStore，過期交由 Redis TTL 處理
+type redisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore 建立 Redis SessionStore
+func NewRedisStore(client *redis.Client) SessionStore {
+	return &redisStore{client: client}
+}
+
+func (s *redisStore) Save(ctx context.Context, id string, data *webauthn.SessionData, ttl time.Duration) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, redisKeyPrefix+id, payload, ttl).Err()
+}
+
+func (s *redisStore) Get(ctx context.Context, id string) (*webauthn.SessionData, error) {
+	payload, err := s.client.Get(ctx, redisKeyPrefix+id).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	var data webauthn.SessionData
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Take 以 GETDEL 取出並刪除 Session，同時送出的請求只有一個能取得
+func (s *redisStore) Take(ctx context.Context, id string) (*webauthn.SessionData, error) {
+	payload, err := s.client.GetDel(ctx, redisKeyPrefix+id).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	var data webauthn.SessionData
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (s *redisStore) Delete(ctx context.Context, id string) error {
+	return s.client.Del(ctx, redisKeyPrefix+id).Err()
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fido2/config"
+	"fido2/internal/platform/db"
+	"fido2/pkg/utils"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/redis/go-redis/v9"
+)
+
+// ConditionalTTL 條件式 (autofill) 登入的 Session 存活時間
+// 一般登入與註冊的 Session 依 RP 設定的逾時時間保存；條件式登入的 Challenge 在頁面載入時產生，
+// 使用者可能很久之後才選擇 Passkey，因此另外設定
+const ConditionalTTL = 30 * time.Minute
+
+// ErrSessionNotFound Session 不存在或已過期
+var ErrSessionNotFound = errors.New("webauthn session not found or expired")
+
+// SessionStore 定義了 WebAuthn SessionData 的存取介面，以 Ceremony ID 為鍵
+type SessionStore interface {
+	Save(ctx context.Context, id string, data *webauthn.SessionData, ttl time.Duration) error
+	Get(ctx context.Context, id string) (*webauthn.SessionData, error)
+	Take(ctx context.Context, id string) (*webauthn.SessionData, error)
+	Delete(ctx context.Context, id string) error
+}
+
+var (
+	store     SessionStore
+	storeOnce sync.Once
+)
+
+// GetSessionStore 依照 SESSION_STORE 環境變數 (memory / redis / postgres) 回傳 SessionStore 單例
+func GetSessionStore() SessionStore {
+	storeOnce.Do(func() {
+		switch config.GetEnv("SESSION_STORE") {
+		case "redis":
+			redisDB, _ := strconv.Atoi(config.GetEnv("REDIS_DB"))
+			client := redis.NewClient(&redis.Options{
+				Addr:     config.GetEnv("REDIS_ADDR"),
+				Password: config.GetEnv("REDIS_PASSWORD"),
+				DB:       redisDB,
+			})
+			store = NewRedisStore(client)
+		case "postgres":
+			store = NewPostgresStore(db.GetDB())
+		default:
+			store = NewMemoryStore(time.Minute)
+		}
+		utils.GetLogger().Infof("WebAuthn session store initialized: %T", store)
+	})
+	return store
+}
\ No newline at end of file
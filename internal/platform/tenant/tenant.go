@@ -0,0 +1,229 @@
+package tenant
+
+import (
+	"fido2/config"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/pkg/utils"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/metadata"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// contextKey 租戶存放在 gin.Context 中的 key
+const contextKey = "tenant"
+
+// Tenant 單一租戶 (品牌) 的執行期設定
+type Tenant struct {
+	// ID 租戶 ID
+	ID string
+
+	// Hosts 對應到此租戶的 Host header
+	Hosts []string
+
+	// PathPrefix 對應到此租戶的路徑前綴
+	PathPrefix string
+
+	// WebAuthn 此租戶專屬的 go-webauthn 實例
+	WebAuthn *webauthn.WebAuthn
+
+	// RegistrationTimeout 註冊流程的逾時時間，也是註冊 Challenge 的有效時間
+	RegistrationTimeout time.Duration
+
+	// LoginTimeout 登入流程的逾時時間，也是登入 Challenge 的有效時間
+	LoginTimeout time.Duration
+
+	// CredentialParameters 註冊時允許的公鑰演算法
+	CredentialParameters []protocol.CredentialParameter
+
+	// AuthenticatorPolicy 註冊時允許的驗證器條件，nil 表示不限制
+	AuthenticatorPolicy *wAuth.AuthenticatorPolicy
+
+	// EnterpriseAttestation 企業 Attestation 驗證，nil 表示不接受企業 Attestation
+	EnterpriseAttestation *wAuth.EnterpriseAttestation
+
+	// AppID 舊版 U2F 的 AppID，空值表示不要求 appid / appidExclude 擴充
+	AppID string
+
+	// Issuer 此租戶的 OIDC issuer，也是簽發 Token 時的 iss
+	Issuer string
+
+	// CORSOrigins 允許跨來源請求的 Origin
+	CORSOrigins []string
+
+	// AppleAppSiteAssociationFile apple-app-site-association 檔案路徑
+	AppleAppSiteAssociationFile string
+
+	// AssetLinksFile assetlinks.json 檔案路徑
+	AssetLinksFile string
+}
+
+// tenants 所有租戶，第一筆為預設租戶
+var tenants []*Tenant
+
+// Init 讀取租戶設定並初始化各租戶的 WebAuthn RP 伺服器，設定無效時直接結束程式
+func Init() {
+	configs, err := config.LoadTenantConfigs()
+	if err != nil {
+		utils.GetLogger().Fatalf("Failed to load WebAuthn RP config: %v", err)
+	}
+
+	mds, err := loadMetadataProvider()
+	if err != nil {
+		utils.GetLogger().Fatalf("Failed to load FIDO metadata: %v", err)
+	}
+
+	loaded, err := New(configs, mds)
+	if err != nil {
+		utils.GetLogger().Fatalf("Failed to initialize WebAuthn RP server: %v", err)
+	}
+
+	tenants = loaded
+	for _, t := range tenants {
+		utils.GetLogger().Infof("WebAuthn RP server initialized successfully, tenant: %s, RP ID: %s", t.ID, t.WebAuthn.Config.RPID)
+	}
+}
+
+// New 依租戶設定建立 Tenant
+func New(configs []*config.TenantConfig, mds metadata.Provider) ([]*Tenant, error) {
+	loaded := make([]*Tenant, 0, len(configs))
+	for _, cfg := range configs {
+		webAuthn, params, err := wAuth.NewWebAuthn(&cfg.RPConfig, mds)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", cfg.TenantID, err)
+		}
+
+		policy, err := wAuth.NewAuthenticatorPolicy(cfg.AuthenticatorPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", cfg.TenantID, err)
+		}
+
+		enterprise, err := wAuth.NewEnterpriseAttestation(cfg.EnterpriseAttestation)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", cfg.TenantID, err)
+		}
+
+		issuer := cfg.Issuer
+		if issuer == "" {
+			issuer = cfg.Origins[0]
+		}
+
+		loaded = append(loaded, &Tenant{
+			ID:                          cfg.TenantID,
+			Hosts:                       cfg.Hosts,
+			PathPrefix:                  cfg.PathPrefix,
+			WebAuthn:                    webAuthn,
+			RegistrationTimeout:         cfg.RegistrationTimeout,
+			LoginTimeout:                cfg.LoginTimeout,
+			CredentialParameters:        params,
+			AuthenticatorPolicy:         policy,
+			EnterpriseAttestation:       enterprise,
+			AppID:                       cfg.AppID,
+			Issuer:                      issuer,
+			CORSOrigins:                 cfg.CORSOrigins,
+			AppleAppSiteAssociationFile: cfg.AppleAppSiteAssociationFile,
+			AssetLinksFile:              cfg.AssetLinksFile,
+		})
+	}
+	return loaded, nil
+}
+
+// loadMetadataProvider 依 MDS 設定載入離線的 MDS3 BLOB，未設定時回傳 nil (不驗證 Metadata)
+func loadMetadataProvider() (metadata.Provider, error) {
+	cfg, err := config.LoadMDSConfig()
+	if err != nil || cfg.BlobFile == "" {
+		return nil, err
+	}
+
+	m, err := wAuth.LoadMetadataFile(cfg.BlobFile, cfg.RootCertFile)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(m.NextUpdate) {
+		utils.GetLogger().Warnf("FIDO metadata BLOB #%d is stale (next update %s), download a newer BLOB", m.Number, m.NextUpdate.Format(time.DateOnly))
+	}
+	utils.GetLogger().Infof("Loaded FIDO metadata BLOB #%d with %d authenticators", m.Number, m.Len())
+
+	wAuth.SetMetadata(m)
+	return m.Provider(cfg.RequireEntry)
+}
+
+// All 取得所有租戶
+func All() []*Tenant {
+	return tenants
+}
+
+// MaxRegistrationTimeout 所有租戶中最長的註冊逾時，即使用者名稱最長的保留時間
+func MaxRegistrationTimeout() time.Duration {
+	var longest time.Duration
+	for _, t := range tenants {
+		longest = max(longest, t.RegistrationTimeout)
+	}
+	return longest
+}
+
+// Default 取得預設租戶
+func Default() *Tenant {
+	return tenants[0]
+}
+
+// Resolve 依路徑前綴或 Host header 找出對應的租戶，皆不符合時回傳預設租戶
+func Resolve(host, path string) *Tenant {
+	for _, t := range tenants {
+		if t.PathPrefix != "" && (path == t.PathPrefix || strings.HasPrefix(path, t.PathPrefix+"/")) {
+			return t
+		}
+	}
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	for _, t := range tenants {
+		for _, h := range t.Hosts {
+			if h == host {
+				return t
+			}
+		}
+	}
+
+	return Default()
+}
+
+// Middleware 解析請求所屬的租戶並存入 gin.Context
+func Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		Set(ctx, Resolve(ctx.Request.Host, ctx.Request.URL.Path))
+		ctx.Next()
+	}
+}
+
+// Set 將租戶存入 gin.Context
+func Set(ctx *gin.Context, t *Tenant) {
+	ctx.Set(contextKey, t)
+}
+
+// FromContext 取得請求所屬的租戶，未經過 Middleware 時回傳預設租戶
+func FromContext(ctx *gin.Context) *Tenant {
+	if value, ok := ctx.Get(contextKey); ok {
+		if t, ok := value.(*Tenant); ok {
+			return t
+		}
+	}
+	return Default()
+}
+
+// AllowOrigin 檢查 Origin 是否為請求所屬租戶允許的 CORS 來源
+func AllowOrigin(ctx *gin.Context, origin string) bool {
+	for _, allowed := range FromContext(ctx).CORSOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
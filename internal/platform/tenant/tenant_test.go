@@ -0,0 +1,101 @@
+package tenant
+
+import (
+	"fido2/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestTenantConfig 建立測試用的租戶設定
+func newTestTenantConfig(id, rpID string) *config.TenantConfig {
+	return &config.TenantConfig{
+		RPConfig: config.RPConfig{
+			ID:                  rpID,
+			DisplayName:         id,
+			Origins:             []string{"https://" + rpID},
+			TopOriginPolicy:     "ignore",
+			RegistrationTimeout: time.Minute,
+			LoginTimeout:        time.Minute,
+			Algorithms:          []string{"ES256"},
+			Attestation:         "none",
+			UserVerification:    "preferred",
+		},
+		TenantID:    id,
+		CORSOrigins: []string{"https://" + rpID},
+	}
+}
+
+func setupTenants(t *testing.T) {
+	defaultTenant := newTestTenantConfig(config.DefaultTenantID, "example.com")
+	brandA := newTestTenantConfig("brand-a", "brand-a.com")
+	brandA.Hosts = []string{"login.brand-a.com"}
+	brandB := newTestTenantConfig("brand-b", "brand-b.com")
+	brandB.PathPrefix = "/brand-b"
+
+	loaded, err := New([]*config.TenantConfig{defaultTenant, brandA, brandB}, nil)
+	if err != nil {
+		t.Fatalf("建立租戶失敗: %v", err)
+	}
+	tenants = loaded
+}
+
+func TestResolve(t *testing.T) {
+	setupTenants(t)
+
+	tests := []struct {
+		name string
+		host string
+		path string
+		want string
+	}{
+		{name: "Host 符合", host: "login.brand-a.com", path: "/assertion/options", want: "brand-a"},
+		{name: "Host 含 port 與大寫", host: "Login.Brand-A.com:8443", path: "/assertion/options", want: "brand-a"},
+		{name: "路徑前綴符合", host: "example.com", path: "/brand-b/assertion/options", want: "brand-b"},
+		{name: "路徑前綴需完整比對", host: "example.com", path: "/brand-bc/assertion/options", want: config.DefaultTenantID},
+		{name: "皆不符合時使用預設租戶", host: "unknown.com", path: "/assertion/options", want: config.DefaultTenantID},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Resolve(tc.host, tc.path); got.ID != tc.want {
+				t.Errorf("租戶錯誤，got=%s, want=%s", got.ID, tc.want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareAndAllowOrigin(t *testing.T) {
+	setupTenants(t)
+	gin.SetMode(gin.TestMode)
+
+	var resolved *Tenant
+	var allowed, rejected bool
+	app := gin.New()
+	app.Use(Middleware())
+	app.GET("/assertion/options/conditional", func(ctx *gin.Context) {
+		resolved = FromContext(ctx)
+		allowed = AllowOrigin(ctx, "https://brand-a.com")
+		rejected = AllowOrigin(ctx, "https://example.com")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/assertion/options/conditional", nil)
+	req.Host = "login.brand-a.com"
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	if resolved == nil || resolved.ID != "brand-a" {
+		t.Fatalf("Middleware 應解析出 brand-a")
+	}
+	if resolved.WebAuthn.Config.RPID != "brand-a.com" {
+		t.Errorf("RP ID 錯誤，got=%s", resolved.WebAuthn.Config.RPID)
+	}
+	if !allowed {
+		t.Errorf("應允許租戶自己的 Origin")
+	}
+	if rejected {
+		t.Errorf("不應允許其他租戶的 Origin")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,46 @@
+package token
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/base64"
+)
+
+// JWK 公開的 JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+// JWKS 公開的 JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// newJWK 將公鑰轉換為 JWK
+func newJWK(key *signingKey) JWK {
+	jwk := JWK{Kid: key.id, Alg: key.algorithm, Use: "sig"}
+
+	switch public := key.public.(type) {
+	case *ecdsa.PublicKey:
+		// P-256 座標固定為 32 位元組
+		x := make([]byte, 32)
+		y := make([]byte, 32)
+		public.X.FillBytes(x)
+		public.Y.FillBytes(y)
+		jwk.Kty = "EC"
+		jwk.Crv = "P-256"
+		jwk.X = base64.RawURLEncoding.EncodeToString(x)
+		jwk.Y = base64.RawURLEncoding.EncodeToString(y)
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
\ No newline at end of file
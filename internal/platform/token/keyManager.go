@@ -0,0 +1,410 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fido2/internal/entity"
+	"fido2/internal/repository"
+	"fido2/pkg/utils"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// AlgES256 ECDSA P-256 簽章
+	AlgES256 = "ES256"
+	// AlgEdDSA Ed25519 簽章
+	AlgEdDSA = "EdDSA"
+
+	// retentionSkew 退役金鑰在 Token 到期後額外保留的時間，容許時鐘誤差
+	retentionSkew = 5 * time.Minute
+
+	// minReloadInterval 簽署或遇到未知 kid 時重新讀取資料庫的最短間隔
+	// 需短於 retentionSkew，其他節點輪替後以退役金鑰簽出的 Token 才會在金鑰停止公開前到期
+	minReloadInterval = 30 * time.Second
+)
+
+// ErrUnknownKeyID JWT header 中的 kid 不在已公開的金鑰中
+var ErrUnknownKeyID = errors.New("unknown signing key id")
+
+// KeyManagerConfig 金鑰管理設定
+type KeyManagerConfig struct {
+	// Algorithm 新金鑰使用的演算法 (ES256 / EdDSA)
+	Algorithm string
+
+	// RotationInterval 金鑰輪替週期
+	RotationInterval time.Duration
+
+	// TokenTTL 以此金鑰簽出的 Token 最長有效時間，退役金鑰至少公開這麼久
+	TokenTTL time.Duration
+
+	// EncryptionKey 加密私鑰用的 AES-256 金鑰
+	EncryptionKey []byte
+}
+
+// signingKey 已解密可直接使用的金鑰
+type signingKey struct {
+	id        string
+	algorithm string
+	private   crypto.Signer
+	public    crypto.PublicKey
+	createdAt time.Time
+	retired   bool
+}
+
+// KeyManager 管理資料庫中的 JWT 簽章金鑰，負責輪替並提供 JWKS
+type KeyManager struct {
+	repo   repository.SigningKeyRepository
+	config KeyManagerConfig
+	aead   cipher.AEAD
+
+	reloadMu   sync.Mutex
+	mu         sync.RWMutex
+	loaded     bool
+	lastReload time.Time
+	keys       []*signingKey
+}
+
+var _ Signer = (*KeyManager)(nil)
+
+// NewKeyManager 建立 KeyManager，金鑰會在第一次使用時才從資料庫載入
+func NewKeyManager(repo repository.SigningKeyRepository, config KeyManagerConfig) (*KeyManager, error) {
+	if config.Algorithm != AlgES256 && config.Algorithm != AlgEdDSA {
+		return nil, fmt.Errorf("unsupported signing key algorithm %q", config.Algorithm)
+	}
+	if config.RotationInterval <= 0 {
+		return nil, errors.New("signing key rotation interval must be positive")
+	}
+	if len(config.EncryptionKey) != 32 {
+		return nil, errors.New("signing key encryption key must be 32 bytes")
+	}
+
+	block, err := aes.NewCipher(config.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyManager{repo: repo, config: config, aead: aead}, nil
+}
+
+// Sign 以目前使用中的金鑰簽署 Claims，並在 header 中加入 kid
+func (m *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	key, err := m.activeKey()
+	if err != nil {
+		return "", err
+	}
+
+	var method jwt.SigningMethod = jwt.SigningMethodES256
+	if key.algorithm == AlgEdDSA {
+		method = jwt.SigningMethodEdDSA
+	}
+
+	t := jwt.NewWithClaims(method, claims)
+	t.Header["kid"] = key.id
+	return t.SignedString(key.private)
+}
+
+// Keyfunc 依 kid 找出驗證簽章用的公鑰，找不到時重新讀取資料庫一次
+func (m *KeyManager) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	key, err := m.findKey(kid)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		if err := m.reload(false); err != nil {
+			return nil, err
+		}
+		if key, err = m.findKey(kid); err != nil {
+			return nil, err
+		}
+		if key == nil {
+			return nil, ErrUnknownKeyID
+		}
+	}
+
+	if t.Method.Alg() != key.algorithm {
+		return nil, errors.New("unexpected signing method: " + t.Method.Alg())
+	}
+	return key.public, nil
+}
+
+// JWKS 回傳所有仍需公開的公鑰
+func (m *KeyManager) JWKS() (*JWKS, error) {
+	if err := m.ensureLoaded(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	jwks := &JWKS{Keys: make([]JWK, 0, len(m.keys))}
+	for _, key := range m.keys {
+		jwks.Keys = append(jwks.Keys, newJWK(key))
+	}
+	return jwks, nil
+}
+
+// Rotate 產生新的金鑰並將其他金鑰退役，退役金鑰會繼續公開到其簽出的 Token 到期為止
+func (m *KeyManager) Rotate() error {
+	return m.rotate(time.Now())
+}
+
+// rotate 在目前使用中的金鑰建立於 rotateBefore 之前 (或沒有使用中的金鑰) 時輪替
+// 多個節點同時輪替時只有一個會產生新金鑰，其他節點改為重新載入資料庫中的金鑰
+func (m *KeyManager) rotate(rotateBefore time.Time) error {
+	key, record, err := m.generateKey()
+	if err != nil {
+		return err
+	}
+
+	rotated, err := m.repo.RotateSigningKey(record, rotateBefore, record.CreatedAt.Add(m.config.TokenTTL+retentionSkew))
+	if err != nil {
+		return err
+	}
+	if !rotated {
+		return m.loadKeys(time.Now())
+	}
+
+	m.mu.Lock()
+	for _, existing := range m.keys {
+		existing.retired = true
+	}
+	m.keys = append([]*signingKey{key}, m.keys...)
+	m.mu.Unlock()
+
+	utils.GetLogger().Infof("Signing key rotated, new kid: %s", key.id)
+	return nil
+}
+
+// Run 定期重新載入金鑰並在到期時輪替，直到 ctx 結束
+func (m *KeyManager) Run(ctx context.Context) {
+	interval := m.config.RotationInterval / 10
+	if interval > time.Hour {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.reload(true); err != nil {
+				utils.GetLogger().Errorf("Failed to reload signing keys: %v", err)
+			}
+		}
+	}
+}
+
+// ensureLoaded 第一次使用時從資料庫載入金鑰
+func (m *KeyManager) ensureLoaded() error {
+	m.mu.RLock()
+	loaded := m.loaded
+	m.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+	// 同時有多個請求時只有第一個會實際載入
+	return m.reload(false)
+}
+
+// reload 從資料庫重新載入金鑰，沒有可用金鑰或金鑰已到輪替時間時產生新金鑰
+// force 為 false 時距離上次載入未滿 minReloadInterval 則略過
+func (m *KeyManager) reload(force bool) error {
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+
+	recent := time.Since(m.lastReload) < minReloadInterval
+	if !force && recent {
+		return nil
+	}
+
+	now := time.Now()
+	if err := m.loadKeys(now); err != nil {
+		return err
+	}
+
+	active, _ := m.currentKey()
+	if active == nil || now.Sub(active.createdAt) >= m.config.RotationInterval {
+		return m.rotate(now.Add(-m.config.RotationInterval))
+	}
+	return nil
+}
+
+// loadKeys 從資料庫讀取仍需公開的金鑰並取代已載入的金鑰
+func (m *KeyManager) loadKeys(now time.Time) error {
+	records, err := m.repo.GetPublishedSigningKeys(now)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*signingKey, 0, len(records))
+	for _, record := range records {
+		key, err := m.decodeKey(record)
+		if err != nil {
+			return fmt.Errorf("failed to decode signing key %s: %w", record.ID, err)
+		}
+		keys = append(keys, key)
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.loaded = true
+	m.lastReload = now
+	m.mu.Unlock()
+	return nil
+}
+
+// activeKey 取得目前用於簽署的金鑰
+// 距離上次載入超過 minReloadInterval 時先重新讀取資料庫，其他節點輪替後不會繼續以退役金鑰簽署
+func (m *KeyManager) activeKey() (*signingKey, error) {
+	if err := m.reload(false); err != nil {
+		return nil, err
+	}
+	return m.currentKey()
+}
+
+// currentKey 取得最新且未退役的金鑰
+func (m *KeyManager) currentKey() (*signingKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.keys {
+		if !key.retired {
+			return key, nil
+		}
+	}
+	return nil, errors.New("no active signing key")
+}
+
+// findKey 依 kid 找出已載入的金鑰
+func (m *KeyManager) findKey(kid string) (*signingKey, error) {
+	if err := m.ensureLoaded(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.keys {
+		if key.id == kid {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
+
+// generateKey 產生新的金鑰並加密私鑰
+func (m *KeyManager) generateKey() (*signingKey, *entity.SigningKey, error) {
+	var (
+		private crypto.Signer
+		err     error
+	)
+	switch m.config.Algorithm {
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id := uuid.New().String()
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, err
+	}
+
+	record := &entity.SigningKey{
+		ID:         id,
+		Algorithm:  m.config.Algorithm,
+		PrivateKey: m.aead.Seal(nonce, nonce, privateDER, []byte(id)),
+		PublicKey:  publicDER,
+		CreatedAt:  time.Now(),
+	}
+	key := &signingKey{
+		id:        id,
+		algorithm: record.Algorithm,
+		private:   private,
+		public:    private.Public(),
+		createdAt: record.CreatedAt,
+	}
+	return key, record, nil
+}
+
+// decodeKey 解密資料庫中的私鑰
+func (m *KeyManager) decodeKey(record *entity.SigningKey) (*signingKey, error) {
+	nonceSize := m.aead.NonceSize()
+	if len(record.PrivateKey) < nonceSize {
+		return nil, errors.New("encrypted private key is too short")
+	}
+
+	privateDER, err := m.aead.Open(nil, record.PrivateKey[:nonceSize], record.PrivateKey[nonceSize:], []byte(record.ID))
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(privateDER)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key is not a signer")
+	}
+
+	return &signingKey{
+		id:        record.ID,
+		algorithm: record.Algorithm,
+		private:   private,
+		public:    private.Public(),
+		createdAt: record.CreatedAt,
+		retired:   record.RetiredAt != nil,
+	}, nil
+}
+
+// ParseEncryptionKey 解析 base64 編碼的 AES-256 金鑰，未設定時回傳錯誤，不會以預設金鑰加密私鑰
+func ParseEncryptionKey(encoded string) ([]byte, error) {
+	if encoded == "" {
+		return nil, errors.New("signing key encryption key is required, generate one with: openssl rand -base64 32")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("signing key encryption key must be base64 encoded: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, errors.New("signing key encryption key must be 32 bytes")
+	}
+	return key, nil
+}
\ No newline at end of file
@@ -0,0 +1,297 @@
+package token
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fido2/internal/entity"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeSigningKeyRepo 以記憶體模擬簽章金鑰資料表
+type fakeSigningKeyRepo struct {
+	mu   sync.Mutex
+	keys map[string]*entity.SigningKey
+}
+
+func newFakeSigningKeyRepo() *fakeSigningKeyRepo {
+	return &fakeSigningKeyRepo{keys: map[string]*entity.SigningKey{}}
+}
+
+func (r *fakeSigningKeyRepo) GetPublishedSigningKeys(now time.Time) ([]*entity.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var keys []*entity.SigningKey
+	for _, key := range r.keys {
+		if key.ExpiresAt == nil || key.ExpiresAt.After(now) {
+			stored := *key
+			keys = append(keys, &stored)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *fakeSigningKeyRepo) RotateSigningKey(key *entity.SigningKey, rotateBefore, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.keys {
+		if existing.RetiredAt == nil && existing.CreatedAt.After(rotateBefore) {
+			return false, nil
+		}
+	}
+	for _, existing := range r.keys {
+		if existing.RetiredAt == nil {
+			retiredAt := key.CreatedAt
+			existing.RetiredAt = &retiredAt
+			existing.ExpiresAt = &expiresAt
+		}
+	}
+	stored := *key
+	r.keys[key.ID] = &stored
+	return true, nil
+}
+
+func newTestKeyManager(t *testing.T, algorithm string, repo *fakeSigningKeyRepo) *KeyManager {
+	t.Helper()
+	manager, err := NewKeyManager(repo, KeyManagerConfig{
+		Algorithm:        algorithm,
+		RotationInterval: time.Hour,
+		TokenTTL:         time.Minute,
+		EncryptionKey:    bytes.Repeat([]byte{1}, 32),
+	})
+	if err != nil {
+		t.Fatalf("建立 KeyManager 失敗: %v", err)
+	}
+	return manager
+}
+
+func signTestToken(t *testing.T, signer Signer) string {
+	t.Helper()
+	signed, err := signer.Sign(&AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	})
+	if err != nil {
+		t.Fatalf("簽署失敗: %v", err)
+	}
+	return signed
+}
+
+func TestNewKeyManager_InvalidConfig(t *testing.T) {
+	repo := newFakeSigningKeyRepo()
+	tests := []struct {
+		name   string
+		config KeyManagerConfig
+	}{
+		{"不支援的演算法", KeyManagerConfig{Algorithm: "HS256", RotationInterval: time.Hour, EncryptionKey: make([]byte, 32)}},
+		{"輪替週期為零", KeyManagerConfig{Algorithm: AlgES256, EncryptionKey: make([]byte, 32)}},
+		{"加密金鑰長度錯誤", KeyManagerConfig{Algorithm: AlgES256, RotationInterval: time.Hour, EncryptionKey: make([]byte, 16)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewKeyManager(repo, tt.config); err == nil {
+				t.Errorf("應回傳錯誤")
+			}
+		})
+	}
+}
+
+func TestKeyManager_PrivateKeyEncrypted(t *testing.T) {
+	repo := newFakeSigningKeyRepo()
+	manager := newTestKeyManager(t, AlgES256, repo)
+	signed := signTestToken(t, manager)
+
+	if len(repo.keys) != 1 {
+		t.Fatalf("第一次使用時應產生一把金鑰，got=%d", len(repo.keys))
+	}
+	for _, record := range repo.keys {
+		if _, err := x509.ParsePKCS8PrivateKey(record.PrivateKey); err == nil {
+			t.Errorf("私鑰應加密後才存入資料庫")
+		}
+	}
+
+	// 重新啟動後從資料庫解密相同的金鑰，不會再產生新金鑰
+	restarted := newTestKeyManager(t, AlgES256, repo)
+	if _, err := ParseAccessToken(restarted, signed); err != nil {
+		t.Errorf("重新載入的金鑰應能驗證舊 Token: %v", err)
+	}
+	if len(repo.keys) != 1 {
+		t.Errorf("未到輪替時間不應產生新金鑰，got=%d", len(repo.keys))
+	}
+
+	// 加密金鑰不同時無法解密
+	wrong, _ := NewKeyManager(repo, KeyManagerConfig{
+		Algorithm:        AlgES256,
+		RotationInterval: time.Hour,
+		EncryptionKey:    bytes.Repeat([]byte{2}, 32),
+	})
+	if _, err := wrong.JWKS(); err == nil {
+		t.Errorf("加密金鑰錯誤時應無法載入金鑰")
+	}
+}
+
+func TestKeyManager_Rotate(t *testing.T) {
+	repo := newFakeSigningKeyRepo()
+	manager := newTestKeyManager(t, AlgES256, repo)
+	before := signTestToken(t, manager)
+
+	if err := manager.Rotate(); err != nil {
+		t.Fatalf("輪替失敗: %v", err)
+	}
+	after := signTestToken(t, manager)
+
+	beforeToken, _, _ := jwt.NewParser().ParseUnverified(before, &AccessClaims{})
+	afterToken, _, _ := jwt.NewParser().ParseUnverified(after, &AccessClaims{})
+	if beforeToken.Header["kid"] == afterToken.Header["kid"] {
+		t.Errorf("輪替後應使用新的 kid")
+	}
+
+	// 退役金鑰仍公開在 JWKS 中，舊 Token 在到期前仍可驗證
+	jwks, err := manager.JWKS()
+	if err != nil {
+		t.Fatalf("取得 JWKS 失敗: %v", err)
+	}
+	if len(jwks.Keys) != 2 || jwks.Keys[0].Kid != afterToken.Header["kid"] {
+		t.Errorf("JWKS 應包含新舊兩把金鑰且新金鑰在前，got=%+v", jwks.Keys)
+	}
+	if _, err := ParseAccessToken(manager, before); err != nil {
+		t.Errorf("退役金鑰簽出的 Token 應仍可驗證: %v", err)
+	}
+
+	// 其他節點輪替後，以新 kid 簽出的 Token 會觸發重新載入
+	other := newTestKeyManager(t, AlgES256, repo)
+	if _, err := other.JWKS(); err != nil {
+		t.Fatalf("取得 JWKS 失敗: %v", err)
+	}
+	if err := manager.Rotate(); err != nil {
+		t.Fatalf("輪替失敗: %v", err)
+	}
+	rotated := signTestToken(t, manager)
+	if _, err := ParseAccessToken(other, rotated); err == nil {
+		t.Errorf("距離上次載入未滿 minReloadInterval 時不應重新載入")
+	}
+	other.lastReload = time.Time{}
+	if _, err := ParseAccessToken(other, rotated); err != nil {
+		t.Errorf("未知的 kid 應重新載入金鑰後驗證: %v", err)
+	}
+
+	// 退役金鑰超過保留期限後不再公開
+	for _, record := range repo.keys {
+		if record.ExpiresAt != nil {
+			expired := time.Now().Add(-time.Second)
+			record.ExpiresAt = &expired
+		}
+	}
+	restarted := newTestKeyManager(t, AlgES256, repo)
+	if _, err := ParseAccessToken(restarted, before); err == nil {
+		t.Errorf("過期的退役金鑰不應再用於驗證")
+	}
+}
+
+func TestKeyManager_ConcurrentRotation(t *testing.T) {
+	repo := newFakeSigningKeyRepo()
+	first := newTestKeyManager(t, AlgES256, repo)
+	second := newTestKeyManager(t, AlgES256, repo)
+	signTestToken(t, first)
+	signTestToken(t, second)
+
+	// 兩個節點同時到達輪替時間，只有一個會產生新金鑰
+	for _, record := range repo.keys {
+		record.CreatedAt = time.Now().Add(-2 * time.Hour)
+	}
+	first.lastReload, second.lastReload = time.Time{}, time.Time{}
+
+	var wg sync.WaitGroup
+	for _, manager := range []*KeyManager{first, second} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := manager.reload(true); err != nil {
+				t.Errorf("重新載入失敗: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(repo.keys) != 2 {
+		t.Fatalf("同時輪替應只產生一把新金鑰，got=%d", len(repo.keys))
+	}
+
+	// 兩個節點都使用同一把新金鑰簽署
+	firstToken, _, _ := jwt.NewParser().ParseUnverified(signTestToken(t, first), &AccessClaims{})
+	secondToken, _, _ := jwt.NewParser().ParseUnverified(signTestToken(t, second), &AccessClaims{})
+	if firstToken.Header["kid"] != secondToken.Header["kid"] {
+		t.Errorf("輪替後所有節點應使用相同的金鑰，got=%v %v", firstToken.Header["kid"], secondToken.Header["kid"])
+	}
+}
+
+func TestKeyManager_SignReloadsRetiredKey(t *testing.T) {
+	repo := newFakeSigningKeyRepo()
+	manager := newTestKeyManager(t, AlgES256, repo)
+	other := newTestKeyManager(t, AlgES256, repo)
+	signTestToken(t, manager)
+	signTestToken(t, other)
+
+	if err := manager.Rotate(); err != nil {
+		t.Fatalf("輪替失敗: %v", err)
+	}
+
+	// 其他節點輪替後，距離上次載入超過 minReloadInterval 時簽署前會改用新金鑰
+	other.lastReload = time.Now().Add(-minReloadInterval)
+	rotated, _, _ := jwt.NewParser().ParseUnverified(signTestToken(t, manager), &AccessClaims{})
+	signed, _, _ := jwt.NewParser().ParseUnverified(signTestToken(t, other), &AccessClaims{})
+	if signed.Header["kid"] != rotated.Header["kid"] {
+		t.Errorf("不應繼續以退役金鑰簽署，got=%v want=%v", signed.Header["kid"], rotated.Header["kid"])
+	}
+}
+
+func TestKeyManager_JWKS(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		kty       string
+		crv       string
+	}{
+		{AlgES256, "EC", "P-256"},
+		{AlgEdDSA, "OKP", "Ed25519"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			manager := newTestKeyManager(t, tt.algorithm, newFakeSigningKeyRepo())
+			signed := signTestToken(t, manager)
+			if _, err := ParseAccessToken(manager, signed); err != nil {
+				t.Fatalf("驗證失敗: %v", err)
+			}
+
+			jwks, err := manager.JWKS()
+			if err != nil {
+				t.Fatalf("取得 JWKS 失敗: %v", err)
+			}
+			key := jwks.Keys[0]
+			if key.Kty != tt.kty || key.Crv != tt.crv || key.Alg != tt.algorithm || key.Use != "sig" || key.X == "" {
+				t.Errorf("JWK 內容錯誤，got=%+v", key)
+			}
+			if tt.kty == "EC" && key.Y == "" {
+				t.Errorf("EC 金鑰應包含 y 座標")
+			}
+		})
+	}
+}
+
+func TestParseEncryptionKey(t *testing.T) {
+	if _, err := ParseEncryptionKey("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="); err != nil {
+		t.Errorf("合法的金鑰不應回傳錯誤: %v", err)
+	}
+	if _, err := ParseEncryptionKey(""); err == nil {
+		t.Errorf("未設定金鑰時應回傳錯誤")
+	}
+	if _, err := ParseEncryptionKey("c2hvcnQ="); err == nil {
+		t.Errorf("長度錯誤的金鑰應回傳錯誤")
+	}
+	if _, err := ParseEncryptionKey("not base64!"); err == nil {
+		t.Errorf("非 base64 的金鑰應回傳錯誤")
+	}
+}
\ No newline at end of file
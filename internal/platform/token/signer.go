@@ -0,0 +1,71 @@
+package token
+
+import (
+	"context"
+	"fido2/config"
+	"fido2/internal/repository"
+	"fido2/pkg/utils"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultKeyRotationInterval 簽章金鑰預設輪替週期
+const DefaultKeyRotationInterval = 30 * 24 * time.Hour
+
+// Signer 簽發與驗證 JWT 的介面
+type Signer interface {
+	// Sign 簽署 Claims 並回傳 JWT 字串
+	Sign(claims jwt.Claims) (string, error)
+
+	// Keyfunc 提供 jwt.Parse 驗證簽章時使用的金鑰
+	Keyfunc(token *jwt.Token) (interface{}, error)
+
+	// JWKS 回傳驗證簽章用的公鑰
+	JWKS() (*JWKS, error)
+}
+
+var (
+	signer     Signer
+	signerOnce sync.Once
+)
+
+// GetSigner 建立以資料庫金鑰簽署的 Signer 單例並啟動定期輪替，設定無效時直接結束程式
+// SIGNING_KEY_ALG (ES256 / EdDSA)、SIGNING_KEY_ROTATION、SIGNING_KEY_ENCRYPTION_KEY (base64 的 32 位元組)
+func GetSigner() Signer {
+	signerOnce.Do(func() {
+		encryptionKey, err := ParseEncryptionKey(config.GetEnv("SIGNING_KEY_ENCRYPTION_KEY"))
+		if err != nil {
+			utils.GetLogger().Fatalf("Failed to initialize JWT signer: %v", err)
+		}
+
+		algorithm := config.GetEnv("SIGNING_KEY_ALG")
+		if algorithm == "" {
+			algorithm = AlgES256
+		}
+
+		manager, err := NewKeyManager(repository.NewSigningKeyRepository(), KeyManagerConfig{
+			Algorithm:        algorithm,
+			RotationInterval: durationFromEnv("SIGNING_KEY_ROTATION", DefaultKeyRotationInterval),
+			TokenTTL:         GetAccessTokenTTL(),
+			EncryptionKey:    encryptionKey,
+		})
+		if err != nil {
+			utils.GetLogger().Fatalf("Failed to initialize JWT signer: %v", err)
+		}
+
+		go manager.Run(context.Background())
+		signer = manager
+	})
+	return signer
+}
+
+// ParseAccessToken 驗證 Access Token 的簽章與有效期間並回傳內容
+func ParseAccessToken(s Signer, accessToken string) (*AccessClaims, error) {
+	claims := &AccessClaims{}
+	if _, err := jwt.ParseWithClaims(accessToken, claims, s.Keyfunc); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
\ No newline at end of file
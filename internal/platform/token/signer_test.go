@@ -0,0 +1,67 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestSigner_RoundTrip(t *testing.T) {
+	signer := newTestKeyManager(t, AlgES256, newFakeSigningKeyRepo())
+
+	signed, err := signer.Sign(&AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		AMR:          []string{"webauthn"},
+		ACR:          ACR(true),
+		CredentialID: "Y3JlZGlk",
+		TenantID:     "default",
+	})
+	if err != nil {
+		t.Fatalf("簽署失敗: %v", err)
+	}
+
+	claims, err := ParseAccessToken(signer, signed)
+	if err != nil {
+		t.Fatalf("驗證失敗: %v", err)
+	}
+	if claims.Subject != "user-1" || claims.ACR != "aal2" || claims.CredentialID != "Y3JlZGlk" || claims.AMR[0] != "webauthn" {
+		t.Errorf("Claims 錯誤，got=%+v", claims)
+	}
+}
+
+func TestSigner_Invalid(t *testing.T) {
+	signer := newTestKeyManager(t, AlgES256, newFakeSigningKeyRepo())
+	other := newTestKeyManager(t, AlgES256, newFakeSigningKeyRepo())
+
+	expired, _ := signer.Sign(&AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))},
+	})
+	if _, err := ParseAccessToken(signer, expired); err == nil {
+		t.Errorf("過期的 Token 應驗證失敗")
+	}
+
+	foreign, _ := other.Sign(&AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	})
+	if _, err := ParseAccessToken(signer, foreign); err == nil {
+		t.Errorf("其他金鑰簽署的 Token 應驗證失敗")
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	first, err := NewRefreshToken()
+	if err != nil {
+		t.Fatalf("產生 Refresh Token 失敗: %v", err)
+	}
+	second, _ := NewRefreshToken()
+	if first == second {
+		t.Errorf("Refresh Token 應為隨機值")
+	}
+	if HashRefreshToken(first) != HashRefreshToken(first) || HashRefreshToken(first) == first {
+		t.Errorf("雜湊值應固定且不等於原始 Token")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,113 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fido2/config"
+	"fido2/pkg/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// DefaultAccessTokenTTL Access Token 預設有效時間
+	DefaultAccessTokenTTL = 15 * time.Minute
+
+	// DefaultRefreshTokenTTL Refresh Token 預設有效時間
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+	// refreshTokenLength Refresh Token 隨機位元組長度
+	refreshTokenLength = 32
+)
+
+var (
+	// ErrInvalidRefreshToken Refresh Token 不存在、已過期或不屬於目前的租戶
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+
+	// ErrRefreshTokenReused 已輪替過的 Refresh Token 再次被使用，整個登入 Session 會被撤銷
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
+	// ErrInvalidAccessToken Access Token 簽章錯誤、已過期或不屬於目前的租戶
+	ErrInvalidAccessToken = errors.New("access token is invalid or expired")
+)
+
+// AccessClaims 登入成功後簽發的 Access Token 內容
+type AccessClaims struct {
+	jwt.RegisteredClaims
+
+	// AMR 使用的驗證方式
+	AMR []string `json:"amr"`
+
+	// ACR 驗證強度等級
+	ACR string `json:"acr"`
+
+	// AuthTime 實際完成 WebAuthn 驗證的時間 (Unix 秒)
+	AuthTime int64 `json:"auth_time"`
+
+	// CredentialID 登入所使用的 Credential ID
+	CredentialID string `json:"cid"`
+
+	// TenantID 使用者所屬的租戶
+	TenantID string `json:"tid"`
+
+	// Scope OIDC Client 取得的 scope，/userinfo 依此決定回傳的欄位
+	Scope string `json:"scope,omitzero"`
+}
+
+// Pair 回傳給用戶端的 Access Token 與 Refresh Token
+type Pair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// ACR 依使用者是否經過驗證 (UV) 決定驗證強度等級
+func ACR(userVerified bool) string {
+	if userVerified {
+		return "aal2"
+	}
+	return "aal1"
+}
+
+// NewRefreshToken 產生隨機的 Refresh Token
+func NewRefreshToken() (string, error) {
+	raw := make([]byte, refreshTokenLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// HashRefreshToken 計算 Refresh Token 的雜湊值，資料庫只保存雜湊值
+func HashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetAccessTokenTTL 由 ACCESS_TOKEN_TTL 環境變數讀取 Access Token 有效時間
+func GetAccessTokenTTL() time.Duration {
+	return durationFromEnv("ACCESS_TOKEN_TTL", DefaultAccessTokenTTL)
+}
+
+// GetRefreshTokenTTL 由 REFRESH_TOKEN_TTL 環境變數讀取 Refresh Token 有效時間
+func GetRefreshTokenTTL() time.Duration {
+	return durationFromEnv("REFRESH_TOKEN_TTL", DefaultRefreshTokenTTL)
+}
+
+// durationFromEnv 讀取時間長度的環境變數，未設定或無效時使用預設值
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := config.GetEnv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		utils.GetLogger().Warnf("Invalid %s %q, falling back to %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
\ No newline at end of file
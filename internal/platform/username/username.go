@@ -0,0 +1,37 @@
+package username
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength 正規化後使用者名稱的最大字元數
+const MaxLength = 64
+
+// ErrInvalidUsername 使用者名稱為空、過長或包含控制字元與不可見的格式字元
+var ErrInvalidUsername = errors.New("username is empty, too long or contains invalid characters")
+
+// folder 不依語系的 Unicode case folding
+var folder = cases.Fold()
+
+// Normalize 將使用者名稱正規化為比對與儲存用的形式
+// 去除前後空白後依序做 NFKC 與 case folding，folding 後再做一次 NFKC 確保結果穩定
+func Normalize(name string) (string, error) {
+	normalized := norm.NFKC.String(strings.TrimSpace(name))
+	normalized = norm.NFKC.String(folder.String(normalized))
+
+	if normalized == "" || utf8.RuneCountInString(normalized) > MaxLength {
+		return "", ErrInvalidUsername
+	}
+	for _, r := range normalized {
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) || r == utf8.RuneError {
+			return "", ErrInvalidUsername
+		}
+	}
+	return normalized, nil
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+package username
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "去除前後空白", input: "  alice \t", want: "alice"},
+		{name: "大小寫視為相同", input: "Alice", want: "alice"},
+		{name: "全形字元轉為半形", input: "ａｌｉｃｅ", want: "alice"},
+		{name: "合字展開", input: "ﬁnance", want: "finance"},
+		{name: "德文 ß 折疊", input: "Straße", want: "strasse"},
+		{name: "組合字元合併", input: "José", want: "josé"},
+		{name: "保留中文", input: "王小明", want: "王小明"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Normalize(tc.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	for _, input := range []string{"", "   ", "ali\x00ce", "bob‏\n", strings.Repeat("a", MaxLength+1)} {
+		_, err := Normalize(input)
+		assert.ErrorIs(t, err, ErrInvalidUsername, "input %q", input)
+	}
+}
\ No newline at end of file
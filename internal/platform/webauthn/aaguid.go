@@ -0,0 +1,50 @@
+package webauthn
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fido2/pkg/utils"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// aaguidNamesJSON 常見驗證器 AAGUID 與名稱的對照表
+//
+//go:embed aaguids.json
+var aaguidNamesJSON []byte
+
+var (
+	aaguidNames     map[string]string
+	aaguidNamesOnce sync.Once
+)
+
+// FormatAAGUID 將 AAGUID 轉為 UUID 字串，長度不正確或全為 0 (未提供) 時回傳空字串
+func FormatAAGUID(aaguid []byte) string {
+	id, err := uuid.FromBytes(aaguid)
+	if err != nil || id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+// AuthenticatorName 依 AAGUID 取得驗證器名稱，優先使用 MDS Metadata 的描述，未知時回傳空字串
+func AuthenticatorName(aaguid []byte) string {
+	if m := loadedMetadata.Load(); m != nil {
+		if entry := m.Entry(aaguid); entry != nil && entry.MetadataStatement.Description != "" {
+			return entry.MetadataStatement.Description
+		}
+	}
+
+	aaguidNamesOnce.Do(func() {
+		if err := json.Unmarshal(aaguidNamesJSON, &aaguidNames); err != nil {
+			utils.GetLogger().Errorf("Failed to parse AAGUID names: %v", err)
+		}
+	})
+
+	id := FormatAAGUID(aaguid)
+	if id == "" {
+		return ""
+	}
+	return aaguidNames[id]
+}
\ No newline at end of file
@@ -0,0 +1,32 @@
+package webauthn
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAuthenticatorName(t *testing.T) {
+	iCloud := uuid.MustParse("fbfc3007-154e-4ecc-8c0b-6e020557d7bd")
+
+	if got := AuthenticatorName(iCloud[:]); got != "iCloud Keychain" {
+		t.Errorf("AuthenticatorName 錯誤，got=%q", got)
+	}
+	if got := AuthenticatorName(make([]byte, 16)); got != "" {
+		t.Errorf("全為 0 的 AAGUID 應回傳空字串，got=%q", got)
+	}
+	if got := AuthenticatorName([]byte{1, 2, 3}); got != "" {
+		t.Errorf("長度錯誤的 AAGUID 應回傳空字串，got=%q", got)
+	}
+	unknown := uuid.New()
+	if got := AuthenticatorName(unknown[:]); got != "" {
+		t.Errorf("未知的 AAGUID 應回傳空字串，got=%q", got)
+	}
+}
+
+func TestFormatAAGUID(t *testing.T) {
+	id := uuid.MustParse("ea9b8d66-4d01-1d21-3ce4-b6b48cb575d4")
+	if got := FormatAAGUID(id[:]); got != id.String() {
+		t.Errorf("FormatAAGUID 錯誤，got=%q", got)
+	}
+}
\ No newline at end of file
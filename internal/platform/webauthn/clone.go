@@ -0,0 +1,31 @@
+package webauthn
+
+import (
+	"fido2/pkg/utils"
+	"os"
+)
+
+// ClonePolicy 驗證器計數器倒退 (疑似被複製) 時的處理方式
+type ClonePolicy string
+
+const (
+	// ClonePolicyLog 僅記錄警告，照常登入
+	ClonePolicyLog ClonePolicy = "log"
+	// ClonePolicyFlag 標記使用者帳號待審查，照常登入
+	ClonePolicyFlag ClonePolicy = "flag"
+	// ClonePolicyReject 拒絕此次登入
+	ClonePolicyReject ClonePolicy = "reject"
+)
+
+// GetClonePolicy 由環境變數 CLONE_POLICY 讀取複製偵測策略，未設定或無效時使用 log
+func GetClonePolicy() ClonePolicy {
+	switch policy := ClonePolicy(os.Getenv("CLONE_POLICY")); policy {
+	case ClonePolicyLog, ClonePolicyFlag, ClonePolicyReject:
+		return policy
+	case "":
+		return ClonePolicyLog
+	default:
+		utils.GetLogger().Warnf("Unknown CLONE_POLICY %q, falling back to %q", policy, ClonePolicyLog)
+		return ClonePolicyLog
+	}
+}
\ No newline at end of file
@@ -0,0 +1,25 @@
+package webauthn
+
+import (
+	"testing"
+)
+
+func TestGetClonePolicy(t *testing.T) {
+	tests := []struct {
+		env  string
+		want ClonePolicy
+	}{
+		{env: "", want: ClonePolicyLog},
+		{env: "log", want: ClonePolicyLog},
+		{env: "flag", want: ClonePolicyFlag},
+		{env: "reject", want: ClonePolicyReject},
+		{env: "unknown", want: ClonePolicyLog},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("CLONE_POLICY", tt.env)
+		if got := GetClonePolicy(); got != tt.want {
+			t.Errorf("CLONE_POLICY=%q: got %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
\ No newline at end of file
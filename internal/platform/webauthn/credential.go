@@ -0,0 +1,81 @@
+package webauthn
+
+import (
+	"encoding/base64"
+	"fido2/internal/entity"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// NewCredentialEntity 將 go-webauthn 的 Credential 轉換為要儲存的 entity.Credential
+func NewCredentialEntity(tenantID, userID string, credential *webauthn.Credential) *entity.Credential {
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	return &entity.Credential{
+		ID:              base64.RawURLEncoding.EncodeToString(credential.ID),
+		TenantID:        tenantID,
+		UserID:          userID,
+		PublicKey:       credential.PublicKey,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		CloneWarning:    credential.Authenticator.CloneWarning,
+		Attachment:      string(credential.Authenticator.Attachment),
+		Transports:      transports,
+		UserPresent:     credential.Flags.UserPresent,
+		UserVerified:    credential.Flags.UserVerified,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		AttestationType: credential.AttestationType,
+	}
+}
+
+// ToWebAuthnCredential 將儲存的 entity.Credential 還原為 go-webauthn 的 Credential
+func ToWebAuthnCredential(credential *entity.Credential) (webauthn.Credential, error) {
+	id, err := base64.RawURLEncoding.DecodeString(credential.ID)
+	if err != nil {
+		return webauthn.Credential{}, err
+	}
+
+	transports := make([]protocol.AuthenticatorTransport, 0, len(credential.Transports))
+	for _, transport := range credential.Transports {
+		transports = append(transports, protocol.AuthenticatorTransport(transport))
+	}
+
+	return webauthn.Credential{
+		ID:              id,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			UserPresent:    credential.UserPresent,
+			UserVerified:   credential.UserVerified,
+			BackupEligible: credential.BackupEligible,
+			BackupState:    credential.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:       credential.AAGUID,
+			SignCount:    credential.SignCount,
+			CloneWarning: credential.CloneWarning,
+			Attachment:   protocol.AuthenticatorAttachment(credential.Attachment),
+		},
+	}, nil
+}
+
+// CredentialUsageUpdate 產生登入成功後要寫回的 Credential 欄位
+// 包含驗證器計數器、複製警告、Backup 狀態與最後使用時間
+func CredentialUsageUpdate(credential *webauthn.Credential, usedAt time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"sign_count":      credential.Authenticator.SignCount,
+		"clone_warning":   credential.Authenticator.CloneWarning,
+		"user_present":    credential.Flags.UserPresent,
+		"user_verified":   credential.Flags.UserVerified,
+		"backup_eligible": credential.Flags.BackupEligible,
+		"backup_state":    credential.Flags.BackupState,
+		"last_used_at":    usedAt,
+	}
+}
\ No newline at end of file
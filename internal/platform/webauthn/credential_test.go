@@ -0,0 +1,58 @@
+package webauthn
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+func TestCredentialEntityRoundTrip(t *testing.T) {
+	original := &webauthn.Credential{
+		ID:              []byte("credential-id"),
+		PublicKey:       []byte("public-key"),
+		AttestationType: "none",
+		Transport:       []protocol.AuthenticatorTransport{protocol.Internal, protocol.Hybrid},
+		Flags: webauthn.CredentialFlags{
+			UserPresent:    true,
+			UserVerified:   true,
+			BackupEligible: true,
+			BackupState:    false,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:     []byte("0123456789abcdef"),
+			SignCount:  7,
+			Attachment: protocol.Platform,
+		},
+	}
+
+	stored := NewCredentialEntity("brand-a", "user-1", original)
+	if stored.ID != "Y3JlZGVudGlhbC1pZA" {
+		t.Errorf("ID 應為 base64url 編碼：got=%s", stored.ID)
+	}
+	if stored.TenantID != "brand-a" {
+		t.Errorf("TenantID 錯誤：got=%s", stored.TenantID)
+	}
+	if stored.UserID != "user-1" {
+		t.Errorf("UserID 錯誤：got=%s", stored.UserID)
+	}
+
+	restored, err := ToWebAuthnCredential(stored)
+	if err != nil {
+		t.Fatalf("ToWebAuthnCredential 失敗: %v", err)
+	}
+
+	if !bytes.Equal(restored.ID, original.ID) || !bytes.Equal(restored.PublicKey, original.PublicKey) {
+		t.Errorf("ID / PublicKey 不一致：got=%+v", restored)
+	}
+	if restored.Authenticator.SignCount != 7 || restored.Authenticator.Attachment != protocol.Platform {
+		t.Errorf("Authenticator 不一致：got=%+v", restored.Authenticator)
+	}
+	if restored.Flags.UserPresent != true || restored.Flags.BackupEligible != true || restored.Flags.BackupState != false {
+		t.Errorf("Flags 不一致：got=%+v", restored.Flags)
+	}
+	if len(restored.Transport) != 2 || restored.Transport[1] != protocol.Hybrid {
+		t.Errorf("Transport 不一致：got=%v", restored.Transport)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,131 @@
+package webauthn
+
+import (
+	"encoding/pem"
+	"errors"
+	"fido2/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// writeTestRootCertificate 建立企業根憑證並寫入暫存的 PEM 檔案
+func writeTestRootCertificate(t *testing.T, name string) string {
+	t.Helper()
+
+	root, _ := newTestCertificate(t, name, nil, nil)
+	path := filepath.Join(t.TempDir(), "root.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewEnterpriseAttestation_Disabled(t *testing.T) {
+	enterprise, err := NewEnterpriseAttestation(config.EnterpriseAttestation{})
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+	if enterprise != nil {
+		t.Errorf("未設定時應回傳 nil")
+	}
+	if _, err := enterprise.Verify(&webauthn.Credential{}, protocol.AttestationObject{}, time.Now()); !errors.Is(err, ErrNotEnterpriseAttestation) {
+		t.Errorf("未設定時不應接受企業 Attestation，got=%v", err)
+	}
+}
+
+func TestNewEnterpriseAttestation_InvalidRoot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "root.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewEnterpriseAttestation(config.EnterpriseAttestation{
+		AAGUIDs:       []string{certifiedAAGUID.String()},
+		RootCertFiles: []string{path},
+	}); err == nil {
+		t.Errorf("根憑證檔案無效時應回傳錯誤")
+	}
+}
+
+func TestEnterpriseAttestation_Verify(t *testing.T) {
+	root, rootKey := newTestCertificate(t, "Enterprise Root", nil, nil)
+	rootFile := filepath.Join(t.TempDir(), "root.pem")
+	if err := os.WriteFile(rootFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	leaf, _ := newTestCertificate(t, "Device", root, rootKey)
+
+	enterprise, err := NewEnterpriseAttestation(config.EnterpriseAttestation{
+		AAGUIDs:       []string{certifiedAAGUID.String()},
+		RootCertFiles: []string{rootFile},
+	})
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+
+	credential := &webauthn.Credential{Authenticator: webauthn.Authenticator{AAGUID: certifiedAAGUID[:]}}
+	att := protocol.AttestationObject{
+		Format:       "packed",
+		AttStatement: map[string]any{"x5c": []any{leaf.Raw}},
+	}
+
+	device, err := enterprise.Verify(credential, att, time.Now())
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+	// 憑證 Subject 沒有 serialNumber 時使用憑證序號
+	if device.SerialNumber != strings.ToUpper(leaf.SerialNumber.Text(16)) {
+		t.Errorf("裝置序號錯誤，got=%q", device.SerialNumber)
+	}
+	if string(device.Certificate) != string(leaf.Raw) {
+		t.Errorf("應保存裝置的 Attestation 憑證")
+	}
+
+	tests := []struct {
+		name       string
+		credential *webauthn.Credential
+		att        protocol.AttestationObject
+		rootFile   string
+	}{
+		{
+			name:       "AAGUID 不在清單中",
+			credential: &webauthn.Credential{Authenticator: webauthn.Authenticator{AAGUID: revokedAAGUID[:]}},
+			att:        att,
+		},
+		{
+			name:       "缺少 x5c",
+			credential: credential,
+			att:        protocol.AttestationObject{Format: "packed", AttStatement: map[string]any{}},
+		},
+		{
+			name:       "未串接到企業根憑證",
+			credential: credential,
+			att:        att,
+			rootFile:   writeTestRootCertificate(t, "Other Root"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := enterprise
+			if tt.rootFile != "" {
+				e, err = NewEnterpriseAttestation(config.EnterpriseAttestation{
+					AAGUIDs:       []string{certifiedAAGUID.String()},
+					RootCertFiles: []string{tt.rootFile},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := e.Verify(tt.credential, tt.att, time.Now()); !errors.Is(err, ErrNotEnterpriseAttestation) {
+				t.Errorf("應回傳 ErrNotEnterpriseAttestation，got=%v", err)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,17 @@
+package webauthn
+
+import (
+	"crypto/rand"
+)
+
+// UserHandleLength WebAuthn 規範允許的 user handle 最大長度 (bytes)
+const UserHandleLength = 64
+
+// NewUserHandle 產生不含任何個人資料的隨機 user handle
+func NewUserHandle() ([]byte, error) {
+	handle := make([]byte, UserHandleLength)
+	if _, err := rand.Read(handle); err != nil {
+		return nil, err
+	}
+	return handle, nil
+}
\ No newline at end of file
@@ -0,0 +1,180 @@
+package webauthn
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-webauthn/webauthn/metadata"
+	"github.com/go-webauthn/webauthn/metadata/providers/memory"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidMetadataBLOB MDS3 BLOB 格式錯誤或簽章無法驗證
+	ErrInvalidMetadataBLOB = errors.New("invalid metadata BLOB")
+
+	// loadedMetadata 目前使用中的 Metadata，供 AuthenticatorName 查詢驗證器名稱
+	loadedMetadata atomic.Pointer[Metadata]
+)
+
+// Metadata 由 MDS3 BLOB 載入並以 AAGUID 為索引的驗證器資料
+type Metadata struct {
+	// Number BLOB 序號，每次發佈遞增
+	Number int
+
+	// NextUpdate FIDO 預計發佈下一版 BLOB 的日期
+	NextUpdate time.Time
+
+	entries map[uuid.UUID]*metadata.Entry
+}
+
+// LoadMetadataFile 讀取離線的 MDS3 BLOB 與根憑證 (PEM) 檔案並驗證簽章
+func LoadMetadataFile(blobFile, rootCertFile string) (*Metadata, error) {
+	blob, err := os.ReadFile(blobFile)
+	if err != nil {
+		return nil, err
+	}
+
+	rootPEM, err := os.ReadFile(rootCertFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(rootPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s is not a PEM encoded certificate", rootCertFile)
+	}
+	root, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadMetadata(blob, root, time.Now())
+}
+
+// LoadMetadata 以 x5c 標頭中的憑證鏈驗證 BLOB 簽章，憑證鏈必須串接到指定的根憑證
+// 離線環境不會下載 CRL，因此只驗證憑證鏈與有效期間
+func LoadMetadata(blob []byte, root *x509.Certificate, now time.Time) (*Metadata, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"ES256", "RS256", "PS256"}))
+
+	token, err := parser.Parse(strings.TrimSpace(string(blob)), func(token *jwt.Token) (interface{}, error) {
+		leaf, err := verifyMetadataChain(token.Header, root, now)
+		if err != nil {
+			return nil, err
+		}
+		return leaf.PublicKey, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMetadataBLOB, err)
+	}
+
+	// 簽章驗證通過後再以原始 JSON 解析內容，避免 MapClaims 轉換造成的型別差異
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(strings.Split(token.Raw, ".")[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMetadataBLOB, err)
+	}
+
+	var payload metadata.PayloadJSON
+	if err := json.Unmarshal(payloadJSON, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMetadataBLOB, err)
+	}
+
+	// 個別項目解析失敗時略過，不影響其他驗證器
+	decoder, err := metadata.NewDecoder(metadata.WithIgnoreEntryParsingErrors())
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := decoder.Parse(&payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMetadataBLOB, err)
+	}
+
+	return &Metadata{
+		Number:     parsed.Parsed.Number,
+		NextUpdate: parsed.Parsed.NextUpdate,
+		entries:    parsed.ToMap(),
+	}, nil
+}
+
+// verifyMetadataChain 驗證 x5c 憑證鏈並回傳簽署 BLOB 的憑證
+func verifyMetadataChain(header map[string]interface{}, root *x509.Certificate, now time.Time) (*x509.Certificate, error) {
+	x5c, ok := header["x5c"].([]interface{})
+	if !ok || len(x5c) == 0 {
+		return nil, errors.New("x5c header is missing")
+	}
+
+	certificates := make([]*x509.Certificate, 0, len(x5c))
+	for i, value := range x5c {
+		encoded, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("x5c certificate %d is not a string", i)
+		}
+		der, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("x5c certificate %d: %w", i, err)
+		}
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("x5c certificate %d: %w", i, err)
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	if _, err := certificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, err
+	}
+	return certificates[0], nil
+}
+
+// Len Metadata 中以 AAGUID 索引的驗證器數量
+func (m *Metadata) Len() int {
+	return len(m.entries)
+}
+
+// Entry 依 AAGUID 取得驗證器的 Metadata，不存在時回傳 nil
+func (m *Metadata) Entry(aaguid []byte) *metadata.Entry {
+	id, err := uuid.FromBytes(aaguid)
+	if err != nil || id == uuid.Nil {
+		return nil
+	}
+	return m.entries[id]
+}
+
+// Provider 建立 go-webauthn 的 Metadata Provider，註冊時驗證憑證信任鏈與驗證器狀態 (撤銷、金鑰外洩等)
+// requireEntry 為 true 時不在 Metadata 中的驗證器無法註冊 (未提供 AAGUID 者除外)
+func (m *Metadata) Provider(requireEntry bool) (metadata.Provider, error) {
+	return memory.New(
+		memory.WithMetadata(m.entries),
+		memory.WithValidateEntry(requireEntry),
+		memory.WithValidateEntryPermitZeroAAGUID(true),
+		memory.WithValidateTrustAnchor(true),
+		memory.WithValidateStatus(true),
+		memory.WithValidateAttestationTypes(true),
+	)
+}
+
+// SetMetadata 設定目前使用中的 Metadata
+func SetMetadata(m *Metadata) {
+	loadedMetadata.Store(m)
+}
\ No newline at end of file
@@ -0,0 +1,172 @@
+package webauthn
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	certifiedAAGUID = uuid.MustParse("11111111-2222-3333-4444-555555555555")
+	revokedAAGUID   = uuid.MustParse("66666666-7777-8888-9999-000000000000")
+)
+
+// newTestCertificate 建立測試用憑證，parent 為 nil 時為自簽的根憑證
+func newTestCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certificate, key
+}
+
+// newTestBLOB 以 signer 簽署含一個已認證與一個已撤銷驗證器的 MDS3 BLOB
+func newTestBLOB(t *testing.T, signer *x509.Certificate, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+
+	entry := func(aaguid uuid.UUID, description, status string) map[string]interface{} {
+		return map[string]interface{}{
+			"aaguid": aaguid.String(),
+			"metadataStatement": map[string]interface{}{
+				"aaguid":           aaguid.String(),
+				"description":      description,
+				"attestationTypes": []string{"basic_full"},
+			},
+			"statusReports":          []map[string]interface{}{{"status": status, "effectiveDate": "2024-01-01"}},
+			"timeOfLastStatusChange": "2024-01-01",
+		}
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"no":         42,
+		"nextUpdate": "2099-01-01",
+		"entries": []map[string]interface{}{
+			entry(certifiedAAGUID, "Test Security Key", "FIDO_CERTIFIED"),
+			entry(revokedAAGUID, "Revoked Security Key", "REVOKED"),
+		},
+	})
+	token.Header["x5c"] = []string{base64.StdEncoding.EncodeToString(signer.Raw)}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []byte(signed)
+}
+
+func TestLoadMetadata(t *testing.T) {
+	root, rootKey := newTestCertificate(t, "Test MDS Root", nil, nil)
+	signer, signerKey := newTestCertificate(t, "Test MDS Signer", root, rootKey)
+
+	m, err := LoadMetadata(newTestBLOB(t, signer, signerKey), root, time.Now())
+	if err != nil {
+		t.Fatalf("LoadMetadata 失敗：%v", err)
+	}
+	if m.Number != 42 || m.Len() != 2 {
+		t.Errorf("BLOB 內容錯誤，number=%d len=%d", m.Number, m.Len())
+	}
+	if entry := m.Entry(certifiedAAGUID[:]); entry == nil || entry.MetadataStatement.Description != "Test Security Key" {
+		t.Errorf("應可依 AAGUID 取得驗證器，got=%+v", entry)
+	}
+	unknown := uuid.New()
+	if entry := m.Entry(unknown[:]); entry != nil {
+		t.Errorf("未知的 AAGUID 應回傳 nil")
+	}
+
+	// 驗證器名稱優先使用 Metadata 的描述
+	SetMetadata(m)
+	defer SetMetadata(nil)
+	if got := AuthenticatorName(certifiedAAGUID[:]); got != "Test Security Key" {
+		t.Errorf("AuthenticatorName 應使用 Metadata 描述，got=%q", got)
+	}
+}
+
+func TestLoadMetadata_Invalid(t *testing.T) {
+	root, rootKey := newTestCertificate(t, "Test MDS Root", nil, nil)
+	signer, signerKey := newTestCertificate(t, "Test MDS Signer", root, rootKey)
+	otherRoot, _ := newTestCertificate(t, "Other Root", nil, nil)
+	blob := newTestBLOB(t, signer, signerKey)
+
+	// 憑證鏈無法串接到設定的根憑證
+	if _, err := LoadMetadata(blob, otherRoot, time.Now()); !errors.Is(err, ErrInvalidMetadataBLOB) {
+		t.Errorf("根憑證不符應回傳 ErrInvalidMetadataBLOB，got=%v", err)
+	}
+
+	// 簽署憑證已過期
+	if _, err := LoadMetadata(blob, root, time.Now().Add(2*time.Hour)); !errors.Is(err, ErrInvalidMetadataBLOB) {
+		t.Errorf("憑證過期應回傳 ErrInvalidMetadataBLOB，got=%v", err)
+	}
+
+	// 內容遭竄改
+	parts := strings.Split(string(blob), ".")
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"no":43,"nextUpdate":"2099-01-01","entries":[]}`))
+	if _, err := LoadMetadata([]byte(strings.Join(parts, ".")), root, time.Now()); !errors.Is(err, ErrInvalidMetadataBLOB) {
+		t.Errorf("內容遭竄改應回傳 ErrInvalidMetadataBLOB，got=%v", err)
+	}
+}
+
+func TestMetadataProvider(t *testing.T) {
+	root, rootKey := newTestCertificate(t, "Test MDS Root", nil, nil)
+	signer, signerKey := newTestCertificate(t, "Test MDS Signer", root, rootKey)
+
+	m, err := LoadMetadata(newTestBLOB(t, signer, signerKey), root, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := m.Provider(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := protocol.ValidateMetadata(ctx, provider, certifiedAAGUID, "basic_full", nil); err != nil {
+		t.Errorf("已認證的驗證器應通過驗證，got=%v", err)
+	}
+	if err := protocol.ValidateMetadata(ctx, provider, revokedAAGUID, "basic_full", nil); err == nil {
+		t.Errorf("已撤銷的驗證器不應通過驗證")
+	}
+	if err := protocol.ValidateMetadata(ctx, provider, uuid.New(), "basic_full", nil); err == nil {
+		t.Errorf("要求 Metadata 時未知的驗證器不應通過驗證")
+	}
+	if err := protocol.ValidateMetadata(ctx, provider, uuid.Nil, "none", nil); err != nil {
+		t.Errorf("未提供 AAGUID 的驗證器應略過 Metadata 驗證，got=%v", err)
+	}
+}
\ No newline at end of file
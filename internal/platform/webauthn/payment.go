@@ -0,0 +1,152 @@
+package webauthn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+// PaymentExtension Secure Payment Confirmation 的 WebAuthn 擴充名稱
+const PaymentExtension = "payment"
+
+// PaymentCeremony SPC 登入時 clientDataJSON 的 type
+const PaymentCeremony protocol.CeremonyType = "payment.get"
+
+var (
+	// ErrInvalidPaymentTransaction 交易資料不完整或格式錯誤
+	ErrInvalidPaymentTransaction = errors.New("invalid payment transaction")
+
+	// ErrPaymentClientData clientDataJSON 不是 payment.get 或內容與伺服器發出的交易不符
+	ErrPaymentClientData = errors.New("payment client data does not match the transaction")
+)
+
+var (
+	// currencyPattern ISO 4217 格式的幣別代碼 (Payment Request API §7.3)
+	currencyPattern = regexp.MustCompile(`^[A-Za-z]{3}$`)
+
+	// amountPattern 合法的金額字串 (Payment Request API §7.3)
+	amountPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+)
+
+// PaymentTransaction 伺服器發出的交易資料，對應 SPC 的 CollectedClientPaymentData
+// 登入選項的 payment 擴充與驗證器回傳的 clientDataJSON 都以此結構比對
+type PaymentTransaction struct {
+	RPID        string                      `json:"rpId"`
+	TopOrigin   string                      `json:"topOrigin,omitempty"`
+	PayeeName   string                      `json:"payeeName,omitempty"`
+	PayeeOrigin string                      `json:"payeeOrigin,omitempty"`
+	Total       PaymentCurrencyAmount       `json:"total"`
+	Instrument  PaymentCredentialInstrument `json:"instrument"`
+}
+
+// PaymentCurrencyAmount 交易金額
+type PaymentCurrencyAmount struct {
+	Currency string `json:"currency"`
+	Value    string `json:"value"`
+}
+
+// PaymentCredentialInstrument 顯示給使用者確認的付款工具 (例如卡片名稱與圖示)
+type PaymentCredentialInstrument struct {
+	DisplayName string `json:"displayName"`
+	Icon        string `json:"icon"`
+}
+
+// Validate 檢查交易資料，收款方名稱與 Origin 至少需要一個
+func (t *PaymentTransaction) Validate() error {
+	if t.PayeeName == "" && t.PayeeOrigin == "" {
+		return fmt.Errorf("%w: payeeName or payeeOrigin is required", ErrInvalidPaymentTransaction)
+	}
+	for _, origin := range []string{t.PayeeOrigin, t.TopOrigin} {
+		if origin == "" {
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme != "https" || u.Host == "" || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+			return fmt.Errorf("%w: %q is not an https origin", ErrInvalidPaymentTransaction, origin)
+		}
+	}
+	if !currencyPattern.MatchString(t.Total.Currency) || !amountPattern.MatchString(t.Total.Value) {
+		return fmt.Errorf("%w: total must have an ISO 4217 currency and a decimal value", ErrInvalidPaymentTransaction)
+	}
+	if t.Instrument.DisplayName == "" {
+		return fmt.Errorf("%w: instrument displayName is required", ErrInvalidPaymentTransaction)
+	}
+	if u, err := url.Parse(t.Instrument.Icon); err != nil || u.Scheme == "" {
+		return fmt.Errorf("%w: instrument icon must be an absolute URL", ErrInvalidPaymentTransaction)
+	}
+	return nil
+}
+
+// Extension 產生登入選項中的 payment 擴充 (AuthenticationExtensionsPaymentInputs)
+// 以 JSON 轉為 map，SessionData 存入 Redis / Postgres 後仍可還原
+func (t *PaymentTransaction) Extension() map[string]any {
+	data, _ := json.Marshal(t)
+	extension := map[string]any{}
+	_ = json.Unmarshal(data, &extension)
+	extension["isPayment"] = true
+	return extension
+}
+
+// PaymentTransactionFromSession 從 SessionData 的擴充取回交易資料，非 SPC 的登入回傳 nil
+func PaymentTransactionFromSession(extensions protocol.AuthenticationExtensions) *PaymentTransaction {
+	value, ok := extensions[PaymentExtension]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var transaction PaymentTransaction
+	if err := json.Unmarshal(data, &transaction); err != nil {
+		return nil
+	}
+	return &transaction
+}
+
+// paymentClientData payment.get 的 clientDataJSON 中需要比對的欄位
+type paymentClientData struct {
+	Type    protocol.CeremonyType `json:"type"`
+	Origin  string                `json:"origin"`
+	Payment *PaymentTransaction   `json:"payment"`
+}
+
+// VerifyClientData 確認 clientDataJSON 為 payment.get，且使用者確認的交易與伺服器發出的完全相同
+// 由收款方頁面呼叫 SPC 時 origin 為收款方，因此 origin 與 topOrigin 可為 RP 的 Origin 或收款方 Origin
+func (t *PaymentTransaction) VerifyClientData(clientDataJSON []byte, origins []string) error {
+	var clientData paymentClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return fmt.Errorf("%w: %v", ErrPaymentClientData, err)
+	}
+	if clientData.Type != PaymentCeremony {
+		return fmt.Errorf("%w: unexpected type %q", ErrPaymentClientData, clientData.Type)
+	}
+	if clientData.Payment == nil {
+		return fmt.Errorf("%w: payment data is missing", ErrPaymentClientData)
+	}
+
+	allowed := origins
+	if t.PayeeOrigin != "" {
+		allowed = append(slices.Clone(origins), t.PayeeOrigin)
+	}
+	if !slices.Contains(allowed, clientData.Origin) {
+		return fmt.Errorf("%w: unexpected origin %q", ErrPaymentClientData, clientData.Origin)
+	}
+
+	expected := *t
+	if expected.TopOrigin == "" {
+		// 未指定 topOrigin 時由瀏覽器填入實際的頂層頁面
+		if !slices.Contains(allowed, clientData.Payment.TopOrigin) {
+			return fmt.Errorf("%w: unexpected top origin %q", ErrPaymentClientData, clientData.Payment.TopOrigin)
+		}
+		expected.TopOrigin = clientData.Payment.TopOrigin
+	}
+	if *clientData.Payment != expected {
+		return fmt.Errorf("%w: transaction details differ", ErrPaymentClientData)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,140 @@
+package webauthn
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+func testPaymentTransaction() *PaymentTransaction {
+	return &PaymentTransaction{
+		RPID:        "bank.example",
+		PayeeName:   "Example Store",
+		PayeeOrigin: "https://store.example",
+		Total:       PaymentCurrencyAmount{Currency: "TWD", Value: "1280.00"},
+		Instrument:  PaymentCredentialInstrument{DisplayName: "Visa ****1234", Icon: "https://bank.example/card.png"},
+	}
+}
+
+// paymentClientDataJSON 組出瀏覽器回傳的 payment.get clientDataJSON
+func paymentClientDataJSON(t *testing.T, ceremony protocol.CeremonyType, origin string, payment PaymentTransaction) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{
+		"type":      ceremony,
+		"challenge": "challenge",
+		"origin":    origin,
+		"payment":   payment,
+	})
+	if err != nil {
+		t.Fatalf("clientDataJSON 編碼失敗: %v", err)
+	}
+	return data
+}
+
+func TestPaymentTransaction_Validate(t *testing.T) {
+	if err := testPaymentTransaction().Validate(); err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(transaction *PaymentTransaction)
+	}{
+		{name: "缺少收款方", modify: func(tx *PaymentTransaction) { tx.PayeeName, tx.PayeeOrigin = "", "" }},
+		{name: "收款方 Origin 不是 https", modify: func(tx *PaymentTransaction) { tx.PayeeOrigin = "http://store.example" }},
+		{name: "收款方 Origin 含路徑", modify: func(tx *PaymentTransaction) { tx.PayeeOrigin = "https://store.example/checkout" }},
+		{name: "幣別格式錯誤", modify: func(tx *PaymentTransaction) { tx.Total.Currency = "NT$" }},
+		{name: "金額格式錯誤", modify: func(tx *PaymentTransaction) { tx.Total.Value = "1,280" }},
+		{name: "缺少付款工具名稱", modify: func(tx *PaymentTransaction) { tx.Instrument.DisplayName = "" }},
+		{name: "付款工具圖示不是網址", modify: func(tx *PaymentTransaction) { tx.Instrument.Icon = "card.png" }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			transaction := testPaymentTransaction()
+			tc.modify(transaction)
+			if err := transaction.Validate(); !errors.Is(err, ErrInvalidPaymentTransaction) {
+				t.Errorf("應回傳 ErrInvalidPaymentTransaction，got=%v", err)
+			}
+		})
+	}
+}
+
+func TestPaymentTransactionFromSession(t *testing.T) {
+	transaction := testPaymentTransaction()
+
+	// 模擬 SessionData 存入 Redis / Postgres 後再取回
+	data, _ := json.Marshal(protocol.AuthenticationExtensions{PaymentExtension: transaction.Extension()})
+	var extensions protocol.AuthenticationExtensions
+	if err := json.Unmarshal(data, &extensions); err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+	if extensions[PaymentExtension].(map[string]any)["isPayment"] != true {
+		t.Errorf("payment 擴充應帶有 isPayment，got=%v", extensions[PaymentExtension])
+	}
+
+	restored := PaymentTransactionFromSession(extensions)
+	if restored == nil || *restored != *transaction {
+		t.Errorf("交易資料不一致，got=%+v", restored)
+	}
+
+	if PaymentTransactionFromSession(protocol.AuthenticationExtensions{"appid": "https://example.com"}) != nil {
+		t.Errorf("非 SPC 的登入應回傳 nil")
+	}
+}
+
+func TestPaymentTransaction_VerifyClientData(t *testing.T) {
+	origins := []string{"https://bank.example"}
+	transaction := testPaymentTransaction()
+
+	confirmed := *transaction
+	confirmed.TopOrigin = "https://store.example"
+
+	// 由收款方頁面呼叫，瀏覽器填入 topOrigin
+	if err := transaction.VerifyClientData(paymentClientDataJSON(t, PaymentCeremony, "https://store.example", confirmed), origins); err != nil {
+		t.Errorf("不應該有錯: %v", err)
+	}
+
+	// 由 RP 自己的頁面呼叫
+	confirmed.TopOrigin = "https://bank.example"
+	if err := transaction.VerifyClientData(paymentClientDataJSON(t, PaymentCeremony, "https://bank.example", confirmed), origins); err != nil {
+		t.Errorf("不應該有錯: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		ceremony   protocol.CeremonyType
+		origin     string
+		modify     func(payment *PaymentTransaction)
+		clientData []byte
+	}{
+		{name: "一般登入的 clientDataJSON", ceremony: protocol.AssertCeremony, origin: "https://store.example"},
+		{name: "未知的 Origin", ceremony: PaymentCeremony, origin: "https://evil.example"},
+		{name: "金額被竄改", ceremony: PaymentCeremony, origin: "https://store.example", modify: func(p *PaymentTransaction) { p.Total.Value = "1.00" }},
+		{name: "收款方被竄改", ceremony: PaymentCeremony, origin: "https://store.example", modify: func(p *PaymentTransaction) { p.PayeeName = "Evil Store" }},
+		{name: "付款工具不同", ceremony: PaymentCeremony, origin: "https://store.example", modify: func(p *PaymentTransaction) { p.Instrument.DisplayName = "Visa ****9999" }},
+		{name: "未知的 topOrigin", ceremony: PaymentCeremony, origin: "https://store.example", modify: func(p *PaymentTransaction) { p.TopOrigin = "https://evil.example" }},
+		{name: "RP ID 不同", ceremony: PaymentCeremony, origin: "https://store.example", modify: func(p *PaymentTransaction) { p.RPID = "evil.example" }},
+		{name: "缺少 payment", clientData: []byte(`{"type":"payment.get","origin":"https://store.example"}`)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clientData := tc.clientData
+			if clientData == nil {
+				payment := *transaction
+				payment.TopOrigin = "https://store.example"
+				if tc.modify != nil {
+					tc.modify(&payment)
+				}
+				clientData = paymentClientDataJSON(t, tc.ceremony, tc.origin, payment)
+			}
+			if err := transaction.VerifyClientData(clientData, origins); !errors.Is(err, ErrPaymentClientData) {
+				t.Errorf("應回傳 ErrPaymentClientData，got=%v", err)
+			}
+		})
+	}
+}
\ No newline at end of file
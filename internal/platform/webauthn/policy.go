@@ -0,0 +1,235 @@
+package webauthn
+
+import (
+	"fido2/config"
+	"fido2/pkg/utils/common"
+	"fmt"
+	"slices"
+
+	"github.com/go-webauthn/webauthn/metadata"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+// Attestation 類型 (WebAuthn §6.5.4)
+const (
+	AttestationTypeNone  = "none"
+	AttestationTypeSelf  = "self"
+	AttestationTypeBasic = "basic"
+	AttestationTypeAttCA = "attca"
+)
+
+// certificationLevels MDS 認證狀態對應的等級，數字越大越嚴格
+var certificationLevels = map[metadata.AuthenticatorStatus]int{
+	metadata.FidoCertified:       1,
+	metadata.FidoCertifiedL1:     1,
+	metadata.FidoCertifiedL1plus: 2,
+	metadata.FidoCertifiedL2:     3,
+	metadata.FidoCertifiedL2plus: 4,
+	metadata.FidoCertifiedL3:     5,
+	metadata.FidoCertifiedL3plus: 6,
+}
+
+// PolicyViolation 驗證器不符合租戶的驗證器條件，Code 為回應中的 errorCode
+type PolicyViolation struct {
+	Code   string
+	Reason string
+}
+
+func (v *PolicyViolation) Error() string {
+	return "authenticator policy violation: " + v.Reason
+}
+
+// AuthenticatorPolicy 註冊時檢查驗證器是否允許使用，nil 表示不限制
+type AuthenticatorPolicy struct {
+	allowed           map[uuid.UUID]bool
+	denied            map[uuid.UUID]bool
+	minLevel          int
+	minLevelName      string
+	attestationTypes  []string
+	backupEligibility string
+	attachment        protocol.AuthenticatorAttachment
+	credProtect       int
+	credProtectName   string
+	minPinLength      int
+}
+
+// NewAuthenticatorPolicy 依設定建立驗證器條件，未設定任何條件時回傳 nil
+func NewAuthenticatorPolicy(cfg config.AuthenticatorPolicy) (*AuthenticatorPolicy, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	p := &AuthenticatorPolicy{
+		allowed:           make(map[uuid.UUID]bool, len(cfg.AllowedAAGUIDs)),
+		denied:            make(map[uuid.UUID]bool, len(cfg.DeniedAAGUIDs)),
+		minLevel:          slices.Index(config.CertificationLevels, cfg.MinCertificationLevel) + 1,
+		minLevelName:      cfg.MinCertificationLevel,
+		attestationTypes:  cfg.AttestationTypes,
+		backupEligibility: cfg.BackupEligibility,
+		attachment:        protocol.AuthenticatorAttachment(cfg.Attachment),
+		credProtect:       slices.Index(config.CredentialProtectionPolicies, cfg.CredentialProtection) + 1,
+		credProtectName:   cfg.CredentialProtection,
+		minPinLength:      cfg.MinPinLength,
+	}
+	for _, aaguid := range cfg.AllowedAAGUIDs {
+		p.allowed[uuid.MustParse(aaguid)] = true
+	}
+	for _, aaguid := range cfg.DeniedAAGUIDs {
+		p.denied[uuid.MustParse(aaguid)] = true
+	}
+
+	if len(p.allowed) == 0 && len(p.denied) == 0 && p.minLevel == 0 && len(p.attestationTypes) == 0 &&
+		p.backupEligibility == "" && p.attachment == "" && p.credProtect == 0 && p.minPinLength == 0 {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// RegistrationExtensions 註冊時需要驗證器回報的擴充 (credProtect / minPinLength)，未要求時回傳 nil
+func (p *AuthenticatorPolicy) RegistrationExtensions() protocol.AuthenticationExtensions {
+	if p == nil || (p.credProtect == 0 && p.minPinLength == 0) {
+		return nil
+	}
+
+	extensions := protocol.AuthenticationExtensions{}
+	if p.credProtect > 0 {
+		// 驗證器不支援 credProtect 時由用戶端直接拒絕建立
+		extensions["credentialProtectionPolicy"] = p.credProtectName
+		extensions["enforceCredentialProtectionPolicy"] = true
+	}
+	if p.minPinLength > 0 {
+		extensions["minPinLength"] = true
+	}
+	return extensions
+}
+
+// Evaluate 檢查新註冊的 Credential 是否符合驗證器條件，不符合時回傳 *PolicyViolation
+// att 為解析後的 Attestation，entry 為驗證器在 FIDO Metadata 中的資料，未載入 Metadata 或查無資料時為 nil
+func (p *AuthenticatorPolicy) Evaluate(credential *webauthn.Credential, att protocol.AttestationObject, entry *metadata.Entry) error {
+	if p == nil {
+		return nil
+	}
+
+	attestationType := AttestationType(att)
+
+	aaguid, _ := uuid.FromBytes(credential.Authenticator.AAGUID)
+	if p.denied[aaguid] {
+		return &PolicyViolation{Code: common.ErrorCodeAuthenticatorNotAllowed, Reason: fmt.Sprintf("authenticator %s is denied", aaguid)}
+	}
+	if len(p.allowed) > 0 && !p.allowed[aaguid] {
+		return &PolicyViolation{Code: common.ErrorCodeAuthenticatorNotAllowed, Reason: fmt.Sprintf("authenticator %s is not in the allow list", aaguid)}
+	}
+
+	if len(p.attestationTypes) > 0 && !slices.Contains(p.attestationTypes, attestationType) {
+		return &PolicyViolation{Code: common.ErrorCodeAttestationTypeNotAllowed, Reason: fmt.Sprintf("attestation type %q is not allowed", attestationType)}
+	}
+
+	// 只有經過驗證的 Attestation 才能證明 AAGUID 屬實，none 與 self 無法作為認證等級的依據
+	if p.minLevel > 0 {
+		if attestationType != AttestationTypeBasic && attestationType != AttestationTypeAttCA {
+			return &PolicyViolation{Code: common.ErrorCodeCertificationRequired, Reason: fmt.Sprintf("certification level %s requires a verifiable attestation", p.minLevelName)}
+		}
+		if CertificationLevel(entry) < p.minLevel {
+			return &PolicyViolation{Code: common.ErrorCodeCertificationRequired, Reason: fmt.Sprintf("authenticator %s is not certified at level %s", aaguid, p.minLevelName)}
+		}
+	}
+
+	switch {
+	case p.backupEligibility == "required" && !credential.Flags.BackupEligible:
+		return &PolicyViolation{Code: common.ErrorCodeBackupEligibilityNotAllowed, Reason: "credential must be backup eligible"}
+	case p.backupEligibility == "forbidden" && credential.Flags.BackupEligible:
+		return &PolicyViolation{Code: common.ErrorCodeBackupEligibilityNotAllowed, Reason: "credential must be device-bound"}
+	}
+
+	// authenticatorAttachment 由用戶端回報，未回報時視為不符合
+	if p.attachment != "" && credential.Authenticator.Attachment != p.attachment {
+		return &PolicyViolation{Code: common.ErrorCodeAttachmentNotAllowed, Reason: fmt.Sprintf("authenticator attachment %q is not allowed", credential.Authenticator.Attachment)}
+	}
+
+	// credProtect 與 minPinLength 由驗證器寫在 authenticator data 的擴充輸出中，受 Attestation 簽章保護
+	if p.credProtect > 0 || p.minPinLength > 0 {
+		outputs, err := authenticatorExtensionOutputs(att.AuthData)
+		if err != nil {
+			return err
+		}
+		if level, _ := extensionUint(outputs["credProtect"]); int(level) < p.credProtect {
+			return &PolicyViolation{Code: common.ErrorCodeCredentialProtectionInsufficient, Reason: fmt.Sprintf("credential protection %s is required", p.credProtectName)}
+		}
+		if length, ok := extensionUint(outputs["minPinLength"]); p.minPinLength > 0 && (!ok || int(length) < p.minPinLength) {
+			return &PolicyViolation{Code: common.ErrorCodeMinPinLengthInsufficient, Reason: fmt.Sprintf("minimum PIN length %d is required", p.minPinLength)}
+		}
+	}
+
+	return nil
+}
+
+// authenticatorExtensionOutputs 解析 authenticator data 中的擴充輸出 (CBOR map)，未帶 ED 旗標時回傳空值
+func authenticatorExtensionOutputs(authData protocol.AuthenticatorData) (map[string]any, error) {
+	outputs := map[string]any{}
+	if !authData.Flags.HasExtensions() || len(authData.ExtData) == 0 {
+		return outputs, nil
+	}
+	if err := webauthncbor.Unmarshal(authData.ExtData, &outputs); err != nil {
+		return nil, fmt.Errorf("invalid authenticator extension outputs: %w", err)
+	}
+	return outputs, nil
+}
+
+// extensionUint 將 CBOR 解出的整數轉為 uint64
+func extensionUint(value any) (uint64, bool) {
+	switch v := value.(type) {
+	case uint64:
+		return v, true
+	case int64:
+		if v >= 0 {
+			return uint64(v), true
+		}
+	}
+	return 0, false
+}
+
+// CertificationLevel 取得驗證器在 FIDO Metadata 中的最高認證等級，未經認證或查無資料時回傳 0
+func CertificationLevel(entry *metadata.Entry) int {
+	if entry == nil {
+		return 0
+	}
+	level := 0
+	for _, report := range entry.StatusReports {
+		level = max(level, certificationLevels[report.Status])
+	}
+	return level
+}
+
+// AttestationType 依 Attestation 格式與憑證判斷 Attestation 類型，無法判斷時回傳空字串
+func AttestationType(att protocol.AttestationObject) string {
+	_, hasX5C := att.AttStatement["x5c"]
+	switch att.Format {
+	case string(protocol.AttestationFormatNone):
+		return AttestationTypeNone
+	case string(protocol.AttestationFormatPacked):
+		if hasX5C {
+			return AttestationTypeBasic
+		}
+		return AttestationTypeSelf
+	case string(protocol.AttestationFormatFIDOUniversalSecondFactor),
+		string(protocol.AttestationFormatAndroidKey),
+		string(protocol.AttestationFormatAndroidSafetyNet):
+		return AttestationTypeBasic
+	case string(protocol.AttestationFormatTPM), string(protocol.AttestationFormatApple):
+		// TPM 由 Attestation CA 簽發 AIK 憑證，Apple 為匿名化 CA，皆歸類為 attca
+		return AttestationTypeAttCA
+	default:
+		return ""
+	}
+}
+
+// MetadataEntry 由目前載入的 FIDO Metadata 查詢驗證器資料，未載入或查無資料時回傳 nil
+func MetadataEntry(aaguid []byte) *metadata.Entry {
+	if m := loadedMetadata.Load(); m != nil {
+		return m.Entry(aaguid)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,272 @@
+package webauthn
+
+import (
+	"errors"
+	"fido2/config"
+	"fido2/pkg/utils/common"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/metadata"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+const (
+	policyTestAAGUID = "cb69481e-8ff7-4039-93ec-0a2729a154a8"
+	otherTestAAGUID  = "ee882879-721c-4913-9775-3dfcce97072a"
+)
+
+// newPolicyTestCredential 建立指定 AAGUID、備份旗標與連接方式的 Credential
+func newPolicyTestCredential(aaguid string, backupEligible bool, attachment protocol.AuthenticatorAttachment) *webauthn.Credential {
+	id := uuid.MustParse(aaguid)
+	return &webauthn.Credential{
+		Flags: webauthn.CredentialFlags{BackupEligible: backupEligible},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:     id[:],
+			Attachment: attachment,
+		},
+	}
+}
+
+// packedAttestation 建立 packed 格式的 Attestation，withX5C 為 true 時為 basic，否則為 self
+func packedAttestation(withX5C bool) protocol.AttestationObject {
+	statement := map[string]any{"alg": int64(-7), "sig": []byte{1}}
+	if withX5C {
+		statement["x5c"] = []any{[]byte{1}}
+	}
+	return protocol.AttestationObject{Format: "packed", AttStatement: statement}
+}
+
+// extensionAttestation 建立 authenticator data 帶有指定擴充輸出的 Attestation
+func extensionAttestation(t *testing.T, outputs map[string]any) protocol.AttestationObject {
+	t.Helper()
+
+	att := protocol.AttestationObject{Format: "none"}
+	if outputs != nil {
+		extData, err := webauthncbor.Marshal(outputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		att.AuthData = protocol.AuthenticatorData{Flags: protocol.FlagHasExtensions, ExtData: extData}
+	}
+	return att
+}
+
+func TestNewAuthenticatorPolicy_Empty(t *testing.T) {
+	policy, err := NewAuthenticatorPolicy(config.AuthenticatorPolicy{})
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+	if policy != nil {
+		t.Errorf("未設定任何條件時應回傳 nil")
+	}
+	if err := policy.Evaluate(newPolicyTestCredential(otherTestAAGUID, true, ""), protocol.AttestationObject{}, nil); err != nil {
+		t.Errorf("nil 的條件不應拒絕任何驗證器: %v", err)
+	}
+}
+
+func TestNewAuthenticatorPolicy_Invalid(t *testing.T) {
+	if _, err := NewAuthenticatorPolicy(config.AuthenticatorPolicy{AllowedAAGUIDs: []string{"yubikey"}}); err == nil {
+		t.Errorf("AAGUID 格式錯誤時應回傳錯誤")
+	}
+}
+
+func TestAuthenticatorPolicy_Evaluate(t *testing.T) {
+	certified := &metadata.Entry{
+		StatusReports: []metadata.StatusReport{
+			{Status: metadata.FidoCertifiedL1},
+			{Status: metadata.FidoCertifiedL2},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		policy     config.AuthenticatorPolicy
+		credential *webauthn.Credential
+		att        protocol.AttestationObject
+		entry      *metadata.Entry
+		wantCode   string
+	}{
+		{
+			name:       "允許清單中的驗證器",
+			policy:     config.AuthenticatorPolicy{AllowedAAGUIDs: []string{policyTestAAGUID}},
+			credential: newPolicyTestCredential(policyTestAAGUID, false, ""),
+		},
+		{
+			name:       "不在允許清單中",
+			policy:     config.AuthenticatorPolicy{AllowedAAGUIDs: []string{policyTestAAGUID}},
+			credential: newPolicyTestCredential(otherTestAAGUID, false, ""),
+			wantCode:   common.ErrorCodeAuthenticatorNotAllowed,
+		},
+		{
+			name:       "在拒絕清單中",
+			policy:     config.AuthenticatorPolicy{DeniedAAGUIDs: []string{policyTestAAGUID}},
+			credential: newPolicyTestCredential(policyTestAAGUID, false, ""),
+			wantCode:   common.ErrorCodeAuthenticatorNotAllowed,
+		},
+		{
+			name:       "Attestation 類型不允許",
+			policy:     config.AuthenticatorPolicy{AttestationTypes: []string{"basic", "attca"}},
+			credential: newPolicyTestCredential(policyTestAAGUID, false, ""),
+			att:        packedAttestation(false),
+			wantCode:   common.ErrorCodeAttestationTypeNotAllowed,
+		},
+		{
+			name:       "達到認證等級",
+			policy:     config.AuthenticatorPolicy{MinCertificationLevel: "L2"},
+			credential: newPolicyTestCredential(policyTestAAGUID, false, ""),
+			att:        packedAttestation(true),
+			entry:      certified,
+		},
+		{
+			name:       "未達認證等級",
+			policy:     config.AuthenticatorPolicy{MinCertificationLevel: "L2plus"},
+			credential: newPolicyTestCredential(policyTestAAGUID, false, ""),
+			att:        packedAttestation(true),
+			entry:      certified,
+			wantCode:   common.ErrorCodeCertificationRequired,
+		},
+		{
+			name:       "查無 Metadata",
+			policy:     config.AuthenticatorPolicy{MinCertificationLevel: "L1"},
+			credential: newPolicyTestCredential(policyTestAAGUID, false, ""),
+			att:        packedAttestation(true),
+			wantCode:   common.ErrorCodeCertificationRequired,
+		},
+		{
+			name:       "Self Attestation 無法證明認證等級",
+			policy:     config.AuthenticatorPolicy{MinCertificationLevel: "L1"},
+			credential: newPolicyTestCredential(policyTestAAGUID, false, ""),
+			att:        packedAttestation(false),
+			entry:      certified,
+			wantCode:   common.ErrorCodeCertificationRequired,
+		},
+		{
+			name:       "要求可同步但為裝置綁定",
+			policy:     config.AuthenticatorPolicy{BackupEligibility: "required"},
+			credential: newPolicyTestCredential(policyTestAAGUID, false, ""),
+			wantCode:   common.ErrorCodeBackupEligibilityNotAllowed,
+		},
+		{
+			name:       "要求裝置綁定但可同步",
+			policy:     config.AuthenticatorPolicy{BackupEligibility: "forbidden"},
+			credential: newPolicyTestCredential(policyTestAAGUID, true, ""),
+			wantCode:   common.ErrorCodeBackupEligibilityNotAllowed,
+		},
+		{
+			name:       "連接方式符合",
+			policy:     config.AuthenticatorPolicy{Attachment: "cross-platform"},
+			credential: newPolicyTestCredential(policyTestAAGUID, false, protocol.CrossPlatform),
+		},
+		{
+			name:       "連接方式不符",
+			policy:     config.AuthenticatorPolicy{Attachment: "cross-platform"},
+			credential: newPolicyTestCredential(policyTestAAGUID, true, protocol.Platform),
+			wantCode:   common.ErrorCodeAttachmentNotAllowed,
+		},
+		{
+			name:       "未回報連接方式",
+			policy:     config.AuthenticatorPolicy{Attachment: "platform"},
+			credential: newPolicyTestCredential(policyTestAAGUID, true, ""),
+			wantCode:   common.ErrorCodeAttachmentNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewAuthenticatorPolicy(tt.policy)
+			if err != nil {
+				t.Fatalf("不應該有錯: %v", err)
+			}
+
+			err = policy.Evaluate(tt.credential, tt.att, tt.entry)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("不應拒絕: %v", err)
+				}
+				return
+			}
+
+			var violation *PolicyViolation
+			if !errors.As(err, &violation) {
+				t.Fatalf("應回傳 PolicyViolation，got=%v", err)
+			}
+			if violation.Code != tt.wantCode {
+				t.Errorf("errorCode 錯誤，got=%q want=%q", violation.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorPolicy_AuthenticatorExtensions(t *testing.T) {
+	policy, err := NewAuthenticatorPolicy(config.AuthenticatorPolicy{
+		CredentialProtection: "userVerificationRequired",
+		MinPinLength:         6,
+	})
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+
+	extensions := policy.RegistrationExtensions()
+	if extensions["credentialProtectionPolicy"] != "userVerificationRequired" || extensions["enforceCredentialProtectionPolicy"] != true || extensions["minPinLength"] != true {
+		t.Errorf("註冊時應要求 credProtect 與 minPinLength，got=%v", extensions)
+	}
+
+	tests := []struct {
+		name     string
+		outputs  map[string]any
+		wantCode string
+	}{
+		{name: "符合條件", outputs: map[string]any{"credProtect": 3, "minPinLength": 8}},
+		{name: "未回報擴充", wantCode: common.ErrorCodeCredentialProtectionInsufficient},
+		{name: "credProtect 等級不足", outputs: map[string]any{"credProtect": 2, "minPinLength": 8}, wantCode: common.ErrorCodeCredentialProtectionInsufficient},
+		{name: "未回報 PIN 長度", outputs: map[string]any{"credProtect": 3}, wantCode: common.ErrorCodeMinPinLengthInsufficient},
+		{name: "PIN 長度不足", outputs: map[string]any{"credProtect": 3, "minPinLength": 4}, wantCode: common.ErrorCodeMinPinLengthInsufficient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Evaluate(newPolicyTestCredential(policyTestAAGUID, false, ""), extensionAttestation(t, tt.outputs), nil)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("不應拒絕: %v", err)
+				}
+				return
+			}
+
+			var violation *PolicyViolation
+			if !errors.As(err, &violation) || violation.Code != tt.wantCode {
+				t.Errorf("errorCode 錯誤，got=%v want=%q", err, tt.wantCode)
+			}
+		})
+	}
+
+	// 未設定 credProtect 與 minPinLength 時不要求擴充
+	if extensions := (&AuthenticatorPolicy{}).RegistrationExtensions(); extensions != nil {
+		t.Errorf("未設定時不應要求擴充，got=%v", extensions)
+	}
+}
+
+func TestAttestationType(t *testing.T) {
+	tests := []struct {
+		format    string
+		statement map[string]any
+		want      string
+	}{
+		{format: "none", want: AttestationTypeNone},
+		{format: "packed", statement: map[string]any{"alg": -7, "sig": []byte{1}}, want: AttestationTypeSelf},
+		{format: "packed", statement: map[string]any{"x5c": []any{[]byte{1}}}, want: AttestationTypeBasic},
+		{format: "fido-u2f", want: AttestationTypeBasic},
+		{format: "tpm", want: AttestationTypeAttCA},
+		{format: "unknown", want: ""},
+	}
+
+	for _, tt := range tests {
+		got := AttestationType(protocol.AttestationObject{Format: tt.format, AttStatement: tt.statement})
+		if got != tt.want {
+			t.Errorf("%s 的 Attestation 類型錯誤，got=%q want=%q", tt.format, got, tt.want)
+		}
+	}
+}
\ No newline at end of file
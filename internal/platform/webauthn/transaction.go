@@ -0,0 +1,92 @@
+package webauthn
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fido2/internal/entity"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// TransactionTTL 交易建立後等待使用者確認的期限
+	TransactionTTL = 5 * time.Minute
+
+	// maxPayeeLength 收款方名稱的最大長度
+	maxPayeeLength = 140
+
+	// transactionHashDomain 交易雜湊值的用途標記，避免與其他 Challenge 混用
+	transactionHashDomain = "fido2-transaction-v1"
+)
+
+// ErrInvalidTransaction 交易資料不完整或格式錯誤
+var ErrInvalidTransaction = errors.New("invalid transaction")
+
+// transactionAmountPattern 交易金額需為正的十進位數字
+var transactionAmountPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// NewTransaction 建立等待確認的交易，並產生 Nonce 與作為 Challenge 的交易雜湊值
+func NewTransaction(tenantID, userID, payee, amount, currency string, now time.Time) (*entity.Transaction, error) {
+	payee = strings.TrimSpace(payee)
+	if payee == "" || utf8.RuneCountInString(payee) > maxPayeeLength {
+		return nil, fmt.Errorf("%w: payee must be 1 to %d characters", ErrInvalidTransaction, maxPayeeLength)
+	}
+	if !transactionAmountPattern.MatchString(amount) || strings.Trim(amount, "0.") == "" {
+		return nil, fmt.Errorf("%w: amount must be a positive decimal value", ErrInvalidTransaction)
+	}
+	if !currencyPattern.MatchString(currency) {
+		return nil, fmt.Errorf("%w: currency must be an ISO 4217 code", ErrInvalidTransaction)
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	transaction := &entity.Transaction{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		UserID:    userID,
+		Payee:     payee,
+		Amount:    amount,
+		Currency:  strings.ToUpper(currency),
+		Nonce:     base64.RawURLEncoding.EncodeToString(nonce),
+		Status:    entity.TransactionPending,
+		ExpiresAt: now.Add(TransactionTTL),
+	}
+	transaction.Hash = base64.RawURLEncoding.EncodeToString(TransactionHash(transaction))
+	return transaction, nil
+}
+
+// TransactionHash 計算交易內容的 SHA-256 雜湊值，作為登入選項的 Challenge (PSD2 動態連結)
+// 依序將租戶、交易 ID、使用者、收款方、金額、幣別與 Nonce 以 4 bytes 長度前綴串接，欄位內容無法互相混淆
+func TransactionHash(transaction *entity.Transaction) []byte {
+	h := sha256.New()
+	h.Write([]byte(transactionHashDomain))
+	for _, field := range []string{
+		transaction.TenantID,
+		transaction.ID,
+		transaction.UserID,
+		transaction.Payee,
+		transaction.Amount,
+		transaction.Currency,
+		transaction.Nonce,
+	} {
+		_ = binary.Write(h, binary.BigEndian, uint32(len(field)))
+		h.Write([]byte(field))
+	}
+	return h.Sum(nil)
+}
+
+// VerifyTransactionHash 確認保存的雜湊值與目前的交易內容相符，交易內容在建立後被修改時回傳 false
+func VerifyTransactionHash(transaction *entity.Transaction) bool {
+	return transaction.Hash == base64.RawURLEncoding.EncodeToString(TransactionHash(transaction))
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+package webauthn
+
+import (
+	"encoding/base64"
+	"errors"
+	"fido2/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestNewTransaction(t *testing.T) {
+	now := time.Now()
+
+	transaction, err := NewTransaction("default", "1", " Example Store ", "1280.00", "twd", now)
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+	if transaction.Payee != "Example Store" || transaction.Currency != "TWD" {
+		t.Errorf("收款方或幣別未正規化，got=%q %q", transaction.Payee, transaction.Currency)
+	}
+	if transaction.Status != entity.TransactionPending || !transaction.ExpiresAt.Equal(now.Add(TransactionTTL)) {
+		t.Errorf("新交易應為 pending 並在 TransactionTTL 後過期，got=%s %s", transaction.Status, transaction.ExpiresAt)
+	}
+
+	hash, err := base64.RawURLEncoding.DecodeString(transaction.Hash)
+	if err != nil || len(hash) != 32 {
+		t.Errorf("交易雜湊值應為 32 bytes 的 base64url，got=%q", transaction.Hash)
+	}
+	if !VerifyTransactionHash(transaction) {
+		t.Errorf("交易雜湊值應與交易內容相符")
+	}
+
+	// 相同內容的交易因 Nonce 不同而有不同的 Challenge
+	other, _ := NewTransaction("default", "1", "Example Store", "1280.00", "TWD", now)
+	if other.Nonce == transaction.Nonce || other.Hash == transaction.Hash {
+		t.Errorf("每筆交易應有不同的 Nonce 與雜湊值")
+	}
+
+	tests := []struct {
+		name     string
+		payee    string
+		amount   string
+		currency string
+	}{
+		{name: "缺少收款方", payee: " ", amount: "10", currency: "TWD"},
+		{name: "負數金額", payee: "Example Store", amount: "-10", currency: "TWD"},
+		{name: "零元", payee: "Example Store", amount: "0.00", currency: "TWD"},
+		{name: "金額格式錯誤", payee: "Example Store", amount: "1,280", currency: "TWD"},
+		{name: "幣別格式錯誤", payee: "Example Store", amount: "10", currency: "NT$"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewTransaction("default", "1", tc.payee, tc.amount, tc.currency, now); !errors.Is(err, ErrInvalidTransaction) {
+				t.Errorf("應回傳 ErrInvalidTransaction，got=%v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyTransactionHash(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(transaction *entity.Transaction)
+	}{
+		{name: "金額被修改", modify: func(tx *entity.Transaction) { tx.Amount = "1.00" }},
+		{name: "收款方被修改", modify: func(tx *entity.Transaction) { tx.Payee = "Evil Store" }},
+		{name: "幣別被修改", modify: func(tx *entity.Transaction) { tx.Currency = "USD" }},
+		{name: "Nonce 被修改", modify: func(tx *entity.Transaction) { tx.Nonce = "bm9uY2U" }},
+		{name: "使用者被修改", modify: func(tx *entity.Transaction) { tx.UserID = "2" }},
+		// 欄位邊界移動後長度前綴不同，雜湊值也不同
+		{name: "欄位內容位移", modify: func(tx *entity.Transaction) { tx.Payee, tx.Amount = tx.Payee+"1", tx.Amount[1:] }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			transaction, err := NewTransaction("default", "1", "Example Store", "1280.00", "TWD", time.Now())
+			if err != nil {
+				t.Fatalf("不應該有錯: %v", err)
+			}
+			tc.modify(transaction)
+			if VerifyTransactionHash(transaction) {
+				t.Errorf("交易內容修改後雜湊值不應相符")
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,46 @@
+package webauthn
+
+import (
+	"crypto/ecdh"
+	"encoding/base64"
+	"errors"
+	"fido2/internal/entity"
+
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+// maxU2FKeyHandleLength U2F 的 key handle 長度以一個位元組表示 (FIDO U2F Raw Message Formats §4.3)
+const maxU2FKeyHandleLength = 255
+
+var (
+	// ErrInvalidU2FKeyHandle key handle 為空或超過 255 bytes
+	ErrInvalidU2FKeyHandle = errors.New("u2f key handle must be 1 to 255 bytes")
+
+	// ErrInvalidU2FPublicKey 公鑰不是未壓縮格式的 P-256 公鑰
+	ErrInvalidU2FPublicKey = errors.New("u2f public key must be an uncompressed P-256 point")
+)
+
+// NewU2FCredentialEntity 將舊版 U2F 金鑰 (key handle 與公鑰) 轉換為要儲存的 entity.Credential
+// Attestation 類型標記為 fido-u2f，登入時才會以 appid 擴充驗證 AppID 的 rpIdHash
+// 公鑰維持 U2F 的未壓縮格式 (65 bytes)，go-webauthn 在使用 AppID 時以此格式解析公鑰
+func NewU2FCredentialEntity(tenantID, userID string, keyHandle, publicKey []byte, signCount uint32) (*entity.Credential, error) {
+	if len(keyHandle) == 0 || len(keyHandle) > maxU2FKeyHandleLength {
+		return nil, ErrInvalidU2FKeyHandle
+	}
+
+	if _, err := ecdh.P256().NewPublicKey(publicKey); err != nil || len(publicKey) != 65 {
+		return nil, ErrInvalidU2FPublicKey
+	}
+
+	return &entity.Credential{
+		ID:              base64.RawURLEncoding.EncodeToString(keyHandle),
+		TenantID:        tenantID,
+		UserID:          userID,
+		PublicKey:       publicKey,
+		SignCount:       signCount,
+		Attachment:      string(protocol.CrossPlatform),
+		Transports:      []string{string(protocol.USB)},
+		UserPresent:     true,
+		AttestationType: string(protocol.CredentialTypeFIDOU2F),
+	}, nil
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+func TestNewU2FCredentialEntity(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("產生金鑰失敗: %v", err)
+	}
+	raw, err := key.PublicKey.ECDH()
+	if err != nil {
+		t.Fatalf("轉換公鑰失敗: %v", err)
+	}
+
+	credential, err := NewU2FCredentialEntity("default", "user-1", []byte("key-handle"), raw.Bytes(), 42)
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+	if credential.ID != "a2V5LWhhbmRsZQ" || credential.AttestationType != "fido-u2f" || credential.SignCount != 42 {
+		t.Errorf("Credential 欄位錯誤，got=%+v", credential)
+	}
+
+	// 保存的公鑰應可由 go-webauthn 以 U2F 格式解析並驗證簽章
+	parsed, err := webauthncose.ParseFIDOPublicKey(credential.PublicKey)
+	if err != nil {
+		t.Fatalf("無法解析 U2F 公鑰: %v", err)
+	}
+	message := []byte("signed data")
+	digest := sha256.Sum256(message)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("簽章失敗: %v", err)
+	}
+	if ok, err := webauthncose.VerifySignature(parsed, message, signature); err != nil || !ok {
+		t.Errorf("簽章驗證失敗，ok=%v err=%v", ok, err)
+	}
+}
+
+func TestNewU2FCredentialEntity_Invalid(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	raw, _ := key.PublicKey.ECDH()
+
+	if _, err := NewU2FCredentialEntity("default", "user-1", nil, raw.Bytes(), 0); !errors.Is(err, ErrInvalidU2FKeyHandle) {
+		t.Errorf("空的 key handle 應回傳 ErrInvalidU2FKeyHandle，got=%v", err)
+	}
+	if _, err := NewU2FCredentialEntity("default", "user-1", make([]byte, 256), raw.Bytes(), 0); !errors.Is(err, ErrInvalidU2FKeyHandle) {
+		t.Errorf("過長的 key handle 應回傳 ErrInvalidU2FKeyHandle，got=%v", err)
+	}
+
+	// 壓縮格式與不在曲線上的點都不接受
+	compressed := elliptic.MarshalCompressed(elliptic.P256(), key.PublicKey.X, key.PublicKey.Y)
+	offCurve := append([]byte{}, raw.Bytes()...)
+	offCurve[64] ^= 0x01
+	for _, publicKey := range [][]byte{compressed, offCurve, raw.Bytes()[:64]} {
+		if _, err := NewU2FCredentialEntity("default", "user-1", []byte("key-handle"), publicKey, 0); !errors.Is(err, ErrInvalidU2FPublicKey) {
+			t.Errorf("無效的公鑰應回傳 ErrInvalidU2FPublicKey，got=%v", err)
+		}
+	}
+}
\ No newline at end of file
@@ -1,30 +1,34 @@
 package webauthn
 
 import (
-	"encoding/json"
+	"bytes"
 	"fido2/internal/entity"
-	"fido2/internal/usecase/impl"
 	"fido2/pkg/utils"
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
-	"strconv"
-	"strings"
 )
 
 type UserWebAuthn struct {
 	*entity.User
+	Credentials []*entity.Credential
+
+	// useLegacyHandle 驗證器回傳的是舊版 user handle 時，改以舊版別名作為 WebAuthn ID
+	useLegacyHandle bool
 }
 
 // NewUserWebAuthn creates a new UserWebAuthn wrapper
-func NewUserWebAuthn(user *entity.User) *UserWebAuthn {
-	return &UserWebAuthn{User: user}
+func NewUserWebAuthn(user *entity.User, credentials []*entity.Credential) *UserWebAuthn {
+	return &UserWebAuthn{User: user, Credentials: credentials}
 }
 
 // WebAuthn 介面實作 - 這些方法實現了 webauthn.User 介面
 
 // WebAuthnID 取得使用者的 WebAuthn ID
 func (u *UserWebAuthn) WebAuthnID() []byte {
-	return []byte(u.UserName)
+	if u.useLegacyHandle {
+		return u.LegacyUserHandle
+	}
+	return u.UserHandle
 }
 
 // WebAuthnName 取得使用者的 WebAuthn 名稱
@@ -44,95 +48,43 @@ func (u *UserWebAuthn) WebAuthnIcon() string {
 
 // WebAuthnCredentials 取得使用者的所有 Credential
 func (u *UserWebAuthn) WebAuthnCredentials() []webauthn.Credential {
-	return u.getAllCredentials()
-}
-
-// CredentialExcludeList WebAuthnCredentialByID 根據 Credential ID 取得對應的 Credential
-func (u *UserWebAuthn) CredentialExcludeList() []protocol.CredentialDescriptor {
-	var credentialExcludeList []protocol.CredentialDescriptor
-	for _, credential := range u.WebAuthnCredentials() {
-		descriptor := credential.Descriptor()
-		credentialExcludeList = append(credentialExcludeList, descriptor)
-	}
-	return credentialExcludeList
-}
-
-// 輔助方法 - 用於處理 WebAuthn 相關邏輯
-
-// getAllCredentials 取得所有使用者的憑證
-func (u *UserWebAuthn) getAllCredentials() []webauthn.Credential {
-	credentials := []webauthn.Credential{}
-
-	// 取得所有使用者
-	userUseCase := impl.GetUserUseCase()
-	allUsers, err := userUseCase.GetUsers()
-	if err != nil {
-		utils.GetLogger().Errorf("Failed to get users: %v", err)
-		return credentials
-	}
-
-	// 遍歷所有使用者，處理他們的憑證
-	for _, user := range allUsers {
-		// 跳過沒有有效憑證的使用者
-		if user.Credential == "" || user.Credential == "`{}`" {
+	credentials := make([]webauthn.Credential, 0, len(u.Credentials))
+	for _, stored := range u.Credentials {
+		credential, err := ToWebAuthnCredential(stored)
+		if err != nil {
+			utils.GetLogger().Warnf("Failed to decode credential %s: %v", stored.ID, err)
 			continue
 		}
-
-		// 處理憑證字串
-		creds := parseCredential(user.Credential)
-		credentials = append(credentials, creds...)
+		credentials = append(credentials, credential)
 	}
-
 	return credentials
 }
 
-// parseCredential 解析單一使用者的憑證字串
-func parseCredential(credentialStr string) []webauthn.Credential {
-	logger := utils.GetLogger()
-
-	// 處理可能帶有反引號的憑證字串
-	unquoted := credentialStr
-	if strings.HasPrefix(credentialStr, "`") {
-		s, err := strconv.Unquote(credentialStr)
-		if err != nil {
-			logger.Debugf("Unquote failed for credential, using original: %v", err)
-			// 移除開頭和結尾的反引號
-			if len(credentialStr) > 2 {
-				unquoted = credentialStr[1 : len(credentialStr)-1]
-			}
-		} else {
-			unquoted = s
-		}
+// MatchUserHandle 檢查驗證器回傳的 userHandle 是否屬於此使用者
+// 若符合的是舊版別名，之後的 WebAuthnID 會回傳舊版別名
+func (u *UserWebAuthn) MatchUserHandle(userHandle []byte) bool {
+	if bytes.Equal(userHandle, u.UserHandle) {
+		u.useLegacyHandle = false
+		return true
 	}
-
-	// 嘗試多種格式解析憑證
-
-	// 嘗試解析為憑證陣列
-	var credentials []webauthn.Credential
-	if err := json.Unmarshal([]byte(unquoted), &credentials); err == nil {
-		return credentials
+	if len(u.LegacyUserHandle) > 0 && bytes.Equal(userHandle, u.LegacyUserHandle) {
+		u.useLegacyHandle = true
+		return true
 	}
+	return false
+}
 
-	// 嘗試解析為單一憑證
-	var credential webauthn.Credential
-	if err := json.Unmarshal([]byte(unquoted), &credential); err == nil {
-		return []webauthn.Credential{credential}
-	}
+// UsesLegacyHandle 是否正在使用舊版 user handle
+func (u *UserWebAuthn) UsesLegacyHandle() bool {
+	return u.useLegacyHandle
+}
 
-	// 嘗試透過 map 解析
-	var credsMap map[string]interface{}
-	if err := json.Unmarshal([]byte(unquoted), &credsMap); err == nil {
-		credsJson, err := json.Marshal(credsMap)
-		if err == nil {
-			var cred webauthn.Credential
-			if err := json.Unmarshal(credsJson, &cred); err == nil {
-				return []webauthn.Credential{cred}
-			}
-		}
+// CredentialExcludeList WebAuthnCredentialByID 根據 Credential ID 取得對應的 Credential
+func (u *UserWebAuthn) CredentialExcludeList() []protocol.CredentialDescriptor {
+	var credentialExcludeList []protocol.CredentialDescriptor
+	for _, credential := range u.WebAuthnCredentials() {
+		descriptor := credential.Descriptor()
+		credentialExcludeList = append(credentialExcludeList, descriptor)
 	}
-
-	// 解析失敗時記錄警告
-	utils.GetLogger().Warningf("Failed to parse credential, using original: %v", unquoted)
-	logger.Warnf("Failed to parse credential string: %s", credentialStr)
-	return []webauthn.Credential{}
+	return credentialExcludeList
 }
\ No newline at end of file
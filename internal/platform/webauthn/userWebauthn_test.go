@@ -0,0 +1,54 @@
+package webauthn
+
+import (
+	"bytes"
+	"fido2/internal/entity"
+	"testing"
+)
+
+func TestUserWebAuthn_MatchUserHandle(t *testing.T) {
+	user := &entity.User{
+		ID:               "1",
+		UserHandle:       []byte("random-handle"),
+		LegacyUserHandle: []byte("testuser"),
+		UserName:         "testuser",
+	}
+	webauthnUser := NewUserWebAuthn(user, nil)
+
+	// 預設使用隨機 user handle，不含使用者名稱
+	if !bytes.Equal(webauthnUser.WebAuthnID(), []byte("random-handle")) {
+		t.Errorf("WebAuthnID 錯誤：got=%s", webauthnUser.WebAuthnID())
+	}
+
+	// 舊版 Passkey 回傳使用者名稱作為 userHandle
+	if !webauthnUser.MatchUserHandle([]byte("testuser")) {
+		t.Fatal("舊版 user handle 應該符合")
+	}
+	if !webauthnUser.UsesLegacyHandle() || !bytes.Equal(webauthnUser.WebAuthnID(), []byte("testuser")) {
+		t.Errorf("應改用舊版別名：got=%s", webauthnUser.WebAuthnID())
+	}
+
+	// 新版 Passkey 回傳隨機 user handle
+	if !webauthnUser.MatchUserHandle([]byte("random-handle")) || webauthnUser.UsesLegacyHandle() {
+		t.Error("隨機 user handle 應該符合且不使用舊版別名")
+	}
+
+	if webauthnUser.MatchUserHandle([]byte("someone-else")) {
+		t.Error("其他使用者的 user handle 不應符合")
+	}
+}
+
+func TestNewUserHandle(t *testing.T) {
+	first, err := NewUserHandle()
+	if err != nil {
+		t.Fatalf("NewUserHandle 失敗: %v", err)
+	}
+	second, _ := NewUserHandle()
+
+	if len(first) != UserHandleLength {
+		t.Errorf("長度錯誤：got=%d want=%d", len(first), UserHandleLength)
+	}
+	if bytes.Equal(first, second) {
+		t.Error("兩次產生的 user handle 不應相同")
+	}
+}
\ No newline at end of file
@@ -1,28 +1,82 @@
 package webauthn
 
 import (
-	"fido2/pkg/utils"
+	"fido2/config"
+	"fmt"
 
+	"github.com/go-webauthn/webauthn/metadata"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
 	"github.com/go-webauthn/webauthn/webauthn"
 )
 
-var WebAuthn *webauthn.WebAuthn
+// coseAlgorithms 設定檔中演算法名稱與 COSE 演算法代碼的對應
+var coseAlgorithms = map[string]webauthncose.COSEAlgorithmIdentifier{
+	"ES256":  webauthncose.AlgES256,
+	"ES384":  webauthncose.AlgES384,
+	"ES512":  webauthncose.AlgES512,
+	"EdDSA":  webauthncose.AlgEdDSA,
+	"ES256K": webauthncose.AlgES256K,
+	"RS256":  webauthncose.AlgRS256,
+	"RS384":  webauthncose.AlgRS384,
+	"RS512":  webauthncose.AlgRS512,
+	"PS256":  webauthncose.AlgPS256,
+	"PS384":  webauthncose.AlgPS384,
+	"PS512":  webauthncose.AlgPS512,
+	"RS1":    webauthncose.AlgRS1,
+}
+
+// topOriginModes 設定檔中 Top Origin 驗證方式與 go-webauthn 的對應
+var topOriginModes = map[string]protocol.TopOriginVerificationMode{
+	"ignore":   protocol.TopOriginIgnoreVerificationMode,
+	"auto":     protocol.TopOriginAutoVerificationMode,
+	"implicit": protocol.TopOriginImplicitVerificationMode,
+	"explicit": protocol.TopOriginExplicitVerificationMode,
+}
+
+// NewWebAuthn 依 RP 設定建立 go-webauthn 實例與註冊時允許的公鑰演算法
+// mds 不為 nil 時註冊會以 FIDO Metadata 驗證 Attestation 信任鏈與驗證器狀態
+func NewWebAuthn(rpConfig *config.RPConfig, mds metadata.Provider) (*webauthn.WebAuthn, []protocol.CredentialParameter, error) {
+	params := make([]protocol.CredentialParameter, 0, len(rpConfig.Algorithms))
+	for _, name := range rpConfig.Algorithms {
+		alg, ok := coseAlgorithms[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("unsupported COSE algorithm %q", name)
+		}
+		params = append(params, protocol.CredentialParameter{
+			Type:      protocol.PublicKeyCredentialType,
+			Algorithm: alg,
+		})
+	}
 
-// NewRPServer 使用指定的配置初始化並返回 WebAuthn RP 伺服器
-func NewRPServer() {
-	// 建立 WebAuthn 配置
 	wConfig := &webauthn.Config{
-		RPDisplayName: "my RP server",
-		RPID:          "da47-106-107-249-202.ngrok-free.app",
-		RPOrigins:     []string{"https://da47-106-107-249-202.ngrok-free.app"},
+		RPID:                        rpConfig.ID,
+		RPDisplayName:               rpConfig.DisplayName,
+		RPOrigins:                   rpConfig.Origins,
+		RPTopOrigins:                rpConfig.TopOrigins,
+		RPTopOriginVerificationMode: topOriginModes[rpConfig.TopOriginPolicy],
+		AttestationPreference:       protocol.ConveyancePreference(rpConfig.Attestation),
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			UserVerification: protocol.UserVerificationRequirement(rpConfig.UserVerification),
+		},
+		MDS: mds,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login: webauthn.TimeoutConfig{
+				Enforce:    rpConfig.EnforceTimeouts,
+				Timeout:    rpConfig.LoginTimeout,
+				TimeoutUVD: rpConfig.LoginTimeout,
+			},
+			Registration: webauthn.TimeoutConfig{
+				Enforce:    rpConfig.EnforceTimeouts,
+				Timeout:    rpConfig.RegistrationTimeout,
+				TimeoutUVD: rpConfig.RegistrationTimeout,
+			},
+		},
 	}
 
 	webAuthn, err := webauthn.New(wConfig)
 	if err != nil {
-		utils.GetLogger().Fatalf("Failed to initialize WebAuthn RP server: %v", err)
-	} else {
-		utils.GetLogger().Info("WebAuthn RP server initialized successfully")
+		return nil, nil, err
 	}
-
-	WebAuthn = webAuthn
+	return webAuthn, params, nil
 }
\ No newline at end of file
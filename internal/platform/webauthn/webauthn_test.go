@@ -0,0 +1,56 @@
+package webauthn
+
+import (
+	"fido2/config"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+func newTestRPConfig() *config.RPConfig {
+	return &config.RPConfig{
+		ID:                  "example.com",
+		DisplayName:         "Example",
+		Origins:             []string{"https://example.com"},
+		TopOriginPolicy:     "ignore",
+		RegistrationTimeout: time.Minute,
+		LoginTimeout:        2 * time.Minute,
+		Algorithms:          []string{"EdDSA", "ES256"},
+		Attestation:         "direct",
+		UserVerification:    "required",
+	}
+}
+
+func TestNewWebAuthn(t *testing.T) {
+	webAuthn, params, err := NewWebAuthn(newTestRPConfig(), nil)
+	if err != nil {
+		t.Fatalf("不應該有錯: %v", err)
+	}
+
+	if len(params) != 2 || params[0].Algorithm != webauthncose.AlgEdDSA || params[1].Algorithm != webauthncose.AlgES256 {
+		t.Errorf("演算法順序錯誤，got=%v", params)
+	}
+	if webAuthn.Config.RPID != "example.com" {
+		t.Errorf("RPID 錯誤，got=%q", webAuthn.Config.RPID)
+	}
+	if webAuthn.Config.AttestationPreference != protocol.PreferDirectAttestation {
+		t.Errorf("Attestation 偏好錯誤，got=%q", webAuthn.Config.AttestationPreference)
+	}
+	if webAuthn.Config.AuthenticatorSelection.UserVerification != protocol.VerificationRequired {
+		t.Errorf("UserVerification 錯誤，got=%q", webAuthn.Config.AuthenticatorSelection.UserVerification)
+	}
+	if webAuthn.Config.Timeouts.Login.Timeout != 2*time.Minute {
+		t.Errorf("登入逾時錯誤，got=%v", webAuthn.Config.Timeouts.Login.Timeout)
+	}
+}
+
+func TestNewWebAuthn_UnsupportedAlgorithm(t *testing.T) {
+	rpConfig := newTestRPConfig()
+	rpConfig.Algorithms = []string{"HS256"}
+
+	if _, _, err := NewWebAuthn(rpConfig, nil); err == nil {
+		t.Errorf("不支援的演算法應回傳錯誤")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+	"fido2/internal/entity"
+	"fido2/internal/platform/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuthorizationRequestRepository 定義了 OIDC 授權請求資料操作的介面
+type AuthorizationRequestRepository interface {
+	CreateAuthorizationRequest(request *entity.AuthorizationRequest) error
+	GetAuthorizationRequestByID(tenantID, id string) (*entity.AuthorizationRequest, error)
+	GetAuthorizationRequestByCodeHash(codeHash string) (*entity.AuthorizationRequest, error)
+	CompleteAuthorizationRequest(id string, result *entity.AuthorizationRequest) (bool, error)
+	RedeemAuthorizationCode(id, familyID string) (bool, error)
+}
+
+// authorizationRequestRepositoryImpl 實作 AuthorizationRequestRepository 介面
+type authorizationRequestRepositoryImpl struct{}
+
+// NewAuthorizationRequestRepository 建立 AuthorizationRequestRepository 的新實例
+func NewAuthorizationRequestRepository() AuthorizationRequestRepository {
+	return &authorizationRequestRepositoryImpl{}
+}
+
+// CreateAuthorizationRequest 在資料庫中建立新授權請求
+func (r *authorizationRequestRepositoryImpl) CreateAuthorizationRequest(request *entity.AuthorizationRequest) error {
+	return db.GetDB().Create(request).Error
+}
+
+// GetAuthorizationRequestByID 透過租戶與 ID 取得授權請求
+func (r *authorizationRequestRepositoryImpl) GetAuthorizationRequestByID(tenantID, id string) (*entity.AuthorizationRequest, error) {
+	var request entity.AuthorizationRequest
+	if err := db.GetDB().Where("tenant_id = ? AND id = ?", tenantID, id).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetAuthorizationRequestByCodeHash 透過 Authorization Code 雜湊值取得授權請求
+func (r *authorizationRequestRepositoryImpl) GetAuthorizationRequestByCodeHash(codeHash string) (*entity.AuthorizationRequest, error) {
+	var request entity.AuthorizationRequest
+	if err := db.GetDB().Where("code_hash = ?", codeHash).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+// CompleteAuthorizationRequest 綁定驗證結果與 Authorization Code，回傳是否由此次呼叫完成
+// 以條件更新確保同一個授權請求只能產生一次 Authorization Code
+func (r *authorizationRequestRepositoryImpl) CompleteAuthorizationRequest(id string, result *entity.AuthorizationRequest) (bool, error) {
+	tx := db.GetDB().Model(&entity.AuthorizationRequest{}).
+		Where("id = ? AND code_hash = ''", id).
+		Updates(result)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected == 1, nil
+}
+
+// RedeemAuthorizationCode 將 Authorization Code 標記為已兌換並記錄換發的 Refresh Token 家族，回傳是否由此次呼叫兌換
+func (r *authorizationRequestRepositoryImpl) RedeemAuthorizationCode(id, familyID string) (bool, error) {
+	result := db.GetDB().Model(&entity.AuthorizationRequest{}).
+		Where("id = ? AND redeemed_at IS NULL", id).
+		Updates(map[string]interface{}{"redeemed_at": time.Now(), "refresh_token_family_id": familyID})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
\ No newline at end of file
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"errors"
+	"fido2/internal/entity"
+	"fido2/internal/platform/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CredentialRepository 定義了 Credential 資料操作的介面
+type CredentialRepository interface {
+	CreateCredential(credential *entity.Credential) error
+	GetCredentialByID(id string) (*entity.Credential, error)
+	GetCredentialsByUserID(userID string) ([]*entity.Credential, error)
+	GetFlaggedCredentials(tenantID string) ([]*entity.Credential, error)
+	UpdateCredential(credential *entity.Credential, updateData interface{}) error
+	RenameCredential(userID, id, nickname string) (bool, error)
+	DeleteCredential(id string) error
+	DeleteUserCredential(userID, id string, allowLast bool) error
+}
+
+var (
+	// ErrCredentialNotFound Credential 不存在或不屬於該使用者
+	ErrCredentialNotFound = errors.New("credential not found")
+
+	// ErrLastCredential 使用者沒有其他登入方式，不可刪除最後一個 Credential
+	ErrLastCredential = errors.New("cannot delete the last credential without a recovery method")
+
+	// ErrCredentialExists 已有相同 Credential ID 的 Credential
+	ErrCredentialExists = errors.New("credential already exists")
+)
+
+// credentialRepositoryImpl 實作 CredentialRepository 介面
+type credentialRepositoryImpl struct{}
+
+// NewCredentialRepository 建立 CredentialRepository 的新實例
+func NewCredentialRepository() CredentialRepository {
+	return &credentialRepositoryImpl{}
+}
+
+// CreateCredential 在資料庫中建立新 Credential
+// Credential ID 重複時回傳 ErrCredentialExists
+func (r *credentialRepositoryImpl) CreateCredential(credential *entity.Credential) error {
+	if err := db.GetDB().Create(credential).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrCredentialExists
+		}
+		return err
+	}
+	return nil
+}
+
+// GetCredentialByID 透過 Credential ID (base64url) 取得 Credential
+func (r *credentialRepositoryImpl) GetCredentialByID(id string) (*entity.Credential, error) {
+	var credential entity.Credential
+	if err := db.GetDB().Where("id = ?", id).First(&credential).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// GetCredentialsByUserID 取得指定使用者的所有 Credential
+func (r *credentialRepositoryImpl) GetCredentialsByUserID(userID string) ([]*entity.Credential, error) {
+	var credentials []*entity.Credential
+	if err := db.GetDB().Where("user_id = ?", userID).Order("created_at").Find(&credentials).Error; err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+// GetFlaggedCredentials 取得租戶內所有出現複製警告 (CloneWarning) 的 Credential
+func (r *credentialRepositoryImpl) GetFlaggedCredentials(tenantID string) ([]*entity.Credential, error) {
+	var credentials []*entity.Credential
+	if err := db.GetDB().Where("tenant_id = ? AND clone_warning = ?", tenantID, true).Order("last_used_at DESC").Find(&credentials).Error; err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+// UpdateCredential 更新 Credential 資料
+func (r *credentialRepositoryImpl) UpdateCredential(credential *entity.Credential, updateData interface{}) error {
+	return db.GetDB().Model(credential).Updates(updateData).Error
+}
+
+// DeleteCredential 刪除 Credential
+func (r *credentialRepositoryImpl) DeleteCredential(id string) error {
+	return db.GetDB().Delete(&entity.Credential{}, "id = ?", id).Error
+}
+
+// RenameCredential 更新使用者自己的 Credential 名稱，回傳 Credential 是否存在
+func (r *credentialRepositoryImpl) RenameCredential(userID, id, nickname string) (bool, error) {
+	result := db.GetDB().Model(&entity.Credential{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("nickname", nickname)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteUserCredential 刪除使用者自己的 Credential
+// allowLast 為 false 時若為最後一個 Credential 則回傳 ErrLastCredential
+func (r *credentialRepositoryImpl) DeleteUserCredential(userID, id string, allowLast bool) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		// 鎖定使用者資料列，避免同時刪除兩個 Credential 後沒有任何登入方式
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&entity.User{}, "id = ?", userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCredentialNotFound
+			}
+			return err
+		}
+
+		var total, owned int64
+		if err := tx.Model(&entity.Credential{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.Credential{}).Where("id = ? AND user_id = ?", id, userID).Count(&owned).Error; err != nil {
+			return err
+		}
+		if owned == 0 {
+			return ErrCredentialNotFound
+		}
+		if total <= 1 && !allowLast {
+			return ErrLastCredential
+		}
+
+		return tx.Delete(&entity.Credential{}, "id = ? AND user_id = ?", id, userID).Error
+	})
+}
\ No newline at end of file
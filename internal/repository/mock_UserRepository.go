@@ -5,6 +5,8 @@
 package repository
 
 import (
+	"time"
+
 	"fido2/internal/entity"
 
 	mock "github.com/stretchr/testify/mock"
@@ -37,6 +39,197 @@ func (_m *MockUserRepository) EXPECT() *MockUserRepository_Expecter {
 	return &MockUserRepository_Expecter{mock: &_m.Mock}
 }
 
+// ClearExpiredChallenges provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) ClearExpiredChallenges(now time.Time) (int64, error) {
+	ret := _mock.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClearExpiredChallenges")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return returnFunc(now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = returnFunc(now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = returnFunc(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_ClearExpiredChallenges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClearExpiredChallenges'
+type MockUserRepository_ClearExpiredChallenges_Call struct {
+	*mock.Call
+}
+
+// ClearExpiredChallenges is a helper method to define mock.On call
+//   - now time.Time
+func (_e *MockUserRepository_Expecter) ClearExpiredChallenges(now interface{}) *MockUserRepository_ClearExpiredChallenges_Call {
+	return &MockUserRepository_ClearExpiredChallenges_Call{Call: _e.mock.On("ClearExpiredChallenges", now)}
+}
+
+func (_c *MockUserRepository_ClearExpiredChallenges_Call) Run(run func(now time.Time)) *MockUserRepository_ClearExpiredChallenges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 time.Time
+		if args[0] != nil {
+			arg0 = args[0].(time.Time)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ClearExpiredChallenges_Call) Return(n int64, err error) *MockUserRepository_ClearExpiredChallenges_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockUserRepository_ClearExpiredChallenges_Call) RunAndReturn(run func(now time.Time) (int64, error)) *MockUserRepository_ClearExpiredChallenges_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompleteRegistration provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) CompleteRegistration(user *entity.User, credential *entity.Credential) error {
+	ret := _mock.Called(user, credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteRegistration")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*entity.User, *entity.Credential) error); ok {
+		r0 = returnFunc(user, credential)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_CompleteRegistration_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteRegistration'
+type MockUserRepository_CompleteRegistration_Call struct {
+	*mock.Call
+}
+
+// CompleteRegistration is a helper method to define mock.On call
+//   - user *entity.User
+//   - credential *entity.Credential
+func (_e *MockUserRepository_Expecter) CompleteRegistration(user interface{}, credential interface{}) *MockUserRepository_CompleteRegistration_Call {
+	return &MockUserRepository_CompleteRegistration_Call{Call: _e.mock.On("CompleteRegistration", user, credential)}
+}
+
+func (_c *MockUserRepository_CompleteRegistration_Call) Run(run func(user *entity.User, credential *entity.Credential)) *MockUserRepository_CompleteRegistration_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *entity.User
+		if args[0] != nil {
+			arg0 = args[0].(*entity.User)
+		}
+		var arg1 *entity.Credential
+		if args[1] != nil {
+			arg1 = args[1].(*entity.Credential)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_CompleteRegistration_Call) Return(err error) *MockUserRepository_CompleteRegistration_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_CompleteRegistration_Call) RunAndReturn(run func(user *entity.User, credential *entity.Credential) error) *MockUserRepository_CompleteRegistration_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConsumeChallenge provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) ConsumeChallenge(tenantID string, challenge string, now time.Time) (*entity.User, error) {
+	ret := _mock.Called(tenantID, challenge, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeChallenge")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, time.Time) (*entity.User, error)); ok {
+		return returnFunc(tenantID, challenge, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, time.Time) *entity.User); ok {
+		r0 = returnFunc(tenantID, challenge, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, time.Time) error); ok {
+		r1 = returnFunc(tenantID, challenge, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_ConsumeChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConsumeChallenge'
+type MockUserRepository_ConsumeChallenge_Call struct {
+	*mock.Call
+}
+
+// ConsumeChallenge is a helper method to define mock.On call
+//   - tenantID string
+//   - challenge string
+//   - now time.Time
+func (_e *MockUserRepository_Expecter) ConsumeChallenge(tenantID interface{}, challenge interface{}, now interface{}) *MockUserRepository_ConsumeChallenge_Call {
+	return &MockUserRepository_ConsumeChallenge_Call{Call: _e.mock.On("ConsumeChallenge", tenantID, challenge, now)}
+}
+
+func (_c *MockUserRepository_ConsumeChallenge_Call) Run(run func(tenantID string, challenge string, now time.Time)) *MockUserRepository_ConsumeChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ConsumeChallenge_Call) Return(user *entity.User, err error) *MockUserRepository_ConsumeChallenge_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserRepository_ConsumeChallenge_Call) RunAndReturn(run func(tenantID string, challenge string, now time.Time) (*entity.User, error)) *MockUserRepository_ConsumeChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateUser provides a mock function for the type MockUserRepository
 func (_mock *MockUserRepository) CreateUser(user *entity.User) error {
 	ret := _mock.Called(user)
@@ -88,6 +281,66 @@ func (_c *MockUserRepository_CreateUser_Call) RunAndReturn(run func(user *entity
 	return _c
 }
 
+// DeleteAbandonedRegistrations provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) DeleteAbandonedRegistrations(reservedBefore time.Time) (int64, error) {
+	ret := _mock.Called(reservedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAbandonedRegistrations")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return returnFunc(reservedBefore)
+	}
+	if returnFunc, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = returnFunc(reservedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = returnFunc(reservedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_DeleteAbandonedRegistrations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAbandonedRegistrations'
+type MockUserRepository_DeleteAbandonedRegistrations_Call struct {
+	*mock.Call
+}
+
+// DeleteAbandonedRegistrations is a helper method to define mock.On call
+//   - reservedBefore time.Time
+func (_e *MockUserRepository_Expecter) DeleteAbandonedRegistrations(reservedBefore interface{}) *MockUserRepository_DeleteAbandonedRegistrations_Call {
+	return &MockUserRepository_DeleteAbandonedRegistrations_Call{Call: _e.mock.On("DeleteAbandonedRegistrations", reservedBefore)}
+}
+
+func (_c *MockUserRepository_DeleteAbandonedRegistrations_Call) Run(run func(reservedBefore time.Time)) *MockUserRepository_DeleteAbandonedRegistrations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 time.Time
+		if args[0] != nil {
+			arg0 = args[0].(time.Time)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_DeleteAbandonedRegistrations_Call) Return(n int64, err error) *MockUserRepository_DeleteAbandonedRegistrations_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockUserRepository_DeleteAbandonedRegistrations_Call) RunAndReturn(run func(reservedBefore time.Time) (int64, error)) *MockUserRepository_DeleteAbandonedRegistrations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteUser provides a mock function for the type MockUserRepository
 func (_mock *MockUserRepository) DeleteUser(id string) error {
 	ret := _mock.Called(id)
@@ -139,46 +392,46 @@ func (_c *MockUserRepository_DeleteUser_Call) RunAndReturn(run func(id string) e
 	return _c
 }
 
-// GetUserByChallenge provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) GetUserByChallenge(challenge string) (*entity.User, error) {
-	ret := _mock.Called(challenge)
+// GetFlaggedUsers provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) GetFlaggedUsers(tenantID string) ([]*entity.User, error) {
+	ret := _mock.Called(tenantID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserByChallenge")
+		panic("no return value specified for GetFlaggedUsers")
 	}
 
-	var r0 *entity.User
+	var r0 []*entity.User
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (*entity.User, error)); ok {
-		return returnFunc(challenge)
+	if returnFunc, ok := ret.Get(0).(func(string) ([]*entity.User, error)); ok {
+		return returnFunc(tenantID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) *entity.User); ok {
-		r0 = returnFunc(challenge)
+	if returnFunc, ok := ret.Get(0).(func(string) []*entity.User); ok {
+		r0 = returnFunc(tenantID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.User)
+			r0 = ret.Get(0).([]*entity.User)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
-		r1 = returnFunc(challenge)
+		r1 = returnFunc(tenantID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockUserRepository_GetUserByChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByChallenge'
-type MockUserRepository_GetUserByChallenge_Call struct {
+// MockUserRepository_GetFlaggedUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFlaggedUsers'
+type MockUserRepository_GetFlaggedUsers_Call struct {
 	*mock.Call
 }
 
-// GetUserByChallenge is a helper method to define mock.On call
-//   - challenge string
-func (_e *MockUserRepository_Expecter) GetUserByChallenge(challenge interface{}) *MockUserRepository_GetUserByChallenge_Call {
-	return &MockUserRepository_GetUserByChallenge_Call{Call: _e.mock.On("GetUserByChallenge", challenge)}
+// GetFlaggedUsers is a helper method to define mock.On call
+//   - tenantID string
+func (_e *MockUserRepository_Expecter) GetFlaggedUsers(tenantID interface{}) *MockUserRepository_GetFlaggedUsers_Call {
+	return &MockUserRepository_GetFlaggedUsers_Call{Call: _e.mock.On("GetFlaggedUsers", tenantID)}
 }
 
-func (_c *MockUserRepository_GetUserByChallenge_Call) Run(run func(challenge string)) *MockUserRepository_GetUserByChallenge_Call {
+func (_c *MockUserRepository_GetFlaggedUsers_Call) Run(run func(tenantID string)) *MockUserRepository_GetFlaggedUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
@@ -191,12 +444,12 @@ func (_c *MockUserRepository_GetUserByChallenge_Call) Run(run func(challenge str
 	return _c
 }
 
-func (_c *MockUserRepository_GetUserByChallenge_Call) Return(user *entity.User, err error) *MockUserRepository_GetUserByChallenge_Call {
-	_c.Call.Return(user, err)
+func (_c *MockUserRepository_GetFlaggedUsers_Call) Return(users []*entity.User, err error) *MockUserRepository_GetFlaggedUsers_Call {
+	_c.Call.Return(users, err)
 	return _c
 }
 
-func (_c *MockUserRepository_GetUserByChallenge_Call) RunAndReturn(run func(challenge string) (*entity.User, error)) *MockUserRepository_GetUserByChallenge_Call {
+func (_c *MockUserRepository_GetFlaggedUsers_Call) RunAndReturn(run func(tenantID string) ([]*entity.User, error)) *MockUserRepository_GetFlaggedUsers_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -263,9 +516,77 @@ func (_c *MockUserRepository_GetUserByID_Call) RunAndReturn(run func(id string)
 	return _c
 }
 
+// GetUserByUserHandle provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) GetUserByUserHandle(tenantID string, userHandle []byte) (*entity.User, error) {
+	ret := _mock.Called(tenantID, userHandle)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByUserHandle")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, []byte) (*entity.User, error)); ok {
+		return returnFunc(tenantID, userHandle)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, []byte) *entity.User); ok {
+		r0 = returnFunc(tenantID, userHandle)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, []byte) error); ok {
+		r1 = returnFunc(tenantID, userHandle)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_GetUserByUserHandle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByUserHandle'
+type MockUserRepository_GetUserByUserHandle_Call struct {
+	*mock.Call
+}
+
+// GetUserByUserHandle is a helper method to define mock.On call
+//   - tenantID string
+//   - userHandle []byte
+func (_e *MockUserRepository_Expecter) GetUserByUserHandle(tenantID interface{}, userHandle interface{}) *MockUserRepository_GetUserByUserHandle_Call {
+	return &MockUserRepository_GetUserByUserHandle_Call{Call: _e.mock.On("GetUserByUserHandle", tenantID, userHandle)}
+}
+
+func (_c *MockUserRepository_GetUserByUserHandle_Call) Run(run func(tenantID string, userHandle []byte)) *MockUserRepository_GetUserByUserHandle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []byte
+		if args[1] != nil {
+			arg1 = args[1].([]byte)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserByUserHandle_Call) Return(user *entity.User, err error) *MockUserRepository_GetUserByUserHandle_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserByUserHandle_Call) RunAndReturn(run func(tenantID string, userHandle []byte) (*entity.User, error)) *MockUserRepository_GetUserByUserHandle_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetUserByUsername provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) GetUserByUsername(username string) (*entity.User, error) {
-	ret := _mock.Called(username)
+func (_mock *MockUserRepository) GetUserByUsername(tenantID string, username string) (*entity.User, error) {
+	ret := _mock.Called(tenantID, username)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByUsername")
@@ -273,18 +594,18 @@ func (_mock *MockUserRepository) GetUserByUsername(username string) (*entity.Use
 
 	var r0 *entity.User
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (*entity.User, error)); ok {
-		return returnFunc(username)
+	if returnFunc, ok := ret.Get(0).(func(string, string) (*entity.User, error)); ok {
+		return returnFunc(tenantID, username)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) *entity.User); ok {
-		r0 = returnFunc(username)
+	if returnFunc, ok := ret.Get(0).(func(string, string) *entity.User); ok {
+		r0 = returnFunc(tenantID, username)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.User)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
-		r1 = returnFunc(username)
+	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = returnFunc(tenantID, username)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -297,19 +618,25 @@ type MockUserRepository_GetUserByUsername_Call struct {
 }
 
 // GetUserByUsername is a helper method to define mock.On call
+//   - tenantID string
 //   - username string
-func (_e *MockUserRepository_Expecter) GetUserByUsername(username interface{}) *MockUserRepository_GetUserByUsername_Call {
-	return &MockUserRepository_GetUserByUsername_Call{Call: _e.mock.On("GetUserByUsername", username)}
+func (_e *MockUserRepository_Expecter) GetUserByUsername(tenantID interface{}, username interface{}) *MockUserRepository_GetUserByUsername_Call {
+	return &MockUserRepository_GetUserByUsername_Call{Call: _e.mock.On("GetUserByUsername", tenantID, username)}
 }
 
-func (_c *MockUserRepository_GetUserByUsername_Call) Run(run func(username string)) *MockUserRepository_GetUserByUsername_Call {
+func (_c *MockUserRepository_GetUserByUsername_Call) Run(run func(tenantID string, username string)) *MockUserRepository_GetUserByUsername_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
 			arg0 = args[0].(string)
 		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -320,7 +647,7 @@ func (_c *MockUserRepository_GetUserByUsername_Call) Return(user *entity.User, e
 	return _c
 }
 
-func (_c *MockUserRepository_GetUserByUsername_Call) RunAndReturn(run func(username string) (*entity.User, error)) *MockUserRepository_GetUserByUsername_Call {
+func (_c *MockUserRepository_GetUserByUsername_Call) RunAndReturn(run func(tenantID string, username string) (*entity.User, error)) *MockUserRepository_GetUserByUsername_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -380,17 +707,83 @@ func (_c *MockUserRepository_GetUsers_Call) RunAndReturn(run func() ([]*entity.U
 	return _c
 }
 
+// ReclaimExpiredReservation provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) ReclaimExpiredReservation(user *entity.User, now time.Time) (bool, error) {
+	ret := _mock.Called(user, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReclaimExpiredReservation")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*entity.User, time.Time) (bool, error)); ok {
+		return returnFunc(user, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*entity.User, time.Time) bool); ok {
+		r0 = returnFunc(user, now)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(*entity.User, time.Time) error); ok {
+		r1 = returnFunc(user, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_ReclaimExpiredReservation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReclaimExpiredReservation'
+type MockUserRepository_ReclaimExpiredReservation_Call struct {
+	*mock.Call
+}
+
+// ReclaimExpiredReservation is a helper method to define mock.On call
+//   - user *entity.User
+//   - now time.Time
+func (_e *MockUserRepository_Expecter) ReclaimExpiredReservation(user interface{}, now interface{}) *MockUserRepository_ReclaimExpiredReservation_Call {
+	return &MockUserRepository_ReclaimExpiredReservation_Call{Call: _e.mock.On("ReclaimExpiredReservation", user, now)}
+}
+
+func (_c *MockUserRepository_ReclaimExpiredReservation_Call) Run(run func(user *entity.User, now time.Time)) *MockUserRepository_ReclaimExpiredReservation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *entity.User
+		if args[0] != nil {
+			arg0 = args[0].(*entity.User)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ReclaimExpiredReservation_Call) Return(b bool, err error) *MockUserRepository_ReclaimExpiredReservation_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockUserRepository_ReclaimExpiredReservation_Call) RunAndReturn(run func(user *entity.User, now time.Time) (bool, error)) *MockUserRepository_ReclaimExpiredReservation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateUser provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) UpdateUser(user *entity.User) error {
-	ret := _mock.Called(user)
+func (_mock *MockUserRepository) UpdateUser(user *entity.User, updateData interface{}) error {
+	ret := _mock.Called(user, updateData)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUser")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*entity.User) error); ok {
-		r0 = returnFunc(user)
+	if returnFunc, ok := ret.Get(0).(func(*entity.User, interface{}) error); ok {
+		r0 = returnFunc(user, updateData)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -404,18 +797,24 @@ type MockUserRepository_UpdateUser_Call struct {
 
 // UpdateUser is a helper method to define mock.On call
 //   - user *entity.User
-func (_e *MockUserRepository_Expecter) UpdateUser(user interface{}) *MockUserRepository_UpdateUser_Call {
-	return &MockUserRepository_UpdateUser_Call{Call: _e.mock.On("UpdateUser", user)}
+//   - updateData interface{}
+func (_e *MockUserRepository_Expecter) UpdateUser(user interface{}, updateData interface{}) *MockUserRepository_UpdateUser_Call {
+	return &MockUserRepository_UpdateUser_Call{Call: _e.mock.On("UpdateUser", user, updateData)}
 }
 
-func (_c *MockUserRepository_UpdateUser_Call) Run(run func(user *entity.User)) *MockUserRepository_UpdateUser_Call {
+func (_c *MockUserRepository_UpdateUser_Call) Run(run func(user *entity.User, updateData interface{})) *MockUserRepository_UpdateUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 *entity.User
 		if args[0] != nil {
 			arg0 = args[0].(*entity.User)
 		}
+		var arg1 interface{}
+		if args[1] != nil {
+			arg1 = args[1].(interface{})
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -426,7 +825,7 @@ func (_c *MockUserRepository_UpdateUser_Call) Return(err error) *MockUserReposit
 	return _c
 }
 
-func (_c *MockUserRepository_UpdateUser_Call) RunAndReturn(run func(user *entity.User) error) *MockUserRepository_UpdateUser_Call {
+func (_c *MockUserRepository_UpdateUser_Call) RunAndReturn(run func(user *entity.User, updateData interface{}) error) *MockUserRepository_UpdateUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
\ No newline at end of file
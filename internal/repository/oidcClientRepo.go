@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"errors"
+	"fido2/internal/entity"
+	"fido2/internal/platform/db"
+
+	"gorm.io/gorm"
+)
+
+// OIDCClientRepository 定義了 OIDC Client 資料操作的介面
+type OIDCClientRepository interface {
+	CreateClient(client *entity.OIDCClient) error
+	GetClientByID(tenantID, clientID string) (*entity.OIDCClient, error)
+}
+
+// oidcClientRepositoryImpl 實作 OIDCClientRepository 介面
+type oidcClientRepositoryImpl struct{}
+
+// NewOIDCClientRepository 建立 OIDCClientRepository 的新實例
+func NewOIDCClientRepository() OIDCClientRepository {
+	return &oidcClientRepositoryImpl{}
+}
+
+// CreateClient 在資料庫中註冊新 Client
+func (r *oidcClientRepositoryImpl) CreateClient(client *entity.OIDCClient) error {
+	return db.GetDB().Create(client).Error
+}
+
+// GetClientByID 透過租戶與 client_id 取得 Client
+func (r *oidcClientRepositoryImpl) GetClientByID(tenantID, clientID string) (*entity.OIDCClient, error) {
+	var client entity.OIDCClient
+	if err := db.GetDB().Where("tenant_id = ? AND id = ?", tenantID, clientID).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &client, nil
+}
\ No newline at end of file
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+	"fido2/internal/entity"
+	"fido2/internal/platform/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository 定義了 Refresh Token 資料操作的介面
+type RefreshTokenRepository interface {
+	CreateRefreshToken(refreshToken *entity.RefreshToken) error
+	GetRefreshTokenByHash(tokenHash string) (*entity.RefreshToken, error)
+	RevokeRefreshToken(id, replacedBy string) (bool, error)
+	RevokeRefreshTokenFamily(familyID string) error
+	RevokeRefreshTokensByCredential(credentialID string) error
+}
+
+// refreshTokenRepositoryImpl 實作 RefreshTokenRepository 介面
+type refreshTokenRepositoryImpl struct{}
+
+// NewRefreshTokenRepository 建立 RefreshTokenRepository 的新實例
+func NewRefreshTokenRepository() RefreshTokenRepository {
+	return &refreshTokenRepositoryImpl{}
+}
+
+// CreateRefreshToken 在資料庫中建立新 Refresh Token
+func (r *refreshTokenRepositoryImpl) CreateRefreshToken(refreshToken *entity.RefreshToken) error {
+	return db.GetDB().Create(refreshToken).Error
+}
+
+// GetRefreshTokenByHash 透過 Refresh Token 雜湊值取得記錄
+func (r *refreshTokenRepositoryImpl) GetRefreshTokenByHash(tokenHash string) (*entity.RefreshToken, error) {
+	var refreshToken entity.RefreshToken
+	if err := db.GetDB().Where("token_hash = ?", tokenHash).First(&refreshToken).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &refreshToken, nil
+}
+
+// RevokeRefreshToken 撤銷尚未撤銷的 Refresh Token，回傳是否由此次呼叫撤銷
+// 以條件更新確保同一個 Refresh Token 只能被輪替一次
+func (r *refreshTokenRepositoryImpl) RevokeRefreshToken(id, replacedBy string) (bool, error) {
+	result := db.GetDB().Model(&entity.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "replaced_by": replacedBy})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeRefreshTokenFamily 撤銷同一次登入輪替出的所有 Refresh Token
+func (r *refreshTokenRepositoryImpl) RevokeRefreshTokenFamily(familyID string) error {
+	return db.GetDB().Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeRefreshTokensByCredential 撤銷以指定 Credential 登入所取得的所有 Refresh Token
+func (r *refreshTokenRepositoryImpl) RevokeRefreshTokensByCredential(credentialID string) error {
+	return db.GetDB().Model(&entity.RefreshToken{}).
+		Where("credential_id = ? AND revoked_at IS NULL", credentialID).
+		Update("revoked_at", time.Now()).Error
+}
\ No newline at end of file
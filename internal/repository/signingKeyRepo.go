@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+	"fido2/internal/entity"
+	"fido2/internal/platform/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SigningKeyRepository 定義了 JWT 簽章金鑰資料操作的介面
+type SigningKeyRepository interface {
+	GetPublishedSigningKeys(now time.Time) ([]*entity.SigningKey, error)
+	RotateSigningKey(key *entity.SigningKey, rotateBefore, expiresAt time.Time) (bool, error)
+}
+
+// signingKeyRotationLock 金鑰輪替使用的 Postgres advisory lock 鍵值，讓多個節點不會同時輪替
+const signingKeyRotationLock = 0x66696432
+
+// signingKeyRepositoryImpl 實作 SigningKeyRepository 介面
+type signingKeyRepositoryImpl struct{}
+
+// NewSigningKeyRepository 建立 SigningKeyRepository 的新實例
+func NewSigningKeyRepository() SigningKeyRepository {
+	return &signingKeyRepositoryImpl{}
+}
+
+// GetPublishedSigningKeys 取得仍需公開的金鑰 (未退役或尚未過期)，由新到舊排序
+func (r *signingKeyRepositoryImpl) GetPublishedSigningKeys(now time.Time) ([]*entity.SigningKey, error) {
+	var keys []*entity.SigningKey
+	if err := db.GetDB().
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("created_at DESC").
+		Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// RotateSigningKey 建立新金鑰並將其他仍在使用的金鑰退役，設定停止公開的時間，回傳是否由此次呼叫輪替
+// 在同一個資料庫交易中持有 advisory lock，目前使用中的金鑰建立於 rotateBefore 之後 (已由其他節點輪替) 時不建立新金鑰
+func (r *signingKeyRepositoryImpl) RotateSigningKey(key *entity.SigningKey, rotateBefore, expiresAt time.Time) (bool, error) {
+	rotated := false
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", signingKeyRotationLock).Error; err != nil {
+			return err
+		}
+
+		var active entity.SigningKey
+		err := tx.Where("retired_at IS NULL").Order("created_at DESC").First(&active).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil && active.CreatedAt.After(rotateBefore) {
+			return nil
+		}
+
+		if err := tx.Create(key).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.SigningKey{}).
+			Where("id <> ? AND retired_at IS NULL", key.ID).
+			Updates(map[string]interface{}{"retired_at": key.CreatedAt, "expires_at": expiresAt}).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return rotated, nil
+}
\ No newline at end of file
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"errors"
+	"fido2/internal/entity"
+	"fido2/internal/platform/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TransactionRepository 定義了交易確認資料操作的介面
+type TransactionRepository interface {
+	CreateTransaction(transaction *entity.Transaction) error
+	GetTransactionByID(tenantID, userID, id string) (*entity.Transaction, error)
+	GetTransactionsByUserID(tenantID, userID string, status entity.TransactionStatus) ([]*entity.Transaction, error)
+	ExpireTransactions(userID string, now time.Time) (int64, error)
+	RejectTransaction(tenantID, userID, id string, now time.Time) (bool, error)
+	ConfirmTransaction(id string, record *entity.TransactionSignature, now time.Time) (bool, error)
+	GetTransactionSignature(transactionID string) (*entity.TransactionSignature, error)
+}
+
+var (
+	// ErrTransactionNotFound 交易不存在或不屬於該使用者
+	ErrTransactionNotFound = errors.New("transaction not found")
+
+	// ErrTransactionNotPending 交易已確認、已拒絕或已過期
+	ErrTransactionNotPending = errors.New("transaction is no longer pending")
+)
+
+// transactionRepositoryImpl 實作 TransactionRepository 介面
+type transactionRepositoryImpl struct{}
+
+// NewTransactionRepository 建立 TransactionRepository 的新實例
+func NewTransactionRepository() TransactionRepository {
+	return &transactionRepositoryImpl{}
+}
+
+// CreateTransaction 在資料庫中建立新交易
+func (r *transactionRepositoryImpl) CreateTransaction(transaction *entity.Transaction) error {
+	return db.GetDB().Create(transaction).Error
+}
+
+// GetTransactionByID 透過租戶、使用者與 ID 取得交易
+func (r *transactionRepositoryImpl) GetTransactionByID(tenantID, userID, id string) (*entity.Transaction, error) {
+	var transaction entity.Transaction
+	if err := db.GetDB().Where("tenant_id = ? AND user_id = ? AND id = ?", tenantID, userID, id).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// GetTransactionsByUserID 取得使用者的交易，依建立時間由新到舊排序，status 為空時不篩選狀態
+func (r *transactionRepositoryImpl) GetTransactionsByUserID(tenantID, userID string, status entity.TransactionStatus) ([]*entity.Transaction, error) {
+	query := db.GetDB().Where("tenant_id = ? AND user_id = ?", tenantID, userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var transactions []*entity.Transaction
+	if err := query.Order("created_at DESC").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// ExpireTransactions 將使用者已超過確認期限的 pending 交易標記為 expired，回傳更新筆數
+func (r *transactionRepositoryImpl) ExpireTransactions(userID string, now time.Time) (int64, error) {
+	result := db.GetDB().Model(&entity.Transaction{}).
+		Where("user_id = ? AND status = ? AND expires_at <= ?", userID, entity.TransactionPending, now).
+		Update("status", entity.TransactionExpired)
+	return result.RowsAffected, result.Error
+}
+
+// RejectTransaction 將使用者尚未過期的 pending 交易標記為 rejected，回傳是否由此次呼叫拒絕
+func (r *transactionRepositoryImpl) RejectTransaction(tenantID, userID, id string, now time.Time) (bool, error) {
+	result := db.GetDB().Model(&entity.Transaction{}).
+		Where("tenant_id = ? AND user_id = ? AND id = ? AND status = ? AND expires_at > ?", tenantID, userID, id, entity.TransactionPending, now).
+		Update("status", entity.TransactionRejected)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ConfirmTransaction 將尚未過期的 pending 交易標記為 confirmed 並寫入不可否認性紀錄，回傳是否由此次呼叫確認
+// 狀態更新與紀錄寫入在同一個資料庫交易中完成，確保每筆交易只會被確認一次
+func (r *transactionRepositoryImpl) ConfirmTransaction(id string, record *entity.TransactionSignature, now time.Time) (bool, error) {
+	confirmed := false
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Transaction{}).
+			Where("id = ? AND status = ? AND expires_at > ?", id, entity.TransactionPending, now).
+			Updates(map[string]interface{}{"status": entity.TransactionConfirmed, "confirmed_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		confirmed = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return confirmed, nil
+}
+
+// GetTransactionSignature 取得交易的不可否認性紀錄，尚未確認時回傳 nil
+func (r *transactionRepositoryImpl) GetTransactionSignature(transactionID string) (*entity.TransactionSignature, error) {
+	var record entity.TransactionSignature
+	if err := db.GetDB().Where("transaction_id = ?", transactionID).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
\ No newline at end of file
@@ -1,21 +1,43 @@
 package repository
 
 import (
+	"errors"
 	"fido2/internal/entity"
 	"fido2/internal/platform/db"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository 定義了用戶資料操作的介面
 type UserRepository interface {
 	CreateUser(user *entity.User) error
 	GetUserByID(id string) (*entity.User, error)
-	GetUserByUsername(username string) (*entity.User, error)
-	GetUserByChallenge(challenge string) (*entity.User, error)
+	GetUserByUsername(tenantID, username string) (*entity.User, error)
+	GetUserByUserHandle(tenantID string, userHandle []byte) (*entity.User, error)
+	ConsumeChallenge(tenantID, challenge string, now time.Time) (*entity.User, error)
 	GetUsers() ([]*entity.User, error)
+	GetFlaggedUsers(tenantID string) ([]*entity.User, error)
 	UpdateUser(user *entity.User, updateData interface{}) error
+	ReclaimExpiredReservation(user *entity.User, now time.Time) (bool, error)
+	CompleteRegistration(user *entity.User, credential *entity.Credential) error
+	DeleteAbandonedRegistrations(reservedBefore time.Time) (int64, error)
+	ClearExpiredChallenges(now time.Time) (int64, error)
 	DeleteUser(id string) error
 }
 
+var (
+	// ErrUsernameTaken 同一租戶內已有相同的使用者名稱 (已註冊或保留中)
+	ErrUsernameTaken = errors.New("username is already taken")
+
+	// ErrChallengeExpired Challenge 不存在、已過期或已被使用過
+	ErrChallengeExpired = errors.New("challenge is expired or has already been used")
+
+	// ErrReservationLost 使用者名稱的保留已過期並被其他註冊接手
+	ErrReservationLost = errors.New("username reservation has been taken over")
+)
+
 // userRepositoryImpl 實作 UserRepository 介面
 type userRepositoryImpl struct{}
 
@@ -25,14 +47,21 @@ func NewUserRepository() UserRepository {
 }
 
 // CreateUser 在資料庫中建立新用戶
+// 使用者名稱違反唯一限制時回傳 ErrUsernameTaken
 func (r *userRepositoryImpl) CreateUser(user *entity.User) error {
-	return db.GetDB().Create(user).Error
+	if err := db.GetDB().Create(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrUsernameTaken
+		}
+		return err
+	}
+	return nil
 }
 
 // GetUserByID 透過 ID 取得用戶
 func (r *userRepositoryImpl) GetUserByID(id string) (*entity.User, error) {
 	var user entity.User
-	if err := db.GetDB().First(&user, id).Error; err != nil {
+	if err := db.GetDB().First(&user, "id = ?", id).Error; err != nil {
 		if err.Error() == "record not found" {
 			return nil, nil
 		}
@@ -41,10 +70,10 @@ func (r *userRepositoryImpl) GetUserByID(id string) (*entity.User, error) {
 	return &user, nil
 }
 
-// GetUserByUsername 透過使用者名稱取得用戶
-func (r *userRepositoryImpl) GetUserByUsername(username string) (*entity.User, error) {
+// GetUserByUsername 透過租戶與使用者名稱取得用戶
+func (r *userRepositoryImpl) GetUserByUsername(tenantID, username string) (*entity.User, error) {
 	var user entity.User
-	if err := db.GetDB().Where("user_name = ?", username).First(&user).Error; err != nil {
+	if err := db.GetDB().Where("tenant_id = ? AND user_name = ?", tenantID, username).First(&user).Error; err != nil {
 		if err.Error() == "record not found" {
 			return nil, nil
 		}
@@ -54,15 +83,41 @@ func (r *userRepositoryImpl) GetUserByUsername(username string) (*entity.User, e
 	return &user, nil
 }
 
-// GetUserByChallenge 透過 Challenge 取得用戶
-func (r *userRepositoryImpl) GetUserByChallenge(challenge string) (*entity.User, error) {
+// GetUserByUserHandle 透過租戶與 WebAuthn user handle (含舊版別名) 取得用戶
+func (r *userRepositoryImpl) GetUserByUserHandle(tenantID string, userHandle []byte) (*entity.User, error) {
 	var user entity.User
-	if err := db.GetDB().Where("challenge = ?", challenge).First(&user).Error; err != nil {
+	if err := db.GetDB().
+		Where("tenant_id = ? AND (user_handle = ? OR legacy_user_handle = ?)", tenantID, userHandle, userHandle).
+		First(&user).Error; err != nil {
+		if err.Error() == "record not found" {
+			return nil, nil
+		}
 		return nil, err
 	}
+
 	return &user, nil
 }
 
+// ConsumeChallenge 以單一 UPDATE 清除尚未過期的 Challenge 並回傳其使用者，確保每個 Challenge 只能使用一次
+// 找不到、已過期或已被使用過時回傳 nil
+func (r *userRepositoryImpl) ConsumeChallenge(tenantID, challenge string, now time.Time) (*entity.User, error) {
+	var users []*entity.User
+	if err := db.GetDB().Model(&users).
+		Clauses(clause.Returning{}).
+		Where("tenant_id = ? AND challenge = ? AND challenge_expires_at > ?", tenantID, challenge, now).
+		Updates(map[string]interface{}{
+			"challenge":            "",
+			"challenge_issued_at":  nil,
+			"challenge_expires_at": nil,
+		}).Error; err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return users[0], nil
+}
+
 // GetUsers 取得所有用戶
 func (r *userRepositoryImpl) GetUsers() ([]*entity.User, error) {
 	var users []*entity.User
@@ -72,11 +127,81 @@ func (r *userRepositoryImpl) GetUsers() ([]*entity.User, error) {
 	return users, nil
 }
 
+// GetFlaggedUsers 取得租戶內因 Credential 疑似被複製而被標記的用戶
+func (r *userRepositoryImpl) GetFlaggedUsers(tenantID string) ([]*entity.User, error) {
+	var users []*entity.User
+	if err := db.GetDB().Where("tenant_id = ? AND flagged = ?", tenantID, true).Order("user_name").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // UpdateUser 更新用戶資料
 func (r *userRepositoryImpl) UpdateUser(user *entity.User, updateData interface{}) error {
 	return db.GetDB().Model(user).Updates(updateData).Error
 }
 
+// ReclaimExpiredReservation 以新的 user handle 與保留期限接手已過期的使用者名稱保留
+// 只有保留期限早於 now 且尚未建立任何 Credential 時才會更新，回傳 false 代表保留仍有效或已被他人接手
+func (r *userRepositoryImpl) ReclaimExpiredReservation(user *entity.User, now time.Time) (bool, error) {
+	result := db.GetDB().Model(&entity.User{}).
+		Where(`id = ? AND reserved_until IS NOT NULL AND reserved_until < ? AND NOT EXISTS (SELECT 1 FROM credential WHERE credential.user_id = "user".id)`, user.ID, now).
+		Updates(map[string]interface{}{
+			"user_handle":          user.UserHandle,
+			"display_name":         user.DisplayName,
+			"reserved_until":       user.ReservedUntil,
+			"challenge":            "",
+			"challenge_issued_at":  nil,
+			"challenge_expires_at": nil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CompleteRegistration 在同一個資料庫交易中建立 Credential 並解除使用者名稱的保留期限
+// 鎖定使用者資料列並確認 user handle 未變更，保留已被其他註冊接手時回傳 ErrReservationLost，Credential ID 重複時回傳 ErrCredentialExists
+func (r *userRepositoryImpl) CompleteRegistration(user *entity.User, credential *entity.Credential) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&entity.User{}, "id = ? AND user_handle = ?", user.ID, user.UserHandle).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReservationLost
+			}
+			return err
+		}
+
+		if err := tx.Create(credential).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrCredentialExists
+			}
+			return err
+		}
+
+		return tx.Model(&entity.User{}).
+			Where("id = ? AND reserved_until IS NOT NULL", user.ID).
+			Update("reserved_until", nil).Error
+	})
+}
+
+// DeleteAbandonedRegistrations 刪除保留期限早於 reservedBefore 且沒有任何 Credential 的使用者，回傳刪除筆數
+func (r *userRepositoryImpl) DeleteAbandonedRegistrations(reservedBefore time.Time) (int64, error) {
+	result := db.GetDB().
+		Where(`reserved_until < ? AND NOT EXISTS (SELECT 1 FROM credential WHERE credential.user_id = "user".id)`, reservedBefore).
+		Delete(&entity.User{})
+	return result.RowsAffected, result.Error
+}
+
+// ClearExpiredChallenges 清除已過期的 Challenge，回傳清除筆數
+func (r *userRepositoryImpl) ClearExpiredChallenges(now time.Time) (int64, error) {
+	result := db.GetDB().Model(&entity.User{}).
+		Where("challenge <> '' AND (challenge_expires_at IS NULL OR challenge_expires_at < ?)", now).
+		Updates(map[string]interface{}{"challenge": "", "challenge_issued_at": nil, "challenge_expires_at": nil})
+	return result.RowsAffected, result.Error
+}
+
 // DeleteUser 刪除用戶
 func (r *userRepositoryImpl) DeleteUser(id string) error {
 	return db.GetDB().Delete(&entity.User{}, id).Error
@@ -0,0 +1,16 @@
+package router
+
+import (
+	"fido2/internal/platform/tenant"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// 初始化租戶與 WebAuthn RP 伺服器供路由使用
+	_ = os.Setenv("RP_ID", "localhost")
+	_ = os.Setenv("RP_ORIGINS", "http://localhost:3000")
+	_ = os.Setenv("SIGNING_KEY_ENCRYPTION_KEY", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+	tenant.Init()
+	os.Exit(m.Run())
+}
\ No newline at end of file
@@ -1,15 +1,24 @@
 package router
 
 import (
+	"context"
+	"errors"
+	"expvar"
 	"fido2/internal/controller"
+	"fido2/internal/platform/session"
+	"fido2/internal/platform/tenant"
 	"fido2/internal/usecase/impl"
 	"fido2/pkg/middleware"
 	"fido2/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"net/http"
 	"os"
 	"time"
 )
 
+// shutdownTimeout 關閉伺服器時等待進行中請求的最長時間
+const shutdownTimeout = 10 * time.Second
+
 // BuildRouter 回傳 *gin.Engine，測試用
 func BuildRouter() *gin.Engine {
 	logger := utils.GetLogger()
@@ -19,47 +28,126 @@ func BuildRouter() *gin.Engine {
 		mode = gin.DebugMode
 	}
 
-	authCtl := controller.NewAuthController(impl.GetUserUseCase())
+	authCtl := controller.NewAuthController(impl.GetUserUseCase(), impl.GetCredentialUseCase(), impl.GetTokenUseCase(), impl.GetOIDCUseCase(), session.GetSessionStore())
+	tokenCtl := controller.NewTokenController(impl.GetTokenUseCase())
+	adminCtl := controller.NewAdminController(impl.GetUserUseCase(), impl.GetCredentialUseCase(), impl.GetOIDCUseCase())
+	oidcCtl := controller.NewOIDCController(impl.GetOIDCUseCase())
+	credentialCtl := controller.NewCredentialController(impl.GetUserUseCase(), impl.GetCredentialUseCase(), impl.GetTokenUseCase())
+	transactionCtl := controller.NewTransactionController(impl.GetUserUseCase(), impl.GetCredentialUseCase(), impl.GetTransactionUseCase(), session.GetSessionStore())
 
 	gin.SetMode(mode)
 
 	app := gin.New()
 	app.Use(gin.Logger(), gin.Recovery())
 
+	// 依 Host header 或路徑前綴解析租戶，需在 CORS 之前執行
+	app.Use(tenant.Middleware())
+
 	// Security middlewares
 	app.Use(middleware.RateLimit(100, time.Minute))
-	app.Use(middleware.CORS())
+	app.Use(middleware.CORS(tenant.AllowOrigin))
 
 	// 受信任代理
 	if err := app.SetTrustedProxies([]string{"127.0.0.1"}); err != nil {
 		logger.Fatalf("Failed to set trusted proxies: %v", err)
 	}
 
-	att := app.Group("/attestation")
+	// 以 Host header 區分的租戶使用根路徑，以路徑前綴區分的租戶另外掛載一份相同的路由
+	registerRoutes(&app.RouterGroup, authCtl, tokenCtl, adminCtl, oidcCtl, credentialCtl, transactionCtl)
+	for _, t := range tenant.All() {
+		if t.PathPrefix != "" {
+			registerRoutes(app.Group(t.PathPrefix), authCtl, tokenCtl, adminCtl, oidcCtl, credentialCtl, transactionCtl)
+		}
+	}
+
+	return app
+}
+
+// registerRoutes 註冊 WebAuthn、Token、OIDC、Passkey 管理、交易確認、管理者與 well-known 路由
+func registerRoutes(group *gin.RouterGroup, authCtl *controller.AuthController, tokenCtl *controller.TokenController, adminCtl *controller.AdminController, oidcCtl *controller.OIDCController, credentialCtl *controller.CredentialController, transactionCtl *controller.TransactionController) {
+	att := group.Group("/attestation")
 	{
 		att.POST("/options", authCtl.StartAttestationHandler)
 		att.POST("/result", authCtl.FinishAttestationHandler)
 	}
 
-	asr := app.Group("/assertion")
+	asr := group.Group("/assertion")
 	{
 		asr.POST("/options", authCtl.StartAssertionHandler)
+		asr.GET("/options/conditional", authCtl.StartConditionalAssertionHandler)
 		asr.POST("/result", authCtl.FinishAssertionHandler)
 	}
 
-	wellknown := app.Group("/.well-known")
+	// Secure Payment Confirmation，驗證器回應同樣送到 /assertion/result
+	group.POST("/payment/assertion/options", authCtl.StartPaymentAssertionHandler)
+
+	tok := group.Group("/token")
+	{
+		tok.POST("", oidcCtl.TokenHandler)
+		tok.POST("/refresh", tokenCtl.RefreshTokenHandler)
+	}
+	group.POST("/logout", tokenCtl.LogoutHandler)
+
+	// OIDC Provider
+	group.GET("/authorize", oidcCtl.AuthorizeHandler)
+	group.GET("/userinfo", oidcCtl.UserInfoHandler)
+	group.POST("/userinfo", oidcCtl.UserInfoHandler)
+
+	// 使用者管理自己的 Passkey，需以 Access Token 驗證
+	cred := group.Group("/credentials", tokenCtl.Authenticate)
+	{
+		cred.GET("", credentialCtl.ListCredentialsHandler)
+		cred.POST("/attestation/options", authCtl.StartAddCredentialHandler)
+		cred.PATCH("/:id", credentialCtl.RenameCredentialHandler)
+		cred.DELETE("/:id", credentialCtl.DeleteCredentialHandler)
+	}
+
+	// 交易確認 (動態連結)，Challenge 由伺服器保存的交易內容產生，需以 Access Token 驗證
+	txn := group.Group("/transactions", tokenCtl.Authenticate)
+	{
+		txn.POST("", transactionCtl.CreateTransactionHandler)
+		txn.GET("", transactionCtl.ListTransactionsHandler)
+		txn.GET("/:id", transactionCtl.GetTransactionHandler)
+		txn.POST("/:id/reject", transactionCtl.RejectTransactionHandler)
+		txn.POST("/:id/assertion/options", transactionCtl.StartTransactionAssertionHandler)
+		txn.POST("/:id/assertion/result", transactionCtl.FinishTransactionAssertionHandler)
+	}
+
+	admin := group.Group("/admin", middleware.AdminAuth())
+	{
+		admin.GET("/credentials/flagged", adminCtl.FlaggedCredentialsHandler)
+		admin.GET("/credentials/:id/device", adminCtl.CredentialDeviceHandler)
+		admin.POST("/credentials/u2f", adminCtl.ImportU2FCredentialHandler)
+		admin.POST("/oidc/clients", adminCtl.RegisterClientHandler)
+		admin.GET("/metrics", gin.WrapH(expvar.Handler()))
+	}
+
+	wellknown := group.Group("/.well-known")
 	{
 		wellknown.GET("/apple-app-site-association", controller.AppleWellKnownHandler)
 		wellknown.GET("/assetlinks.json", controller.AndroidWellKnownHandler)
+		wellknown.GET("/jwks.json", tokenCtl.JWKSHandler)
+		wellknown.GET("/openid-configuration", oidcCtl.DiscoveryHandler)
 	}
-
-	return app
 }
 
-func InitRouter() {
+// InitRouter 啟動 HTTP 伺服器，ctx 結束後停止接受新連線並等待進行中的請求完成
+func InitRouter(ctx context.Context) {
 	engine := BuildRouter()
+	server := &http.Server{Addr: ":8080", Handler: engine}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			utils.GetLogger().Errorf("Server shutdown failed: %v", err)
+		}
+	}()
+
 	utils.GetLogger().Info("Server started on port 8080")
-	if err := engine.Run(":8080"); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		panic(err)
 	}
+	utils.GetLogger().Info("Server stopped")
 }
\ No newline at end of file
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"fido2/internal/entity"
+)
+
+type CredentialUseCase interface {
+	CreateCredential(credential *entity.Credential) error
+	GetCredentialByID(id string) (*entity.Credential, error)
+	GetCredentialsByUserID(userID string) ([]*entity.Credential, error)
+	GetFlaggedCredentials(tenantID string) ([]*entity.Credential, error)
+	UpdateCredential(credential *entity.Credential, updateData interface{}) error
+	RenameCredential(userID, id, nickname string) error
+	DeleteCredential(id string) error
+	DeleteUserCredential(user *entity.User, id string) error
+}
\ No newline at end of file
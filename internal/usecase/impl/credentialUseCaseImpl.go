@@ -0,0 +1,76 @@
+package impl
+
+import (
+	"fido2/internal/entity"
+	"fido2/internal/repository"
+	"fido2/internal/usecase"
+	"sync"
+)
+
+type credentialUseCaseImpl struct {
+	credentialRepo repository.CredentialRepository
+}
+
+var _ usecase.CredentialUseCase = (*credentialUseCaseImpl)(nil)
+
+var (
+	credentialUseCase usecase.CredentialUseCase
+	credentialOnce    sync.Once
+)
+
+func GetCredentialUseCase() usecase.CredentialUseCase {
+	credentialOnce.Do(func() {
+		credentialRepo := repository.NewCredentialRepository()
+		credentialUseCase = NewCredentialUseCase(credentialRepo)
+	})
+	return credentialUseCase
+}
+
+// 建構函式(Constructor)
+
+func NewCredentialUseCase(credentialRepo repository.CredentialRepository) usecase.CredentialUseCase {
+	return &credentialUseCaseImpl{
+		credentialRepo: credentialRepo,
+	}
+}
+
+func (uc *credentialUseCaseImpl) CreateCredential(credential *entity.Credential) error {
+	return uc.credentialRepo.CreateCredential(credential)
+}
+
+func (uc *credentialUseCaseImpl) GetCredentialByID(id string) (*entity.Credential, error) {
+	return uc.credentialRepo.GetCredentialByID(id)
+}
+
+func (uc *credentialUseCaseImpl) GetCredentialsByUserID(userID string) ([]*entity.Credential, error) {
+	return uc.credentialRepo.GetCredentialsByUserID(userID)
+}
+
+func (uc *credentialUseCaseImpl) GetFlaggedCredentials(tenantID string) ([]*entity.Credential, error) {
+	return uc.credentialRepo.GetFlaggedCredentials(tenantID)
+}
+
+func (uc *credentialUseCaseImpl) UpdateCredential(credential *entity.Credential, updateData interface{}) error {
+	return uc.credentialRepo.UpdateCredential(credential, updateData)
+}
+
+func (uc *credentialUseCaseImpl) DeleteCredential(id string) error {
+	return uc.credentialRepo.DeleteCredential(id)
+}
+
+// RenameCredential 更新使用者自己的 Credential 名稱
+func (uc *credentialUseCaseImpl) RenameCredential(userID, id, nickname string) error {
+	found, err := uc.credentialRepo.RenameCredential(userID, id, nickname)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return repository.ErrCredentialNotFound
+	}
+	return nil
+}
+
+// DeleteUserCredential 刪除使用者自己的 Credential，沒有備援方式時不可刪除最後一個
+func (uc *credentialUseCaseImpl) DeleteUserCredential(user *entity.User, id string) error {
+	return uc.credentialRepo.DeleteUserCredential(user.ID, id, user.RecoveryEmail != "")
+}
\ No newline at end of file
@@ -0,0 +1,279 @@
+package impl
+
+import (
+	"errors"
+	"fido2/internal/entity"
+	"fido2/internal/platform/oidc"
+	"fido2/internal/platform/token"
+	"fido2/internal/repository"
+	"fido2/internal/usecase"
+	"fido2/pkg/utils"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type oidcUseCaseImpl struct {
+	clientRepo      repository.OIDCClientRepository
+	authRequestRepo repository.AuthorizationRequestRepository
+	userRepo        repository.UserRepository
+	tokenUC         usecase.TokenUseCase
+	signer          token.Signer
+	idTokenTTL      time.Duration
+}
+
+var _ usecase.OIDCUseCase = (*oidcUseCaseImpl)(nil)
+
+var (
+	oidcUseCase usecase.OIDCUseCase
+	oidcOnce    sync.Once
+)
+
+func GetOIDCUseCase() usecase.OIDCUseCase {
+	oidcOnce.Do(func() {
+		clientRepo := repository.NewOIDCClientRepository()
+		authRequestRepo := repository.NewAuthorizationRequestRepository()
+		userRepo := repository.NewUserRepository()
+		oidcUseCase = NewOIDCUseCase(clientRepo, authRequestRepo, userRepo, GetTokenUseCase(), token.GetSigner(), token.GetAccessTokenTTL())
+	})
+	return oidcUseCase
+}
+
+// 建構函式(Constructor)
+
+func NewOIDCUseCase(clientRepo repository.OIDCClientRepository, authRequestRepo repository.AuthorizationRequestRepository, userRepo repository.UserRepository, tokenUC usecase.TokenUseCase, signer token.Signer, idTokenTTL time.Duration) usecase.OIDCUseCase {
+	return &oidcUseCaseImpl{
+		clientRepo:      clientRepo,
+		authRequestRepo: authRequestRepo,
+		userRepo:        userRepo,
+		tokenUC:         tokenUC,
+		signer:          signer,
+		idTokenTTL:      idTokenTTL,
+	}
+}
+
+// RegisterClient 註冊新的 OIDC Client，回傳的 Client Secret 只會出現這一次 (Public Client 為空)
+func (uc *oidcUseCaseImpl) RegisterClient(tenantID, name string, redirectURIs []string, public bool) (*entity.OIDCClient, string, error) {
+	if len(redirectURIs) == 0 {
+		return nil, "", errors.New("at least one redirect URI is required")
+	}
+	for _, redirectURI := range redirectURIs {
+		u, err := url.Parse(redirectURI)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Fragment != "" {
+			return nil, "", errors.New("redirect URI must be an absolute URL without fragment: " + redirectURI)
+		}
+	}
+
+	client := &entity.OIDCClient{
+		ID:           uuid.New().String(),
+		TenantID:     tenantID,
+		Name:         name,
+		Public:       public,
+		RedirectURIs: redirectURIs,
+	}
+
+	var secret string
+	if !public {
+		var err error
+		if secret, err = oidc.NewClientSecret(); err != nil {
+			return nil, "", err
+		}
+		client.SecretHash = oidc.Hash(secret)
+	}
+
+	if err := uc.clientRepo.CreateClient(client); err != nil {
+		return nil, "", err
+	}
+	return client, secret, nil
+}
+
+// StartAuthorization 驗證 /authorize 的參數並保存授權請求，回傳發起授權的 Client
+// client_id 或 redirect_uri 無效時回傳 oidc.ErrUnknownClient / oidc.ErrInvalidRedirectURI，不可導回 Client
+// 其他參數錯誤回傳 *oidc.Error，應導回 redirect_uri
+func (uc *oidcUseCaseImpl) StartAuthorization(request *entity.AuthorizationRequest, responseType string) (*entity.OIDCClient, error) {
+	client, err := uc.clientRepo.GetClientByID(request.TenantID, request.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, oidc.ErrUnknownClient
+	}
+	if !slices.Contains(client.RedirectURIs, request.RedirectURI) {
+		return nil, oidc.ErrInvalidRedirectURI
+	}
+
+	if responseType != oidc.ResponseTypeCode {
+		return nil, oidc.NewError(oidc.ErrorUnsupportedResponseType, "only response_type=code is supported")
+	}
+	if !oidc.HasScope(request.Scope, oidc.ScopeOpenID) {
+		return nil, oidc.NewError(oidc.ErrorInvalidScope, "scope must include openid")
+	}
+	if request.CodeChallenge == "" {
+		return nil, oidc.NewError(oidc.ErrorInvalidRequest, "code_challenge is required")
+	}
+	if request.CodeChallengeMethod != oidc.CodeChallengeMethodS256 {
+		return nil, oidc.NewError(oidc.ErrorInvalidRequest, "code_challenge_method must be S256")
+	}
+
+	request.ID = uuid.New().String()
+	request.ExpiresAt = time.Now().Add(oidc.AuthorizationRequestTTL)
+	if err := uc.authRequestRepo.CreateAuthorizationRequest(request); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// CompleteAuthorization Passkey 驗證成功後產生 Authorization Code，回傳導回 Client 的網址
+func (uc *oidcUseCaseImpl) CompleteAuthorization(tenantID, requestID string, user *entity.User, credentialID string, flags webauthn.CredentialFlags) (string, error) {
+	request, err := uc.authRequestRepo.GetAuthorizationRequestByID(tenantID, requestID)
+	if err != nil {
+		return "", err
+	}
+	if request == nil || request.CodeHash != "" || time.Now().After(request.ExpiresAt) {
+		return "", oidc.ErrAuthorizationRequestNotFound
+	}
+
+	code, err := oidc.NewAuthorizationCode()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	completed, err := uc.authRequestRepo.CompleteAuthorizationRequest(request.ID, &entity.AuthorizationRequest{
+		UserID:       user.ID,
+		CredentialID: credentialID,
+		AMR:          oidc.AMR(flags),
+		ACR:          token.ACR(flags.UserVerified),
+		AuthTime:     &now,
+		CodeHash:     oidc.Hash(code),
+		ExpiresAt:    now.Add(oidc.AuthorizationCodeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	if !completed {
+		return "", oidc.ErrAuthorizationRequestNotFound
+	}
+
+	return oidc.BuildRedirectURI(request.RedirectURI, url.Values{
+		"code":  {code},
+		"state": {request.State},
+	})
+}
+
+// ExchangeCode 以 Authorization Code 換發 Access Token、Refresh Token 與 ID Token
+func (uc *oidcUseCaseImpl) ExchangeCode(issuer, tenantID, clientID, clientSecret, code, redirectURI, codeVerifier string) (*oidc.Tokens, error) {
+	client, err := uc.clientRepo.GetClientByID(tenantID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || (!client.Public && !oidc.VerifyClientSecret(clientSecret, client.SecretHash)) {
+		return nil, oidc.NewError(oidc.ErrorInvalidClient, "client authentication failed")
+	}
+
+	request, err := uc.authRequestRepo.GetAuthorizationRequestByCodeHash(oidc.Hash(code))
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.TenantID != tenantID || request.ClientID != client.ID {
+		return nil, oidc.NewError(oidc.ErrorInvalidGrant, "authorization code is invalid")
+	}
+	if request.RedeemedAt != nil {
+		// RFC 6749 §4.1.2：Authorization Code 被重複使用時撤銷以此 Code 換發的 Token
+		utils.GetLogger().Warnf("Authorization code reuse detected for client %s, user %s, revoking family %s", client.ID, request.UserID, request.RefreshTokenFamilyID)
+		if request.RefreshTokenFamilyID != "" {
+			if err := uc.tokenUC.RevokeTokenFamily(request.RefreshTokenFamilyID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, oidc.NewError(oidc.ErrorInvalidGrant, "authorization code has already been used")
+	}
+	if time.Now().After(request.ExpiresAt) {
+		return nil, oidc.NewError(oidc.ErrorInvalidGrant, "authorization code has expired")
+	}
+	if request.RedirectURI != redirectURI {
+		return nil, oidc.NewError(oidc.ErrorInvalidGrant, "redirect_uri does not match the authorization request")
+	}
+	if !oidc.VerifyCodeChallenge(codeVerifier, request.CodeChallenge) {
+		return nil, oidc.NewError(oidc.ErrorInvalidGrant, "code_verifier does not match code_challenge")
+	}
+
+	// 兌換時一併記錄 Refresh Token 家族，Code 之後被重複使用時才能撤銷
+	familyID := uuid.New().String()
+	redeemed, err := uc.authRequestRepo.RedeemAuthorizationCode(request.ID, familyID)
+	if err != nil {
+		return nil, err
+	}
+	if !redeemed {
+		// 同時有其他請求兌換了同一個 Authorization Code
+		return nil, oidc.NewError(oidc.ErrorInvalidGrant, "authorization code has already been used")
+	}
+
+	user, err := uc.userRepo.GetUserByID(request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, oidc.NewError(oidc.ErrorInvalidGrant, "user no longer exists")
+	}
+
+	pair, err := uc.tokenUC.IssueTokensInFamily(issuer, familyID, request.Scope, user, request.CredentialID, request.ACR == token.ACR(true))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claims := &oidc.IDClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   user.ID,
+			Audience:  jwt.ClaimStrings{client.ID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(uc.idTokenTTL)),
+		},
+		Nonce:    request.Nonce,
+		AuthTime: request.AuthTime.Unix(),
+		AMR:      request.AMR,
+		ACR:      request.ACR,
+	}
+	if oidc.HasScope(request.Scope, oidc.ScopeProfile) {
+		claims.PreferredUsername = user.UserName
+		claims.Name = user.DisplayName
+	}
+
+	idToken, err := uc.signer.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oidc.Tokens{Pair: pair, IDToken: idToken, Scope: request.Scope}, nil
+}
+
+// GetUserInfo 驗證 Access Token 並回傳對應的使用者資料
+func (uc *oidcUseCaseImpl) GetUserInfo(tenantID, accessToken string) (*oidc.UserInfo, error) {
+	claims, err := token.ParseAccessToken(uc.signer, accessToken)
+	if err != nil || claims.TenantID != tenantID {
+		return nil, oidc.NewError(oidc.ErrorInvalidToken, "access token is invalid or expired")
+	}
+
+	user, err := uc.userRepo.GetUserByID(claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, oidc.NewError(oidc.ErrorInvalidToken, "user no longer exists")
+	}
+
+	// 與 ID Token 相同，只有取得 profile scope 的 Client 才能取得使用者名稱
+	userInfo := &oidc.UserInfo{Subject: user.ID}
+	if oidc.HasScope(claims.Scope, oidc.ScopeProfile) {
+		userInfo.PreferredUsername = user.UserName
+		userInfo.Name = user.DisplayName
+	}
+	return userInfo, nil
+}
\ No newline at end of file
@@ -0,0 +1,425 @@
+package impl
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fido2/internal/entity"
+	"fido2/internal/platform/oidc"
+	"fido2/internal/platform/token"
+	"fido2/internal/repository"
+	"fido2/internal/usecase"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testIssuer = "https://auth.example.com"
+
+type oidcTestDeps struct {
+	clientRepo      *repository.MockOIDCClientRepository
+	authRequestRepo *repository.MockAuthorizationRequestRepository
+	userRepo        *repository.MockUserRepository
+	tokenUC         *usecase.MockTokenUseCase
+	signer          token.Signer
+}
+
+func newTestOIDCUseCase(t *testing.T) (usecase.OIDCUseCase, *oidcTestDeps) {
+	deps := &oidcTestDeps{
+		clientRepo:      repository.NewMockOIDCClientRepository(t),
+		authRequestRepo: repository.NewMockAuthorizationRequestRepository(t),
+		userRepo:        repository.NewMockUserRepository(t),
+		tokenUC:         usecase.NewMockTokenUseCase(t),
+		signer:          newTestSigner(t),
+	}
+	uc := NewOIDCUseCase(deps.clientRepo, deps.authRequestRepo, deps.userRepo, deps.tokenUC, deps.signer, time.Minute)
+	return uc, deps
+}
+
+// pkcePair 產生測試用的 code_verifier 與 S256 code_challenge
+func pkcePair() (string, string) {
+	verifier := strings.Repeat("v", 43)
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func testClient(public bool) *entity.OIDCClient {
+	client := &entity.OIDCClient{
+		ID:           "client-1",
+		TenantID:     "default",
+		Name:         "Example App",
+		Public:       public,
+		RedirectURIs: []string{"https://app.example.com/callback"},
+	}
+	if !public {
+		client.SecretHash = oidc.Hash("secret")
+	}
+	return client
+}
+
+func TestRegisterClient(t *testing.T) {
+	uc, deps := newTestOIDCUseCase(t)
+
+	var stored *entity.OIDCClient
+	deps.clientRepo.EXPECT().
+		CreateClient(mock.Anything).
+		Run(func(client *entity.OIDCClient) { stored = client }).
+		Return(nil)
+
+	client, secret, err := uc.RegisterClient("default", "Example App", []string{"https://app.example.com/callback"}, false)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, client.ID)
+	assert.NotEmpty(t, secret)
+	// 資料庫只保存 Secret 的雜湊值
+	assert.Equal(t, oidc.Hash(secret), stored.SecretHash)
+
+	_, _, err = uc.RegisterClient("default", "Bad", []string{"/relative"}, true)
+	assert.Error(t, err)
+}
+
+func TestStartAuthorization(t *testing.T) {
+	_, challenge := pkcePair()
+
+	tests := []struct {
+		name         string
+		responseType string
+		modify       func(request *entity.AuthorizationRequest)
+		client       *entity.OIDCClient
+		wantErr      error
+		wantCode     string
+	}{
+		{name: "成功", responseType: "code", client: testClient(true)},
+		{name: "未知的 Client", responseType: "code", wantErr: oidc.ErrUnknownClient},
+		{
+			name:         "未註冊的 redirect_uri",
+			responseType: "code",
+			client:       testClient(true),
+			modify:       func(r *entity.AuthorizationRequest) { r.RedirectURI = "https://evil.example.com/callback" },
+			wantErr:      oidc.ErrInvalidRedirectURI,
+		},
+		{name: "不支援的 response_type", responseType: "token", client: testClient(true), wantCode: oidc.ErrorUnsupportedResponseType},
+		{
+			name:         "缺少 openid scope",
+			responseType: "code",
+			client:       testClient(true),
+			modify:       func(r *entity.AuthorizationRequest) { r.Scope = "profile" },
+			wantCode:     oidc.ErrorInvalidScope,
+		},
+		{
+			name:         "缺少 PKCE",
+			responseType: "code",
+			client:       testClient(true),
+			modify:       func(r *entity.AuthorizationRequest) { r.CodeChallenge = "" },
+			wantCode:     oidc.ErrorInvalidRequest,
+		},
+		{
+			name:         "PKCE plain",
+			responseType: "code",
+			client:       testClient(true),
+			modify:       func(r *entity.AuthorizationRequest) { r.CodeChallengeMethod = "plain" },
+			wantCode:     oidc.ErrorInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, deps := newTestOIDCUseCase(t)
+
+			request := &entity.AuthorizationRequest{
+				TenantID:            "default",
+				ClientID:            "client-1",
+				RedirectURI:         "https://app.example.com/callback",
+				Scope:               "openid profile",
+				CodeChallenge:       challenge,
+				CodeChallengeMethod: "S256",
+			}
+			if tt.modify != nil {
+				tt.modify(request)
+			}
+
+			deps.clientRepo.EXPECT().GetClientByID("default", "client-1").Return(tt.client, nil)
+			if tt.wantErr == nil && tt.wantCode == "" {
+				deps.authRequestRepo.EXPECT().CreateAuthorizationRequest(request).Return(nil)
+			}
+
+			client, err := uc.StartAuthorization(request, tt.responseType)
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantCode != "":
+				var oauthErr *oidc.Error
+				assert.True(t, errors.As(err, &oauthErr))
+				assert.Equal(t, tt.wantCode, oauthErr.Code)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, "Example App", client.Name)
+				assert.NotEmpty(t, request.ID)
+				assert.True(t, request.ExpiresAt.After(time.Now()))
+			}
+		})
+	}
+}
+
+func TestCompleteAuthorization(t *testing.T) {
+	uc, deps := newTestOIDCUseCase(t)
+	user := &entity.User{ID: "user-1", TenantID: "default"}
+
+	deps.authRequestRepo.EXPECT().
+		GetAuthorizationRequestByID("default", "request-1").
+		Return(&entity.AuthorizationRequest{
+			ID:          "request-1",
+			TenantID:    "default",
+			RedirectURI: "https://app.example.com/callback",
+			State:       "xyz",
+			ExpiresAt:   time.Now().Add(time.Minute),
+		}, nil)
+
+	var completed *entity.AuthorizationRequest
+	deps.authRequestRepo.EXPECT().
+		CompleteAuthorizationRequest("request-1", mock.Anything).
+		Run(func(id string, result *entity.AuthorizationRequest) { completed = result }).
+		Return(true, nil)
+
+	location, err := uc.CompleteAuthorization("default", "request-1", user, "Y3JlZGlk", webauthn.CredentialFlags{UserPresent: true, UserVerified: true})
+	assert.NoError(t, err)
+
+	redirect, _ := url.Parse(location)
+	code := redirect.Query().Get("code")
+	assert.Equal(t, "app.example.com", redirect.Host)
+	assert.Equal(t, "xyz", redirect.Query().Get("state"))
+	assert.Equal(t, oidc.Hash(code), completed.CodeHash)
+	assert.Equal(t, []string{"hwk", "user", "mfa"}, completed.AMR)
+	assert.Equal(t, "aal2", completed.ACR)
+	assert.Equal(t, "user-1", completed.UserID)
+}
+
+func TestCompleteAuthorization_Expired(t *testing.T) {
+	uc, deps := newTestOIDCUseCase(t)
+
+	deps.authRequestRepo.EXPECT().
+		GetAuthorizationRequestByID("default", "request-1").
+		Return(&entity.AuthorizationRequest{ID: "request-1", ExpiresAt: time.Now().Add(-time.Second)}, nil)
+
+	_, err := uc.CompleteAuthorization("default", "request-1", &entity.User{ID: "user-1"}, "Y3JlZGlk", webauthn.CredentialFlags{})
+	assert.ErrorIs(t, err, oidc.ErrAuthorizationRequestNotFound)
+}
+
+// completedRequest 已完成 Passkey 驗證、等待兌換的授權請求
+func completedRequest(challenge string) *entity.AuthorizationRequest {
+	authTime := time.Now().Add(-time.Second)
+	return &entity.AuthorizationRequest{
+		ID:                  "request-1",
+		TenantID:            "default",
+		ClientID:            "client-1",
+		RedirectURI:         "https://app.example.com/callback",
+		Scope:               "openid profile",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       challenge,
+		CodeChallengeMethod: "S256",
+		UserID:              "user-1",
+		CredentialID:        "Y3JlZGlk",
+		AMR:                 []string{"hwk", "user", "mfa"},
+		ACR:                 "aal2",
+		AuthTime:            &authTime,
+		CodeHash:            oidc.Hash("code"),
+		ExpiresAt:           time.Now().Add(time.Minute),
+	}
+}
+
+func TestExchangeCode(t *testing.T) {
+	uc, deps := newTestOIDCUseCase(t)
+	verifier, challenge := pkcePair()
+	user := &entity.User{ID: "user-1", TenantID: "default", UserName: "alice", DisplayName: "Alice"}
+
+	deps.clientRepo.EXPECT().GetClientByID("default", "client-1").Return(testClient(false), nil)
+	deps.authRequestRepo.EXPECT().GetAuthorizationRequestByCodeHash(oidc.Hash("code")).Return(completedRequest(challenge), nil)
+	// 兌換時記錄的家族 ID 即換發 Refresh Token 所使用的家族
+	var familyID string
+	deps.authRequestRepo.EXPECT().
+		RedeemAuthorizationCode("request-1", mock.AnythingOfType("string")).
+		Run(func(_ string, id string) { familyID = id }).
+		Return(true, nil)
+	deps.userRepo.EXPECT().GetUserByID("user-1").Return(user, nil)
+	deps.tokenUC.EXPECT().
+		IssueTokensInFamily(testIssuer, mock.AnythingOfType("string"), "openid profile", user, "Y3JlZGlk", true).
+		Run(func(_ string, id string, _ string, _ *entity.User, _ string, _ bool) { assert.Equal(t, familyID, id) }).
+		Return(&token.Pair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: time.Minute}, nil)
+
+	tokens, err := uc.ExchangeCode(testIssuer, "default", "client-1", "secret", "code", "https://app.example.com/callback", verifier)
+	assert.NoError(t, err)
+	assert.Equal(t, "access", tokens.AccessToken)
+	assert.Equal(t, "openid profile", tokens.Scope)
+
+	claims := &oidc.IDClaims{}
+	_, err = jwt.ParseWithClaims(tokens.IDToken, claims, deps.signer.Keyfunc, jwt.WithAudience("client-1"), jwt.WithIssuer(testIssuer))
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "n-0S6_WzA2Mj", claims.Nonce)
+	assert.Equal(t, []string{"hwk", "user", "mfa"}, claims.AMR)
+	assert.Equal(t, "aal2", claims.ACR)
+	assert.Equal(t, "alice", claims.PreferredUsername)
+	assert.NotZero(t, claims.AuthTime)
+}
+
+func TestExchangeCode_Invalid(t *testing.T) {
+	verifier, challenge := pkcePair()
+
+	tests := []struct {
+		name     string
+		secret   string
+		verifier string
+		redirect string
+		request  func() *entity.AuthorizationRequest
+		redeemed bool
+		wantCode string
+	}{
+		{name: "錯誤的 Client Secret", secret: "wrong", wantCode: oidc.ErrorInvalidClient},
+		{name: "錯誤的 code_verifier", verifier: strings.Repeat("x", 43), wantCode: oidc.ErrorInvalidGrant},
+		{name: "redirect_uri 不符", redirect: "https://app.example.com/other", wantCode: oidc.ErrorInvalidGrant},
+		{
+			name: "已兌換的 Code",
+			request: func() *entity.AuthorizationRequest {
+				r := completedRequest(challenge)
+				redeemedAt := time.Now()
+				r.RedeemedAt = &redeemedAt
+				return r
+			},
+			wantCode: oidc.ErrorInvalidGrant,
+		},
+		{
+			name: "過期的 Code",
+			request: func() *entity.AuthorizationRequest {
+				r := completedRequest(challenge)
+				r.ExpiresAt = time.Now().Add(-time.Second)
+				return r
+			},
+			wantCode: oidc.ErrorInvalidGrant,
+		},
+		{
+			name: "其他 Client 的 Code",
+			request: func() *entity.AuthorizationRequest {
+				r := completedRequest(challenge)
+				r.ClientID = "client-2"
+				return r
+			},
+			wantCode: oidc.ErrorInvalidGrant,
+		},
+		{name: "同時兌換", redeemed: true, wantCode: oidc.ErrorInvalidGrant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, deps := newTestOIDCUseCase(t)
+
+			secret, codeVerifier, redirect := "secret", verifier, "https://app.example.com/callback"
+			if tt.secret != "" {
+				secret = tt.secret
+			}
+			if tt.verifier != "" {
+				codeVerifier = tt.verifier
+			}
+			if tt.redirect != "" {
+				redirect = tt.redirect
+			}
+			request := completedRequest(challenge)
+			if tt.request != nil {
+				request = tt.request()
+			}
+
+			deps.clientRepo.EXPECT().GetClientByID("default", "client-1").Return(testClient(false), nil)
+			deps.authRequestRepo.EXPECT().GetAuthorizationRequestByCodeHash(oidc.Hash("code")).Return(request, nil).Maybe()
+			if tt.redeemed {
+				deps.authRequestRepo.EXPECT().RedeemAuthorizationCode("request-1", mock.AnythingOfType("string")).Return(false, nil)
+			}
+
+			_, err := uc.ExchangeCode(testIssuer, "default", "client-1", secret, "code", redirect, codeVerifier)
+
+			var oauthErr *oidc.Error
+			assert.True(t, errors.As(err, &oauthErr))
+			assert.Equal(t, tt.wantCode, oauthErr.Code)
+		})
+	}
+}
+
+func TestExchangeCode_ReuseRevokesTokens(t *testing.T) {
+	uc, deps := newTestOIDCUseCase(t)
+	verifier, challenge := pkcePair()
+
+	request := completedRequest(challenge)
+	redeemedAt := time.Now()
+	request.RedeemedAt = &redeemedAt
+	request.RefreshTokenFamilyID = "family-1"
+
+	deps.clientRepo.EXPECT().GetClientByID("default", "client-1").Return(testClient(false), nil)
+	deps.authRequestRepo.EXPECT().GetAuthorizationRequestByCodeHash(oidc.Hash("code")).Return(request, nil)
+	// 重複使用 Code 時撤銷第一次兌換換發的 Refresh Token 家族
+	deps.tokenUC.EXPECT().RevokeTokenFamily("family-1").Return(nil)
+
+	_, err := uc.ExchangeCode(testIssuer, "default", "client-1", "secret", "code", "https://app.example.com/callback", verifier)
+
+	var oauthErr *oidc.Error
+	assert.True(t, errors.As(err, &oauthErr))
+	assert.Equal(t, oidc.ErrorInvalidGrant, oauthErr.Code)
+}
+
+func TestGetUserInfo(t *testing.T) {
+	uc, deps := newTestOIDCUseCase(t)
+
+	accessToken, err := deps.signer.Sign(&token.AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		TenantID: "default",
+		Scope:    "openid profile",
+	})
+	assert.NoError(t, err)
+
+	deps.userRepo.EXPECT().
+		GetUserByID("user-1").
+		Return(&entity.User{ID: "user-1", UserName: "alice", DisplayName: "Alice"}, nil)
+
+	userInfo, err := uc.GetUserInfo("default", accessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userInfo.Subject)
+	assert.Equal(t, "alice", userInfo.PreferredUsername)
+	assert.Equal(t, "Alice", userInfo.Name)
+
+	// 其他租戶的 Access Token 不可使用
+	_, err = uc.GetUserInfo("brand-a", accessToken)
+	var oauthErr *oidc.Error
+	assert.True(t, errors.As(err, &oauthErr))
+	assert.Equal(t, oidc.ErrorInvalidToken, oauthErr.Code)
+}
+
+func TestGetUserInfo_OpenIDOnly(t *testing.T) {
+	uc, deps := newTestOIDCUseCase(t)
+
+	accessToken, err := deps.signer.Sign(&token.AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		TenantID: "default",
+		Scope:    "openid",
+	})
+	assert.NoError(t, err)
+
+	deps.userRepo.EXPECT().
+		GetUserByID("user-1").
+		Return(&entity.User{ID: "user-1", UserName: "alice", DisplayName: "Alice"}, nil)
+
+	// 未取得 profile scope 時只回傳 sub
+	userInfo, err := uc.GetUserInfo("default", accessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userInfo.Subject)
+	assert.Empty(t, userInfo.PreferredUsername)
+	assert.Empty(t, userInfo.Name)
+}
\ No newline at end of file
@@ -0,0 +1,195 @@
+package impl
+
+import (
+	"fido2/internal/entity"
+	"fido2/internal/platform/token"
+	"fido2/internal/repository"
+	"fido2/internal/usecase"
+	"fido2/pkg/utils"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type tokenUseCaseImpl struct {
+	refreshTokenRepo repository.RefreshTokenRepository
+	signer           token.Signer
+	accessTokenTTL   time.Duration
+	refreshTokenTTL  time.Duration
+}
+
+var _ usecase.TokenUseCase = (*tokenUseCaseImpl)(nil)
+
+var (
+	tokenUseCase usecase.TokenUseCase
+	tokenOnce    sync.Once
+)
+
+func GetTokenUseCase() usecase.TokenUseCase {
+	tokenOnce.Do(func() {
+		refreshTokenRepo := repository.NewRefreshTokenRepository()
+		tokenUseCase = NewTokenUseCase(refreshTokenRepo, token.GetSigner(), token.GetAccessTokenTTL(), token.GetRefreshTokenTTL())
+	})
+	return tokenUseCase
+}
+
+// 建構函式(Constructor)
+
+func NewTokenUseCase(refreshTokenRepo repository.RefreshTokenRepository, signer token.Signer, accessTokenTTL, refreshTokenTTL time.Duration) usecase.TokenUseCase {
+	return &tokenUseCaseImpl{
+		refreshTokenRepo: refreshTokenRepo,
+		signer:           signer,
+		accessTokenTTL:   accessTokenTTL,
+		refreshTokenTTL:  refreshTokenTTL,
+	}
+}
+
+// IssueTokens WebAuthn 登入成功後簽發 Access Token 並開始新的 Refresh Token 家族
+func (uc *tokenUseCaseImpl) IssueTokens(issuer string, user *entity.User, credentialID string, userVerified bool) (*token.Pair, error) {
+	return uc.IssueTokensInFamily(issuer, uuid.New().String(), "", user, credentialID, userVerified)
+}
+
+// IssueTokensInFamily 以指定的家族 ID 開始新的 Refresh Token 家族，讓呼叫端之後可以整批撤銷
+// scope 為 OIDC Client 取得的 scope，會記錄在 Access Token 中並於輪替時沿用
+func (uc *tokenUseCaseImpl) IssueTokensInFamily(issuer, familyID, scope string, user *entity.User, credentialID string, userVerified bool) (*token.Pair, error) {
+	record := &entity.RefreshToken{
+		FamilyID:     familyID,
+		Scope:        scope,
+		TenantID:     user.TenantID,
+		UserID:       user.ID,
+		CredentialID: credentialID,
+		ACR:          token.ACR(userVerified),
+		AuthTime:     time.Now(),
+	}
+	return uc.issue(issuer, record)
+}
+
+// RefreshTokens 以 Refresh Token 換發新的 Token，舊的 Refresh Token 會被撤銷 (輪替)
+// 已撤銷的 Refresh Token 再次被使用時視為外洩，撤銷同一家族的所有 Token
+func (uc *tokenUseCaseImpl) RefreshTokens(issuer, tenantID, refreshToken string) (*token.Pair, error) {
+	current, err := uc.refreshTokenRepo.GetRefreshTokenByHash(token.HashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.TenantID != tenantID {
+		return nil, token.ErrInvalidRefreshToken
+	}
+	if current.RevokedAt != nil {
+		return nil, uc.revokeReusedFamily(current)
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, token.ErrInvalidRefreshToken
+	}
+
+	next := &entity.RefreshToken{
+		ID:           uuid.New().String(),
+		FamilyID:     current.FamilyID,
+		TenantID:     current.TenantID,
+		UserID:       current.UserID,
+		CredentialID: current.CredentialID,
+		ACR:          current.ACR,
+		AuthTime:     current.AuthTime,
+		Scope:        current.Scope,
+	}
+
+	revoked, err := uc.refreshTokenRepo.RevokeRefreshToken(current.ID, next.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		// 同時有其他請求輪替了同一個 Refresh Token
+		return nil, uc.revokeReusedFamily(current)
+	}
+
+	return uc.issue(issuer, next)
+}
+
+// RevokeRefreshToken 登出時撤銷 Refresh Token 所屬家族的所有 Token
+func (uc *tokenUseCaseImpl) RevokeRefreshToken(tenantID, refreshToken string) error {
+	current, err := uc.refreshTokenRepo.GetRefreshTokenByHash(token.HashRefreshToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	if current == nil || current.TenantID != tenantID {
+		return token.ErrInvalidRefreshToken
+	}
+	return uc.refreshTokenRepo.RevokeRefreshTokenFamily(current.FamilyID)
+}
+
+// RevokeTokenFamily 撤銷同一家族的所有 Refresh Token
+func (uc *tokenUseCaseImpl) RevokeTokenFamily(familyID string) error {
+	return uc.refreshTokenRepo.RevokeRefreshTokenFamily(familyID)
+}
+
+// GetJWKS 取得驗證 Token 簽章用的公鑰
+func (uc *tokenUseCaseImpl) GetJWKS() (*token.JWKS, error) {
+	return uc.signer.JWKS()
+}
+
+// VerifyAccessToken 驗證 Access Token 的簽章、有效期間與所屬租戶
+func (uc *tokenUseCaseImpl) VerifyAccessToken(tenantID, accessToken string) (*token.AccessClaims, error) {
+	claims, err := token.ParseAccessToken(uc.signer, accessToken)
+	if err != nil || claims.TenantID != tenantID {
+		return nil, token.ErrInvalidAccessToken
+	}
+	return claims, nil
+}
+
+// RevokeCredentialTokens 撤銷以指定 Credential 登入所取得的 Refresh Token
+func (uc *tokenUseCaseImpl) RevokeCredentialTokens(credentialID string) error {
+	return uc.refreshTokenRepo.RevokeRefreshTokensByCredential(credentialID)
+}
+
+// revokeReusedFamily 撤銷被重複使用的 Refresh Token 家族
+func (uc *tokenUseCaseImpl) revokeReusedFamily(reused *entity.RefreshToken) error {
+	utils.GetLogger().Warnf("Refresh token reuse detected for user %s, revoking family %s", reused.UserID, reused.FamilyID)
+	if err := uc.refreshTokenRepo.RevokeRefreshTokenFamily(reused.FamilyID); err != nil {
+		return err
+	}
+	return token.ErrRefreshTokenReused
+}
+
+// issue 產生新的 Refresh Token 並簽發對應的 Access Token
+func (uc *tokenUseCaseImpl) issue(issuer string, record *entity.RefreshToken) (*token.Pair, error) {
+	refreshToken, err := token.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+	record.TokenHash = token.HashRefreshToken(refreshToken)
+	record.ExpiresAt = now.Add(uc.refreshTokenTTL)
+	if err := uc.refreshTokenRepo.CreateRefreshToken(record); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := uc.signer.Sign(&token.AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    issuer,
+			Subject:   record.UserID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(uc.accessTokenTTL)),
+		},
+		AMR:          []string{"webauthn"},
+		ACR:          record.ACR,
+		AuthTime:     record.AuthTime.Unix(),
+		CredentialID: record.CredentialID,
+		TenantID:     record.TenantID,
+		Scope:        record.Scope,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &token.Pair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    uc.accessTokenTTL,
+	}, nil
+}
\ No newline at end of file
@@ -0,0 +1,191 @@
+package impl
+
+import (
+	"errors"
+	"fido2/internal/entity"
+	"fido2/internal/platform/token"
+	"fido2/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestSigner 建立以 mock 金鑰資料表儲存金鑰的 Signer
+func newTestSigner(t *testing.T) token.Signer {
+	keyRepo := repository.NewMockSigningKeyRepository(t)
+	keyRepo.EXPECT().GetPublishedSigningKeys(mock.Anything).Return(nil, nil).Maybe()
+	keyRepo.EXPECT().RotateSigningKey(mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Maybe()
+
+	signer, err := token.NewKeyManager(keyRepo, token.KeyManagerConfig{
+		Algorithm:        token.AlgES256,
+		RotationInterval: time.Hour,
+		TokenTTL:         time.Minute,
+		EncryptionKey:    make([]byte, 32),
+	})
+	assert.NoError(t, err)
+	return signer
+}
+
+func newTestTokenUseCase(t *testing.T) (*tokenUseCaseImpl, *repository.MockRefreshTokenRepository, token.Signer) {
+	signer := newTestSigner(t)
+	repo := repository.NewMockRefreshTokenRepository(t)
+	uc := NewTokenUseCase(repo, signer, time.Minute, time.Hour).(*tokenUseCaseImpl)
+	return uc, repo, signer
+}
+
+func TestIssueTokens(t *testing.T) {
+	uc, repo, signer := newTestTokenUseCase(t)
+	user := &entity.User{ID: "user-1", TenantID: "default"}
+
+	var stored *entity.RefreshToken
+	repo.EXPECT().
+		CreateRefreshToken(mock.Anything).
+		Run(func(refreshToken *entity.RefreshToken) { stored = refreshToken }).
+		Return(nil)
+
+	pair, err := uc.IssueTokens("https://example.com", user, "Y3JlZGlk", true)
+	assert.NoError(t, err)
+
+	// 資料庫只保存雜湊值
+	assert.Equal(t, token.HashRefreshToken(pair.RefreshToken), stored.TokenHash)
+	assert.NotEmpty(t, stored.FamilyID)
+	assert.Equal(t, "user-1", stored.UserID)
+
+	claims, err := token.ParseAccessToken(signer, pair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "https://example.com", claims.Issuer)
+	assert.Equal(t, []string{"webauthn"}, claims.AMR)
+	assert.Equal(t, "aal2", claims.ACR)
+	assert.Equal(t, "Y3JlZGlk", claims.CredentialID)
+	assert.Equal(t, stored.AuthTime.Unix(), claims.AuthTime)
+}
+
+func TestRefreshTokens_Rotates(t *testing.T) {
+	uc, repo, signer := newTestTokenUseCase(t)
+	authTime := time.Now().Add(-time.Hour)
+	current := &entity.RefreshToken{
+		ID:        "rt-1",
+		FamilyID:  "family-1",
+		TenantID:  "default",
+		UserID:    "user-1",
+		ACR:       "aal1",
+		AuthTime:  authTime,
+		Scope:     "openid profile",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	repo.EXPECT().GetRefreshTokenByHash(token.HashRefreshToken("old")).Return(current, nil)
+
+	var next *entity.RefreshToken
+	repo.EXPECT().
+		CreateRefreshToken(mock.Anything).
+		Run(func(refreshToken *entity.RefreshToken) { next = refreshToken }).
+		Return(nil)
+	repo.EXPECT().
+		RevokeRefreshToken("rt-1", mock.Anything).
+		Return(true, nil)
+
+	pair, err := uc.RefreshTokens("https://example.com", "default", "old")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "old", pair.RefreshToken)
+
+	// 新 Token 沿用同一家族、原本的驗證時間與 scope
+	assert.Equal(t, "family-1", next.FamilyID)
+	assert.Equal(t, authTime, next.AuthTime)
+	assert.Equal(t, "openid profile", next.Scope)
+	assert.NotEqual(t, "rt-1", next.ID)
+
+	claims, err := token.ParseAccessToken(signer, pair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "aal1", claims.ACR)
+	assert.Equal(t, authTime.Unix(), claims.AuthTime)
+	assert.Equal(t, "openid profile", claims.Scope)
+}
+
+func TestRefreshTokens_ReuseRevokesFamily(t *testing.T) {
+	uc, repo, _ := newTestTokenUseCase(t)
+	revokedAt := time.Now()
+	current := &entity.RefreshToken{
+		ID:        "rt-1",
+		FamilyID:  "family-1",
+		TenantID:  "default",
+		ExpiresAt: time.Now().Add(time.Hour),
+		RevokedAt: &revokedAt,
+	}
+
+	repo.EXPECT().GetRefreshTokenByHash(token.HashRefreshToken("old")).Return(current, nil)
+	repo.EXPECT().RevokeRefreshTokenFamily("family-1").Return(nil)
+
+	_, err := uc.RefreshTokens("https://example.com", "default", "old")
+	assert.True(t, errors.Is(err, token.ErrRefreshTokenReused))
+}
+
+func TestRefreshTokens_ConcurrentRotation(t *testing.T) {
+	uc, repo, _ := newTestTokenUseCase(t)
+	current := &entity.RefreshToken{
+		ID:        "rt-1",
+		FamilyID:  "family-1",
+		TenantID:  "default",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	// 另一個請求已先輪替，條件更新沒有影響任何資料列
+	repo.EXPECT().GetRefreshTokenByHash(token.HashRefreshToken("old")).Return(current, nil)
+	repo.EXPECT().RevokeRefreshToken("rt-1", mock.Anything).Return(false, nil)
+	repo.EXPECT().RevokeRefreshTokenFamily("family-1").Return(nil)
+
+	_, err := uc.RefreshTokens("https://example.com", "default", "old")
+	assert.True(t, errors.Is(err, token.ErrRefreshTokenReused))
+}
+
+func TestRefreshTokens_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		current *entity.RefreshToken
+	}{
+		{name: "不存在", current: nil},
+		{name: "其他租戶", current: &entity.RefreshToken{ID: "rt-1", TenantID: "brand-a", ExpiresAt: time.Now().Add(time.Hour)}},
+		{name: "已過期", current: &entity.RefreshToken{ID: "rt-1", TenantID: "default", ExpiresAt: time.Now().Add(-time.Minute)}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			uc, repo, _ := newTestTokenUseCase(t)
+			repo.EXPECT().GetRefreshTokenByHash(token.HashRefreshToken("old")).Return(tc.current, nil)
+
+			_, err := uc.RefreshTokens("https://example.com", "default", "old")
+			assert.True(t, errors.Is(err, token.ErrInvalidRefreshToken))
+		})
+	}
+}
+
+func TestRevokeRefreshToken(t *testing.T) {
+	uc, repo, _ := newTestTokenUseCase(t)
+	current := &entity.RefreshToken{ID: "rt-1", FamilyID: "family-1", TenantID: "default"}
+
+	repo.EXPECT().GetRefreshTokenByHash(token.HashRefreshToken("refresh")).Return(current, nil)
+	repo.EXPECT().RevokeRefreshTokenFamily("family-1").Return(nil)
+
+	assert.NoError(t, uc.RevokeRefreshToken("default", "refresh"))
+}
+func TestVerifyAccessToken(t *testing.T) {
+	uc, repo, _ := newTestTokenUseCase(t)
+	repo.EXPECT().CreateRefreshToken(mock.Anything).Return(nil)
+
+	pair, err := uc.IssueTokens("https://example.com", &entity.User{ID: "user-1", TenantID: "default"}, "Y3JlZGlk", true)
+	assert.NoError(t, err)
+
+	claims, err := uc.VerifyAccessToken("default", pair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "Y3JlZGlk", claims.CredentialID)
+
+	// 其他租戶或遭竄改的 Access Token
+	_, err = uc.VerifyAccessToken("brand-a", pair.AccessToken)
+	assert.ErrorIs(t, err, token.ErrInvalidAccessToken)
+	_, err = uc.VerifyAccessToken("default", pair.AccessToken+"x")
+	assert.ErrorIs(t, err, token.ErrInvalidAccessToken)
+}
\ No newline at end of file
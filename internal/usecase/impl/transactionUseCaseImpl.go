@@ -0,0 +1,119 @@
+package impl
+
+import (
+	"fido2/internal/entity"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/internal/repository"
+	"fido2/internal/usecase"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type transactionUseCaseImpl struct {
+	transactionRepo repository.TransactionRepository
+}
+
+var _ usecase.TransactionUseCase = (*transactionUseCaseImpl)(nil)
+
+var (
+	transactionUseCase usecase.TransactionUseCase
+	transactionOnce    sync.Once
+)
+
+func GetTransactionUseCase() usecase.TransactionUseCase {
+	transactionOnce.Do(func() {
+		transactionRepo := repository.NewTransactionRepository()
+		transactionUseCase = NewTransactionUseCase(transactionRepo)
+	})
+	return transactionUseCase
+}
+
+// 建構函式(Constructor)
+
+func NewTransactionUseCase(transactionRepo repository.TransactionRepository) usecase.TransactionUseCase {
+	return &transactionUseCaseImpl{
+		transactionRepo: transactionRepo,
+	}
+}
+
+// CreateTransaction 建立等待使用者確認的交易
+func (uc *transactionUseCaseImpl) CreateTransaction(tenantID, userID, payee, amount, currency string) (*entity.Transaction, error) {
+	transaction, err := wAuth.NewTransaction(tenantID, userID, payee, amount, currency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.transactionRepo.CreateTransaction(transaction); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// GetTransaction 取得使用者的交易，超過確認期限的 pending 交易會先標記為 expired
+func (uc *transactionUseCaseImpl) GetTransaction(tenantID, userID, id string) (*entity.Transaction, error) {
+	if _, err := uc.transactionRepo.ExpireTransactions(userID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	transaction, err := uc.transactionRepo.GetTransactionByID(tenantID, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return nil, repository.ErrTransactionNotFound
+	}
+	return transaction, nil
+}
+
+// GetTransactions 依狀態查詢使用者的交易，超過確認期限的 pending 交易會先標記為 expired
+func (uc *transactionUseCaseImpl) GetTransactions(tenantID, userID string, status entity.TransactionStatus) ([]*entity.Transaction, error) {
+	if _, err := uc.transactionRepo.ExpireTransactions(userID, time.Now()); err != nil {
+		return nil, err
+	}
+	return uc.transactionRepo.GetTransactionsByUserID(tenantID, userID, status)
+}
+
+// RejectTransaction 使用者拒絕尚未確認的交易
+func (uc *transactionUseCaseImpl) RejectTransaction(tenantID, userID, id string) error {
+	rejected, err := uc.transactionRepo.RejectTransaction(tenantID, userID, id, time.Now())
+	if err != nil {
+		return err
+	}
+	if rejected {
+		return nil
+	}
+
+	// 未更新時區分交易不存在與狀態已改變
+	if _, err := uc.GetTransaction(tenantID, userID, id); err != nil {
+		return err
+	}
+	return repository.ErrTransactionNotPending
+}
+
+// ConfirmTransaction 將交易標記為已確認並保存簽章，交易已不是 pending 時回傳 ErrTransactionNotPending
+func (uc *transactionUseCaseImpl) ConfirmTransaction(transaction *entity.Transaction, record *entity.TransactionSignature) error {
+	record.ID = uuid.New().String()
+	record.TransactionID = transaction.ID
+	record.TenantID = transaction.TenantID
+	record.UserID = transaction.UserID
+	record.TransactionHash = transaction.Hash
+
+	now := time.Now()
+	confirmed, err := uc.transactionRepo.ConfirmTransaction(transaction.ID, record, now)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return repository.ErrTransactionNotPending
+	}
+
+	transaction.Status = entity.TransactionConfirmed
+	transaction.ConfirmedAt = &now
+	return nil
+}
+
+// GetTransactionSignature 取得交易的不可否認性紀錄
+func (uc *transactionUseCaseImpl) GetTransactionSignature(transactionID string) (*entity.TransactionSignature, error) {
+	return uc.transactionRepo.GetTransactionSignature(transactionID)
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+package impl
+
+import (
+	"fido2/internal/entity"
+	wAuth "fido2/internal/platform/webauthn"
+	"fido2/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransactionUseCase_CreateTransaction(t *testing.T) {
+	repo := repository.NewMockTransactionRepository(t)
+	uc := NewTransactionUseCase(repo)
+
+	repo.EXPECT().CreateTransaction(mock.AnythingOfType("*entity.Transaction")).Return(nil)
+
+	transaction, err := uc.CreateTransaction("default", "1", "Example Store", "1280.00", "TWD")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.TransactionPending, transaction.Status)
+	assert.True(t, wAuth.VerifyTransactionHash(transaction))
+
+	// 格式錯誤的交易不寫入資料庫
+	_, err = uc.CreateTransaction("default", "1", "Example Store", "-1", "TWD")
+	assert.ErrorIs(t, err, wAuth.ErrInvalidTransaction)
+}
+
+func TestTransactionUseCase_GetTransaction(t *testing.T) {
+	repo := repository.NewMockTransactionRepository(t)
+	uc := NewTransactionUseCase(repo)
+
+	// 查詢前先將逾期的 pending 交易標記為 expired
+	repo.EXPECT().ExpireTransactions("1", mock.Anything).Return(1, nil)
+	repo.EXPECT().GetTransactionByID("default", "1", "tx-1").Return(&entity.Transaction{ID: "tx-1", Status: entity.TransactionExpired}, nil).Once()
+	repo.EXPECT().GetTransactionByID("default", "1", "tx-2").Return(nil, nil).Once()
+
+	transaction, err := uc.GetTransaction("default", "1", "tx-1")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.TransactionExpired, transaction.Status)
+
+	_, err = uc.GetTransaction("default", "1", "tx-2")
+	assert.ErrorIs(t, err, repository.ErrTransactionNotFound)
+}
+
+func TestTransactionUseCase_RejectTransaction(t *testing.T) {
+	repo := repository.NewMockTransactionRepository(t)
+	uc := NewTransactionUseCase(repo)
+
+	repo.EXPECT().RejectTransaction("default", "1", "tx-1", mock.Anything).Return(true, nil).Once()
+	assert.NoError(t, uc.RejectTransaction("default", "1", "tx-1"))
+
+	// 已確認的交易不可再拒絕
+	repo.EXPECT().RejectTransaction("default", "1", "tx-2", mock.Anything).Return(false, nil).Once()
+	repo.EXPECT().ExpireTransactions("1", mock.Anything).Return(0, nil)
+	repo.EXPECT().GetTransactionByID("default", "1", "tx-2").Return(&entity.Transaction{ID: "tx-2", Status: entity.TransactionConfirmed}, nil).Once()
+	assert.ErrorIs(t, uc.RejectTransaction("default", "1", "tx-2"), repository.ErrTransactionNotPending)
+
+	// 不屬於使用者的交易
+	repo.EXPECT().RejectTransaction("default", "1", "tx-3", mock.Anything).Return(false, nil).Once()
+	repo.EXPECT().GetTransactionByID("default", "1", "tx-3").Return(nil, nil).Once()
+	assert.ErrorIs(t, uc.RejectTransaction("default", "1", "tx-3"), repository.ErrTransactionNotFound)
+}
+
+func TestTransactionUseCase_ConfirmTransaction(t *testing.T) {
+	repo := repository.NewMockTransactionRepository(t)
+	uc := NewTransactionUseCase(repo)
+
+	transaction := &entity.Transaction{ID: "tx-1", TenantID: "default", UserID: "1", Hash: "hash", Status: entity.TransactionPending}
+	record := &entity.TransactionSignature{CredentialID: "cred-1", Signature: []byte("signature")}
+
+	repo.EXPECT().
+		ConfirmTransaction("tx-1", mock.MatchedBy(func(r *entity.TransactionSignature) bool {
+			return r.ID != "" && r.TransactionID == "tx-1" && r.UserID == "1" && r.TransactionHash == "hash"
+		}), mock.Anything).
+		Return(true, nil).Once()
+
+	assert.NoError(t, uc.ConfirmTransaction(transaction, record))
+	assert.Equal(t, entity.TransactionConfirmed, transaction.Status)
+	assert.NotNil(t, transaction.ConfirmedAt)
+
+	// 同時送出的第二次確認不會成功
+	repo.EXPECT().ConfirmTransaction("tx-1", mock.Anything, mock.Anything).Return(false, nil).Once()
+	err := uc.ConfirmTransaction(&entity.Transaction{ID: "tx-1"}, &entity.TransactionSignature{})
+	assert.ErrorIs(t, err, repository.ErrTransactionNotPending)
+}
\ No newline at end of file
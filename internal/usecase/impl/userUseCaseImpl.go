@@ -2,9 +2,14 @@ package impl
 
 import (
 	"fido2/internal/entity"
+	"fido2/internal/platform/username"
+	wAuth "fido2/internal/platform/webauthn"
 	"fido2/internal/repository"
 	"fido2/internal/usecase"
 	"sync"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type userUseCaseImpl struct {
@@ -42,22 +47,131 @@ func (uc *userUseCaseImpl) GetUserByID(id string) (*entity.User, error) {
 	return uc.userRepo.GetUserByID(id)
 }
 
-func (uc *userUseCaseImpl) GetUserByUsername(username string) (*entity.User, error) {
-	return uc.userRepo.GetUserByUsername(username)
+// GetUserByUsername 以正規化後的使用者名稱查詢，無效的使用者名稱視為不存在
+func (uc *userUseCaseImpl) GetUserByUsername(tenantID, name string) (*entity.User, error) {
+	normalized, err := username.Normalize(name)
+	if err != nil {
+		return nil, nil
+	}
+	return uc.userRepo.GetUserByUsername(tenantID, normalized)
 }
 
-func (uc *userUseCaseImpl) GetUserByChallenge(challenge string) (*entity.User, error) {
-	return uc.userRepo.GetUserByChallenge(challenge)
+func (uc *userUseCaseImpl) GetUserByUserHandle(tenantID string, userHandle []byte) (*entity.User, error) {
+	return uc.userRepo.GetUserByUserHandle(tenantID, userHandle)
 }
 
 func (uc *userUseCaseImpl) GetUsers() ([]*entity.User, error) {
 	return uc.userRepo.GetUsers()
 }
 
+func (uc *userUseCaseImpl) GetFlaggedUsers(tenantID string) ([]*entity.User, error) {
+	return uc.userRepo.GetFlaggedUsers(tenantID)
+}
+
 func (uc *userUseCaseImpl) UpdateUser(user *entity.User, updateData interface{}) error {
 	return uc.userRepo.UpdateUser(user, updateData)
 }
 
+// SetChallenge 保存 WebAuthn 流程的 Challenge 與簽發時間，ttl (RP 的逾時時間) 後失效並由背景清理程序清除
+func (uc *userUseCaseImpl) SetChallenge(user *entity.User, challenge string, ttl time.Duration) error {
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(ttl)
+	if err := uc.userRepo.UpdateUser(user, map[string]interface{}{
+		"challenge":            challenge,
+		"challenge_issued_at":  issuedAt,
+		"challenge_expires_at": expiresAt,
+	}); err != nil {
+		return err
+	}
+	user.Challenge = challenge
+	user.ChallengeIssuedAt = &issuedAt
+	user.ChallengeExpiresAt = &expiresAt
+	return nil
+}
+
+// ConsumeChallenge 消耗 Challenge 並回傳其使用者，過期或已使用過的 Challenge 回傳 repository.ErrChallengeExpired
+func (uc *userUseCaseImpl) ConsumeChallenge(tenantID, challenge string) (*entity.User, error) {
+	if challenge == "" {
+		return nil, repository.ErrChallengeExpired
+	}
+
+	user, err := uc.userRepo.ConsumeChallenge(tenantID, challenge, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, repository.ErrChallengeExpired
+	}
+	return user, nil
+}
+
+// ReserveUsername 為新的註冊保留使用者名稱，ttl 內其他人無法以同名註冊，應與註冊 Challenge 的有效時間一致
+// 已註冊或保留中的使用者名稱回傳 repository.ErrUsernameTaken，保留過期的帳號會以新的 user handle 重新保留
+func (uc *userUseCaseImpl) ReserveUsername(tenantID, name, displayName string, ttl time.Duration) (*entity.User, error) {
+	normalized, err := username.Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+
+	userHandle, err := wAuth.NewUserHandle()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reservedUntil := now.Add(ttl)
+
+	existing, err := uc.userRepo.GetUserByUsername(tenantID, normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		if existing.ReservedUntil == nil || existing.ReservedUntil.After(now) {
+			return nil, repository.ErrUsernameTaken
+		}
+
+		existing.UserHandle = userHandle
+		existing.DisplayName = displayName
+		existing.ReservedUntil = &reservedUntil
+		existing.Challenge = ""
+		existing.ChallengeIssuedAt = nil
+		existing.ChallengeExpiresAt = nil
+
+		reclaimed, err := uc.userRepo.ReclaimExpiredReservation(existing, now)
+		if err != nil {
+			return nil, err
+		}
+		if !reclaimed {
+			return nil, repository.ErrUsernameTaken
+		}
+		return existing, nil
+	}
+
+	user := &entity.User{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		UserHandle:    userHandle,
+		UserName:      normalized,
+		DisplayName:   displayName,
+		ReservedUntil: &reservedUntil,
+	}
+	if err := uc.userRepo.CreateUser(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// CompleteRegistration 保存新的 Credential 並解除使用者名稱的保留期限
+// 兩者在同一個資料庫交易中完成，已建立 Credential 的使用者名稱不會因保留過期而被他人接手
+func (uc *userUseCaseImpl) CompleteRegistration(user *entity.User, credential *entity.Credential) error {
+	if err := uc.userRepo.CompleteRegistration(user, credential); err != nil {
+		return err
+	}
+	user.ReservedUntil = nil
+	return nil
+}
+
 func (uc *userUseCaseImpl) DeleteUser(id string) error {
 	return uc.userRepo.DeleteUser(id)
 }
\ No newline at end of file
@@ -0,0 +1,199 @@
+package impl
+
+import (
+	"fido2/internal/entity"
+	"fido2/internal/platform/username"
+	"fido2/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetUserByUsername_Normalized(t *testing.T) {
+	repo := repository.NewMockUserRepository(t)
+	uc := NewUserUseCase(repo)
+
+	repo.EXPECT().GetUserByUsername("default", "alice").Return(&entity.User{ID: "1"}, nil)
+
+	user, err := uc.GetUserByUsername("default", "  ＡLICE ")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", user.ID)
+
+	// 無效的使用者名稱視為不存在，不會查詢資料庫
+	user, err = uc.GetUserByUsername("default", "")
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+}
+
+func TestReserveUsername_NewUser(t *testing.T) {
+	repo := repository.NewMockUserRepository(t)
+	uc := NewUserUseCase(repo)
+
+	repo.EXPECT().GetUserByUsername("default", "alice").Return(nil, nil)
+	repo.EXPECT().CreateUser(mock.AnythingOfType("*entity.User")).Return(nil)
+
+	// 保留時間與租戶的註冊逾時一致
+	user, err := uc.ReserveUsername("default", " Alice ", "Alice", 10*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", user.UserName)
+	assert.Equal(t, "Alice", user.DisplayName)
+	assert.NotEmpty(t, user.UserHandle)
+	if assert.NotNil(t, user.ReservedUntil) {
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), *user.ReservedUntil, time.Second)
+	}
+}
+
+func TestReserveUsername_Taken(t *testing.T) {
+	future := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name     string
+		existing *entity.User
+	}{
+		{name: "已完成註冊", existing: &entity.User{ID: "1", UserName: "alice"}},
+		{name: "保留中", existing: &entity.User{ID: "1", UserName: "alice", ReservedUntil: &future}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := repository.NewMockUserRepository(t)
+			uc := NewUserUseCase(repo)
+
+			repo.EXPECT().GetUserByUsername("default", "alice").Return(tc.existing, nil)
+
+			_, err := uc.ReserveUsername("default", "alice", "Alice", 5*time.Minute)
+			assert.ErrorIs(t, err, repository.ErrUsernameTaken)
+		})
+	}
+}
+
+func TestReserveUsername_ConcurrentCreate(t *testing.T) {
+	repo := repository.NewMockUserRepository(t)
+	uc := NewUserUseCase(repo)
+
+	// 同時註冊時由唯一索引擋下
+	repo.EXPECT().GetUserByUsername("default", "alice").Return(nil, nil)
+	repo.EXPECT().CreateUser(mock.AnythingOfType("*entity.User")).Return(repository.ErrUsernameTaken)
+
+	_, err := uc.ReserveUsername("default", "alice", "Alice", 5*time.Minute)
+	assert.ErrorIs(t, err, repository.ErrUsernameTaken)
+}
+
+func TestReserveUsername_ReclaimExpired(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	existing := &entity.User{ID: "1", TenantID: "default", UserName: "alice", UserHandle: []byte("old-handle"), ReservedUntil: &past, Challenge: "old"}
+
+	repo := repository.NewMockUserRepository(t)
+	uc := NewUserUseCase(repo)
+
+	repo.EXPECT().GetUserByUsername("default", "alice").Return(existing, nil)
+	repo.EXPECT().ReclaimExpiredReservation(existing, mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	user, err := uc.ReserveUsername("default", "alice", "New Alice", 5*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", user.ID)
+	assert.Equal(t, "New Alice", user.DisplayName)
+	assert.NotEqual(t, []byte("old-handle"), user.UserHandle)
+	assert.Empty(t, user.Challenge)
+	assert.True(t, user.ReservedUntil.After(time.Now()))
+}
+
+func TestReserveUsername_ReclaimLost(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	existing := &entity.User{ID: "1", UserName: "alice", ReservedUntil: &past}
+
+	repo := repository.NewMockUserRepository(t)
+	uc := NewUserUseCase(repo)
+
+	repo.EXPECT().GetUserByUsername("default", "alice").Return(existing, nil)
+	repo.EXPECT().ReclaimExpiredReservation(existing, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	_, err := uc.ReserveUsername("default", "alice", "Alice", 5*time.Minute)
+	assert.ErrorIs(t, err, repository.ErrUsernameTaken)
+}
+
+func TestReserveUsername_ExpiredWithCredential(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	existing := &entity.User{ID: "1", UserName: "alice", UserHandle: []byte("handle"), ReservedUntil: &past}
+
+	repo := repository.NewMockUserRepository(t)
+	uc := NewUserUseCase(repo)
+
+	// 保留已過期但已建立 Credential 時，資料庫條件 (NOT EXISTS credential) 不會更新任何資料列
+	repo.EXPECT().GetUserByUsername("default", "alice").Return(existing, nil)
+	repo.EXPECT().ReclaimExpiredReservation(existing, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	user, err := uc.ReserveUsername("default", "alice", "Mallory", 5*time.Minute)
+	assert.ErrorIs(t, err, repository.ErrUsernameTaken)
+	assert.Nil(t, user)
+}
+
+func TestReserveUsername_Invalid(t *testing.T) {
+	uc := NewUserUseCase(repository.NewMockUserRepository(t))
+
+	_, err := uc.ReserveUsername("default", "  ", "", 5*time.Minute)
+	assert.ErrorIs(t, err, username.ErrInvalidUsername)
+}
+
+func TestCompleteRegistration(t *testing.T) {
+	repo := repository.NewMockUserRepository(t)
+	uc := NewUserUseCase(repo)
+
+	reservedUntil := time.Now().Add(time.Minute)
+	user := &entity.User{ID: "1", ReservedUntil: &reservedUntil}
+
+	credential := &entity.Credential{ID: "cred-1", UserID: "1"}
+
+	repo.EXPECT().CompleteRegistration(user, credential).Return(nil)
+
+	assert.NoError(t, uc.CompleteRegistration(user, credential))
+	assert.Nil(t, user.ReservedUntil)
+
+	// 保留已被其他註冊接手時不解除保留
+	lost := &entity.User{ID: "2", ReservedUntil: &reservedUntil}
+	repo.EXPECT().CompleteRegistration(lost, credential).Return(repository.ErrReservationLost)
+
+	assert.ErrorIs(t, uc.CompleteRegistration(lost, credential), repository.ErrReservationLost)
+	assert.NotNil(t, lost.ReservedUntil)
+}
+func TestSetChallenge(t *testing.T) {
+	repo := repository.NewMockUserRepository(t)
+	uc := NewUserUseCase(repo)
+
+	user := &entity.User{ID: "1"}
+	repo.EXPECT().
+		UpdateUser(user, mock.Anything).
+		Run(func(_ *entity.User, updateData interface{}) {
+			fields := updateData.(map[string]interface{})
+			assert.Equal(t, "challenge", fields["challenge"])
+			assert.WithinDuration(t, time.Now(), fields["challenge_issued_at"].(time.Time), time.Second)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), fields["challenge_expires_at"].(time.Time), time.Second)
+		}).
+		Return(nil)
+
+	assert.NoError(t, uc.SetChallenge(user, "challenge", time.Minute))
+	assert.Equal(t, "challenge", user.Challenge)
+	assert.NotNil(t, user.ChallengeExpiresAt)
+}
+func TestConsumeChallenge(t *testing.T) {
+	repo := repository.NewMockUserRepository(t)
+	uc := NewUserUseCase(repo)
+
+	repo.EXPECT().ConsumeChallenge("default", "challenge", mock.AnythingOfType("time.Time")).Return(&entity.User{ID: "1"}, nil).Once()
+
+	user, err := uc.ConsumeChallenge("default", "challenge")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", user.ID)
+
+	// 第二次使用 (或已過期) 時資料庫不會有符合的資料
+	repo.EXPECT().ConsumeChallenge("default", "challenge", mock.AnythingOfType("time.Time")).Return(nil, nil).Once()
+
+	_, err = uc.ConsumeChallenge("default", "challenge")
+	assert.ErrorIs(t, err, repository.ErrChallengeExpired)
+
+	// 空白 Challenge 不查詢資料庫
+	_, err = uc.ConsumeChallenge("default", "")
+	assert.ErrorIs(t, err, repository.ErrChallengeExpired)
+}
\ No newline at end of file
@@ -5,6 +5,8 @@
 package usecase
 
 import (
+	"time"
+
 	"fido2/internal/entity"
 
 	mock "github.com/stretchr/testify/mock"
@@ -37,6 +39,131 @@ func (_m *MockUserUseCase) EXPECT() *MockUserUseCase_Expecter {
 	return &MockUserUseCase_Expecter{mock: &_m.Mock}
 }
 
+// CompleteRegistration provides a mock function for the type MockUserUseCase
+func (_mock *MockUserUseCase) CompleteRegistration(user *entity.User, credential *entity.Credential) error {
+	ret := _mock.Called(user, credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteRegistration")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*entity.User, *entity.Credential) error); ok {
+		r0 = returnFunc(user, credential)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserUseCase_CompleteRegistration_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteRegistration'
+type MockUserUseCase_CompleteRegistration_Call struct {
+	*mock.Call
+}
+
+// CompleteRegistration is a helper method to define mock.On call
+//   - user *entity.User
+//   - credential *entity.Credential
+func (_e *MockUserUseCase_Expecter) CompleteRegistration(user interface{}, credential interface{}) *MockUserUseCase_CompleteRegistration_Call {
+	return &MockUserUseCase_CompleteRegistration_Call{Call: _e.mock.On("CompleteRegistration", user, credential)}
+}
+
+func (_c *MockUserUseCase_CompleteRegistration_Call) Run(run func(user *entity.User, credential *entity.Credential)) *MockUserUseCase_CompleteRegistration_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *entity.User
+		if args[0] != nil {
+			arg0 = args[0].(*entity.User)
+		}
+		var arg1 *entity.Credential
+		if args[1] != nil {
+			arg1 = args[1].(*entity.Credential)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserUseCase_CompleteRegistration_Call) Return(err error) *MockUserUseCase_CompleteRegistration_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserUseCase_CompleteRegistration_Call) RunAndReturn(run func(user *entity.User, credential *entity.Credential) error) *MockUserUseCase_CompleteRegistration_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConsumeChallenge provides a mock function for the type MockUserUseCase
+func (_mock *MockUserUseCase) ConsumeChallenge(tenantID string, challenge string) (*entity.User, error) {
+	ret := _mock.Called(tenantID, challenge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeChallenge")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) (*entity.User, error)); ok {
+		return returnFunc(tenantID, challenge)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string) *entity.User); ok {
+		r0 = returnFunc(tenantID, challenge)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = returnFunc(tenantID, challenge)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserUseCase_ConsumeChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConsumeChallenge'
+type MockUserUseCase_ConsumeChallenge_Call struct {
+	*mock.Call
+}
+
+// ConsumeChallenge is a helper method to define mock.On call
+//   - tenantID string
+//   - challenge string
+func (_e *MockUserUseCase_Expecter) ConsumeChallenge(tenantID interface{}, challenge interface{}) *MockUserUseCase_ConsumeChallenge_Call {
+	return &MockUserUseCase_ConsumeChallenge_Call{Call: _e.mock.On("ConsumeChallenge", tenantID, challenge)}
+}
+
+func (_c *MockUserUseCase_ConsumeChallenge_Call) Run(run func(tenantID string, challenge string)) *MockUserUseCase_ConsumeChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserUseCase_ConsumeChallenge_Call) Return(user *entity.User, err error) *MockUserUseCase_ConsumeChallenge_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserUseCase_ConsumeChallenge_Call) RunAndReturn(run func(tenantID string, challenge string) (*entity.User, error)) *MockUserUseCase_ConsumeChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateUser provides a mock function for the type MockUserUseCase
 func (_mock *MockUserUseCase) CreateUser(user *entity.User) error {
 	ret := _mock.Called(user)
@@ -139,46 +266,46 @@ func (_c *MockUserUseCase_DeleteUser_Call) RunAndReturn(run func(id string) erro
 	return _c
 }
 
-// GetUserByChallenge provides a mock function for the type MockUserUseCase
-func (_mock *MockUserUseCase) GetUserByChallenge(challenge string) (*entity.User, error) {
-	ret := _mock.Called(challenge)
+// GetFlaggedUsers provides a mock function for the type MockUserUseCase
+func (_mock *MockUserUseCase) GetFlaggedUsers(tenantID string) ([]*entity.User, error) {
+	ret := _mock.Called(tenantID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserByChallenge")
+		panic("no return value specified for GetFlaggedUsers")
 	}
 
-	var r0 *entity.User
+	var r0 []*entity.User
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (*entity.User, error)); ok {
-		return returnFunc(challenge)
+	if returnFunc, ok := ret.Get(0).(func(string) ([]*entity.User, error)); ok {
+		return returnFunc(tenantID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) *entity.User); ok {
-		r0 = returnFunc(challenge)
+	if returnFunc, ok := ret.Get(0).(func(string) []*entity.User); ok {
+		r0 = returnFunc(tenantID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.User)
+			r0 = ret.Get(0).([]*entity.User)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
-		r1 = returnFunc(challenge)
+		r1 = returnFunc(tenantID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockUserUseCase_GetUserByChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByChallenge'
-type MockUserUseCase_GetUserByChallenge_Call struct {
+// MockUserUseCase_GetFlaggedUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFlaggedUsers'
+type MockUserUseCase_GetFlaggedUsers_Call struct {
 	*mock.Call
 }
 
-// GetUserByChallenge is a helper method to define mock.On call
-//   - challenge string
-func (_e *MockUserUseCase_Expecter) GetUserByChallenge(challenge interface{}) *MockUserUseCase_GetUserByChallenge_Call {
-	return &MockUserUseCase_GetUserByChallenge_Call{Call: _e.mock.On("GetUserByChallenge", challenge)}
+// GetFlaggedUsers is a helper method to define mock.On call
+//   - tenantID string
+func (_e *MockUserUseCase_Expecter) GetFlaggedUsers(tenantID interface{}) *MockUserUseCase_GetFlaggedUsers_Call {
+	return &MockUserUseCase_GetFlaggedUsers_Call{Call: _e.mock.On("GetFlaggedUsers", tenantID)}
 }
 
-func (_c *MockUserUseCase_GetUserByChallenge_Call) Run(run func(challenge string)) *MockUserUseCase_GetUserByChallenge_Call {
+func (_c *MockUserUseCase_GetFlaggedUsers_Call) Run(run func(tenantID string)) *MockUserUseCase_GetFlaggedUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
@@ -191,12 +318,12 @@ func (_c *MockUserUseCase_GetUserByChallenge_Call) Run(run func(challenge string
 	return _c
 }
 
-func (_c *MockUserUseCase_GetUserByChallenge_Call) Return(user *entity.User, err error) *MockUserUseCase_GetUserByChallenge_Call {
-	_c.Call.Return(user, err)
+func (_c *MockUserUseCase_GetFlaggedUsers_Call) Return(users []*entity.User, err error) *MockUserUseCase_GetFlaggedUsers_Call {
+	_c.Call.Return(users, err)
 	return _c
 }
 
-func (_c *MockUserUseCase_GetUserByChallenge_Call) RunAndReturn(run func(challenge string) (*entity.User, error)) *MockUserUseCase_GetUserByChallenge_Call {
+func (_c *MockUserUseCase_GetFlaggedUsers_Call) RunAndReturn(run func(tenantID string) ([]*entity.User, error)) *MockUserUseCase_GetFlaggedUsers_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -263,9 +390,77 @@ func (_c *MockUserUseCase_GetUserByID_Call) RunAndReturn(run func(id string) (*e
 	return _c
 }
 
+// GetUserByUserHandle provides a mock function for the type MockUserUseCase
+func (_mock *MockUserUseCase) GetUserByUserHandle(tenantID string, userHandle []byte) (*entity.User, error) {
+	ret := _mock.Called(tenantID, userHandle)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByUserHandle")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, []byte) (*entity.User, error)); ok {
+		return returnFunc(tenantID, userHandle)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, []byte) *entity.User); ok {
+		r0 = returnFunc(tenantID, userHandle)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, []byte) error); ok {
+		r1 = returnFunc(tenantID, userHandle)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserUseCase_GetUserByUserHandle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByUserHandle'
+type MockUserUseCase_GetUserByUserHandle_Call struct {
+	*mock.Call
+}
+
+// GetUserByUserHandle is a helper method to define mock.On call
+//   - tenantID string
+//   - userHandle []byte
+func (_e *MockUserUseCase_Expecter) GetUserByUserHandle(tenantID interface{}, userHandle interface{}) *MockUserUseCase_GetUserByUserHandle_Call {
+	return &MockUserUseCase_GetUserByUserHandle_Call{Call: _e.mock.On("GetUserByUserHandle", tenantID, userHandle)}
+}
+
+func (_c *MockUserUseCase_GetUserByUserHandle_Call) Run(run func(tenantID string, userHandle []byte)) *MockUserUseCase_GetUserByUserHandle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []byte
+		if args[1] != nil {
+			arg1 = args[1].([]byte)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserUseCase_GetUserByUserHandle_Call) Return(user *entity.User, err error) *MockUserUseCase_GetUserByUserHandle_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserUseCase_GetUserByUserHandle_Call) RunAndReturn(run func(tenantID string, userHandle []byte) (*entity.User, error)) *MockUserUseCase_GetUserByUserHandle_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetUserByUsername provides a mock function for the type MockUserUseCase
-func (_mock *MockUserUseCase) GetUserByUsername(username string) (*entity.User, error) {
-	ret := _mock.Called(username)
+func (_mock *MockUserUseCase) GetUserByUsername(tenantID string, username string) (*entity.User, error) {
+	ret := _mock.Called(tenantID, username)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByUsername")
@@ -273,18 +468,18 @@ func (_mock *MockUserUseCase) GetUserByUsername(username string) (*entity.User,
 
 	var r0 *entity.User
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (*entity.User, error)); ok {
-		return returnFunc(username)
+	if returnFunc, ok := ret.Get(0).(func(string, string) (*entity.User, error)); ok {
+		return returnFunc(tenantID, username)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) *entity.User); ok {
-		r0 = returnFunc(username)
+	if returnFunc, ok := ret.Get(0).(func(string, string) *entity.User); ok {
+		r0 = returnFunc(tenantID, username)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.User)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
-		r1 = returnFunc(username)
+	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = returnFunc(tenantID, username)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -297,19 +492,25 @@ type MockUserUseCase_GetUserByUsername_Call struct {
 }
 
 // GetUserByUsername is a helper method to define mock.On call
+//   - tenantID string
 //   - username string
-func (_e *MockUserUseCase_Expecter) GetUserByUsername(username interface{}) *MockUserUseCase_GetUserByUsername_Call {
-	return &MockUserUseCase_GetUserByUsername_Call{Call: _e.mock.On("GetUserByUsername", username)}
+func (_e *MockUserUseCase_Expecter) GetUserByUsername(tenantID interface{}, username interface{}) *MockUserUseCase_GetUserByUsername_Call {
+	return &MockUserUseCase_GetUserByUsername_Call{Call: _e.mock.On("GetUserByUsername", tenantID, username)}
 }
 
-func (_c *MockUserUseCase_GetUserByUsername_Call) Run(run func(username string)) *MockUserUseCase_GetUserByUsername_Call {
+func (_c *MockUserUseCase_GetUserByUsername_Call) Run(run func(tenantID string, username string)) *MockUserUseCase_GetUserByUsername_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
 			arg0 = args[0].(string)
 		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -320,7 +521,7 @@ func (_c *MockUserUseCase_GetUserByUsername_Call) Return(user *entity.User, err
 	return _c
 }
 
-func (_c *MockUserUseCase_GetUserByUsername_Call) RunAndReturn(run func(username string) (*entity.User, error)) *MockUserUseCase_GetUserByUsername_Call {
+func (_c *MockUserUseCase_GetUserByUsername_Call) RunAndReturn(run func(tenantID string, username string) (*entity.User, error)) *MockUserUseCase_GetUserByUsername_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -380,17 +581,160 @@ func (_c *MockUserUseCase_GetUsers_Call) RunAndReturn(run func() ([]*entity.User
 	return _c
 }
 
+// ReserveUsername provides a mock function for the type MockUserUseCase
+func (_mock *MockUserUseCase) ReserveUsername(tenantID string, username string, displayName string, ttl time.Duration) (*entity.User, error) {
+	ret := _mock.Called(tenantID, username, displayName, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveUsername")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, string, time.Duration) (*entity.User, error)); ok {
+		return returnFunc(tenantID, username, displayName, ttl)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, string, time.Duration) *entity.User); ok {
+		r0 = returnFunc(tenantID, username, displayName, ttl)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, string, time.Duration) error); ok {
+		r1 = returnFunc(tenantID, username, displayName, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserUseCase_ReserveUsername_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReserveUsername'
+type MockUserUseCase_ReserveUsername_Call struct {
+	*mock.Call
+}
+
+// ReserveUsername is a helper method to define mock.On call
+//   - tenantID string
+//   - username string
+//   - displayName string
+//   - ttl time.Duration
+func (_e *MockUserUseCase_Expecter) ReserveUsername(tenantID interface{}, username interface{}, displayName interface{}, ttl interface{}) *MockUserUseCase_ReserveUsername_Call {
+	return &MockUserUseCase_ReserveUsername_Call{Call: _e.mock.On("ReserveUsername", tenantID, username, displayName, ttl)}
+}
+
+func (_c *MockUserUseCase_ReserveUsername_Call) Run(run func(tenantID string, username string, displayName string, ttl time.Duration)) *MockUserUseCase_ReserveUsername_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserUseCase_ReserveUsername_Call) Return(user *entity.User, err error) *MockUserUseCase_ReserveUsername_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserUseCase_ReserveUsername_Call) RunAndReturn(run func(tenantID string, username string, displayName string, ttl time.Duration) (*entity.User, error)) *MockUserUseCase_ReserveUsername_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetChallenge provides a mock function for the type MockUserUseCase
+func (_mock *MockUserUseCase) SetChallenge(user *entity.User, challenge string, ttl time.Duration) error {
+	ret := _mock.Called(user, challenge, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetChallenge")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*entity.User, string, time.Duration) error); ok {
+		r0 = returnFunc(user, challenge, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserUseCase_SetChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetChallenge'
+type MockUserUseCase_SetChallenge_Call struct {
+	*mock.Call
+}
+
+// SetChallenge is a helper method to define mock.On call
+//   - user *entity.User
+//   - challenge string
+//   - ttl time.Duration
+func (_e *MockUserUseCase_Expecter) SetChallenge(user interface{}, challenge interface{}, ttl interface{}) *MockUserUseCase_SetChallenge_Call {
+	return &MockUserUseCase_SetChallenge_Call{Call: _e.mock.On("SetChallenge", user, challenge, ttl)}
+}
+
+func (_c *MockUserUseCase_SetChallenge_Call) Run(run func(user *entity.User, challenge string, ttl time.Duration)) *MockUserUseCase_SetChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *entity.User
+		if args[0] != nil {
+			arg0 = args[0].(*entity.User)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserUseCase_SetChallenge_Call) Return(err error) *MockUserUseCase_SetChallenge_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserUseCase_SetChallenge_Call) RunAndReturn(run func(user *entity.User, challenge string, ttl time.Duration) error) *MockUserUseCase_SetChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateUser provides a mock function for the type MockUserUseCase
-func (_mock *MockUserUseCase) UpdateUser(user *entity.User) error {
-	ret := _mock.Called(user)
+func (_mock *MockUserUseCase) UpdateUser(user *entity.User, updateData interface{}) error {
+	ret := _mock.Called(user, updateData)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUser")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*entity.User) error); ok {
-		r0 = returnFunc(user)
+	if returnFunc, ok := ret.Get(0).(func(*entity.User, interface{}) error); ok {
+		r0 = returnFunc(user, updateData)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -404,18 +748,24 @@ type MockUserUseCase_UpdateUser_Call struct {
 
 // UpdateUser is a helper method to define mock.On call
 //   - user *entity.User
-func (_e *MockUserUseCase_Expecter) UpdateUser(user interface{}) *MockUserUseCase_UpdateUser_Call {
-	return &MockUserUseCase_UpdateUser_Call{Call: _e.mock.On("UpdateUser", user)}
+//   - updateData interface{}
+func (_e *MockUserUseCase_Expecter) UpdateUser(user interface{}, updateData interface{}) *MockUserUseCase_UpdateUser_Call {
+	return &MockUserUseCase_UpdateUser_Call{Call: _e.mock.On("UpdateUser", user, updateData)}
 }
 
-func (_c *MockUserUseCase_UpdateUser_Call) Run(run func(user *entity.User)) *MockUserUseCase_UpdateUser_Call {
+func (_c *MockUserUseCase_UpdateUser_Call) Run(run func(user *entity.User, updateData interface{})) *MockUserUseCase_UpdateUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 *entity.User
 		if args[0] != nil {
 			arg0 = args[0].(*entity.User)
 		}
+		var arg1 interface{}
+		if args[1] != nil {
+			arg1 = args[1].(interface{})
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -426,7 +776,7 @@ func (_c *MockUserUseCase_UpdateUser_Call) Return(err error) *MockUserUseCase_Up
 	return _c
 }
 
-func (_c *MockUserUseCase_UpdateUser_Call) RunAndReturn(run func(user *entity.User) error) *MockUserUseCase_UpdateUser_Call {
+func (_c *MockUserUseCase_UpdateUser_Call) RunAndReturn(run func(user *entity.User, updateData interface{}) error) *MockUserUseCase_UpdateUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
\ No newline at end of file
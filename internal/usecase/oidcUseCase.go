@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"fido2/internal/entity"
+	"fido2/internal/platform/oidc"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+type OIDCUseCase interface {
+	RegisterClient(tenantID, name string, redirectURIs []string, public bool) (*entity.OIDCClient, string, error)
+	StartAuthorization(request *entity.AuthorizationRequest, responseType string) (*entity.OIDCClient, error)
+	CompleteAuthorization(tenantID, requestID string, user *entity.User, credentialID string, flags webauthn.CredentialFlags) (string, error)
+	ExchangeCode(issuer, tenantID, clientID, clientSecret, code, redirectURI, codeVerifier string) (*oidc.Tokens, error)
+	GetUserInfo(tenantID, accessToken string) (*oidc.UserInfo, error)
+}
\ No newline at end of file
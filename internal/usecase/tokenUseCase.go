@@ -0,0 +1,17 @@
+package usecase
+
+import (
+	"fido2/internal/entity"
+	"fido2/internal/platform/token"
+)
+
+type TokenUseCase interface {
+	IssueTokens(issuer string, user *entity.User, credentialID string, userVerified bool) (*token.Pair, error)
+	IssueTokensInFamily(issuer, familyID, scope string, user *entity.User, credentialID string, userVerified bool) (*token.Pair, error)
+	RefreshTokens(issuer, tenantID, refreshToken string) (*token.Pair, error)
+	RevokeRefreshToken(tenantID, refreshToken string) error
+	RevokeTokenFamily(familyID string) error
+	GetJWKS() (*token.JWKS, error)
+	VerifyAccessToken(tenantID, accessToken string) (*token.AccessClaims, error)
+	RevokeCredentialTokens(credentialID string) error
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"fido2/internal/entity"
+)
+
+type TransactionUseCase interface {
+	CreateTransaction(tenantID, userID, payee, amount, currency string) (*entity.Transaction, error)
+	GetTransaction(tenantID, userID, id string) (*entity.Transaction, error)
+	GetTransactions(tenantID, userID string, status entity.TransactionStatus) ([]*entity.Transaction, error)
+	RejectTransaction(tenantID, userID, id string) error
+	ConfirmTransaction(transaction *entity.Transaction, record *entity.TransactionSignature) error
+	GetTransactionSignature(transactionID string) (*entity.TransactionSignature, error)
+}
\ No newline at end of file
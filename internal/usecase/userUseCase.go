@@ -2,14 +2,20 @@ package usecase
 
 import (
 	"fido2/internal/entity"
+	"time"
 )
 
 type UserUseCase interface {
 	CreateUser(user *entity.User) error
 	GetUserByID(id string) (*entity.User, error)
-	GetUserByUsername(username string) (*entity.User, error)
-	GetUserByChallenge(challenge string) (*entity.User, error)
+	GetUserByUsername(tenantID, username string) (*entity.User, error)
+	GetUserByUserHandle(tenantID string, userHandle []byte) (*entity.User, error)
 	GetUsers() ([]*entity.User, error)
+	GetFlaggedUsers(tenantID string) ([]*entity.User, error)
 	UpdateUser(user *entity.User, updateData interface{}) error
+	SetChallenge(user *entity.User, challenge string, ttl time.Duration) error
+	ConsumeChallenge(tenantID, challenge string) (*entity.User, error)
+	ReserveUsername(tenantID, username, displayName string, ttl time.Duration) (*entity.User, error)
+	CompleteRegistration(user *entity.User, credential *entity.Credential) error
 	DeleteUser(id string) error
 }
\ No newline at end of file
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"fido2/pkg/utils/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth protects admin endpoints with the static token in ADMIN_TOKEN.
+// If ADMIN_TOKEN is not set, every admin request is rejected.
+func AdminAuth() gin.HandlerFunc {
+	token := os.Getenv("ADMIN_TOKEN")
+
+	return func(c *gin.Context) {
+		provided := c.GetHeader("X-Admin-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(
+				http.StatusUnauthorized,
+				common.CommonResponse{
+					Status:       "failed",
+					ErrorMessage: "admin token is missing or invalid",
+				},
+			)
+			return
+		}
+		c.Next()
+	}
+}
\ No newline at end of file
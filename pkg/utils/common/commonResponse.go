@@ -3,4 +3,5 @@ package common
 type CommonResponse struct {
 	Status       string `json:"status"`
 	ErrorMessage string `json:"errorMessage"`
+	ErrorCode    string `json:"errorCode,omitzero"`
 }
\ No newline at end of file
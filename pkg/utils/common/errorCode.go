@@ -0,0 +1,70 @@
+package common
+
+// 回應中的 errorCode，讓用戶端不必解析 errorMessage 即可判斷錯誤原因
+const (
+	// ErrorCodeInvalidUsername 使用者名稱為空、過長或包含不允許的字元
+	ErrorCodeInvalidUsername = "invalid_username"
+
+	// ErrorCodeUsernameTaken 使用者名稱已被註冊或正被他人保留
+	ErrorCodeUsernameTaken = "username_taken"
+
+	// ErrorCodeChallengeExpired Challenge 或 Ceremony 已過期或已被使用過，需重新取得 Options
+	ErrorCodeChallengeExpired = "challenge_expired"
+
+	// ErrorCodeAuthenticatorNotAllowed 驗證器的 AAGUID 被租戶拒絕或不在允許清單中
+	ErrorCodeAuthenticatorNotAllowed = "authenticator_not_allowed"
+
+	// ErrorCodeCertificationRequired 驗證器未達租戶要求的 FIDO 認證等級
+	ErrorCodeCertificationRequired = "authenticator_certification_required"
+
+	// ErrorCodeAttestationTypeNotAllowed 驗證器的 Attestation 類型不被租戶接受
+	ErrorCodeAttestationTypeNotAllowed = "attestation_type_not_allowed"
+
+	// ErrorCodeBackupEligibilityNotAllowed Credential 是否可備份 (同步) 不符合租戶要求
+	ErrorCodeBackupEligibilityNotAllowed = "backup_eligibility_not_allowed"
+
+	// ErrorCodeAttachmentNotAllowed 驗證器的連接方式 (platform / cross-platform) 不被租戶接受
+	ErrorCodeAttachmentNotAllowed = "authenticator_attachment_not_allowed"
+
+	// ErrorCodeCredentialProtectionInsufficient 驗證器未回報或未達租戶要求的 credProtect 等級
+	ErrorCodeCredentialProtectionInsufficient = "credential_protection_insufficient"
+
+	// ErrorCodeMinPinLengthInsufficient 驗證器未回報或未達租戶要求的最小 PIN 長度
+	ErrorCodeMinPinLengthInsufficient = "min_pin_length_insufficient"
+
+	// ErrorCodeInvalidAttestation 不支援的 Attestation 傳遞偏好
+	ErrorCodeInvalidAttestation = "invalid_attestation_preference"
+
+	// ErrorCodeEnterpriseAttestationNotAllowed 此租戶未設定企業 Attestation
+	ErrorCodeEnterpriseAttestationNotAllowed = "enterprise_attestation_not_allowed"
+
+	// ErrorCodeEnterpriseAttestationRequired 此租戶要求企業 Attestation，但驗證器未提供或無法驗證
+	ErrorCodeEnterpriseAttestationRequired = "enterprise_attestation_required"
+
+	// ErrorCodeInvalidExtension 要求的 WebAuthn 擴充不合法或在此流程中不支援
+	ErrorCodeInvalidExtension = "invalid_extension"
+
+	// ErrorCodeInvalidU2FKey 匯入的 U2F key handle 或公鑰格式錯誤
+	ErrorCodeInvalidU2FKey = "invalid_u2f_key"
+
+	// ErrorCodeCredentialExists 相同 Credential ID 的 Credential 已存在
+	ErrorCodeCredentialExists = "credential_exists"
+
+	// ErrorCodeInvalidPaymentTransaction Secure Payment Confirmation 的交易資料不完整或格式錯誤
+	ErrorCodeInvalidPaymentTransaction = "invalid_payment_transaction"
+
+	// ErrorCodeNoPaymentCredential 使用者沒有以 payment 擴充註冊的 Credential
+	ErrorCodeNoPaymentCredential = "no_payment_credential"
+
+	// ErrorCodePaymentMismatch payment.get 的 clientDataJSON 與伺服器發出的交易不符
+	ErrorCodePaymentMismatch = "payment_data_mismatch"
+
+	// ErrorCodeInvalidTransaction 待確認交易的收款方、金額或幣別格式錯誤
+	ErrorCodeInvalidTransaction = "invalid_transaction"
+
+	// ErrorCodeTransactionNotFound 交易不存在或不屬於目前登入的使用者
+	ErrorCodeTransactionNotFound = "transaction_not_found"
+
+	// ErrorCodeTransactionNotPending 交易已確認、已拒絕或已過期
+	ErrorCodeTransactionNotPending = "transaction_not_pending"
+)
\ No newline at end of file